@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/agent"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/logging"
+)
+
+const defaultServerURL = "http://localhost:8080"
+
+func main() {
+	// Load environment variables from .env
+	if err := godotenv.Load(); err != nil {
+		slog.Info("No .env file loaded, relying on environment variables")
+	}
+	setupLogging()
+
+	paths := envList("AGENT_PATHS")
+	if len(paths) == 0 {
+		fatal("AGENT_PATHS must be set")
+	}
+	applicationID := envUUID("AGENT_APPLICATION_ID")
+	userID := envUUID("AGENT_USER_ID")
+	if applicationID == uuid.Nil || userID == uuid.Nil {
+		fatal("AGENT_APPLICATION_ID and AGENT_USER_ID must be set")
+	}
+
+	serverURL := os.Getenv("AGENT_SERVER_URL")
+	if serverURL == "" {
+		serverURL = defaultServerURL
+	}
+	hostname := os.Getenv("AGENT_HOSTNAME")
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+
+	shipper := agent.NewShipper(agent.ShipperConfig{
+		URL:           serverURL,
+		ApplicationID: applicationID,
+		UserID:        userID,
+		Hostname:      hostname,
+		Labels:        envLabels("AGENT_LABELS"),
+		Timeout:       envDuration("AGENT_REQUEST_TIMEOUT"),
+		MinBackoff:    envDuration("AGENT_MIN_BACKOFF"),
+		MaxBackoff:    envDuration("AGENT_MAX_BACKOFF"),
+		MaxRetries:    int(envFloat("AGENT_MAX_RETRIES")),
+		TLS:           clientTLSConfig(),
+	})
+
+	multiline := agent.DefaultMultiline()
+	if pattern := os.Getenv("AGENT_MULTILINE_FIRSTLINE"); pattern != "" {
+		multiline.FirstLine = envRegexp("AGENT_MULTILINE_FIRSTLINE", pattern)
+	}
+	if pattern := os.Getenv("AGENT_MULTILINE_CONTINUATION"); pattern != "" {
+		multiline.Continuation = envRegexp("AGENT_MULTILINE_CONTINUATION", pattern)
+	}
+	multiline.MaxLines = int(envFloat("AGENT_MULTILINE_MAX_LINES"))
+	multiline.MaxWait = envDuration("AGENT_MULTILINE_MAX_WAIT")
+
+	a, err := agent.New(agent.Config{
+		Paths:          paths,
+		CheckpointFile: os.Getenv("AGENT_CHECKPOINT_FILE"),
+		PollInterval:   envDuration("AGENT_POLL_INTERVAL"),
+		BatchSize:      int(envFloat("AGENT_BATCH_SIZE")),
+		BatchWait:      envDuration("AGENT_BATCH_WAIT"),
+		Multiline:      multiline,
+	}, shipper)
+	if err != nil {
+		fatal("Failed to start agent", "error", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	slog.Info("Shipping files", "paths", paths, "server", serverURL)
+	if err := a.Run(ctx); err != nil {
+		fatal("Agent stopped", "error", err)
+	}
+}
+
+// setupLogging writes the agent's logs to stderr at AGENT_LOG_LEVEL, as
+// "text" or "json" lines per AGENT_LOG_FORMAT
+func setupLogging() {
+	level := slog.LevelInfo
+	if value := os.Getenv("AGENT_LOG_LEVEL"); value != "" {
+		parsed, err := logging.ParseLevel(value)
+		if err != nil {
+			fatal("AGENT_LOG_LEVEL must be debug, info, warn or error", "value", value)
+		}
+		level = parsed
+	}
+	logger, err := logging.New(os.Stderr, os.Getenv("AGENT_LOG_FORMAT"), level)
+	if err != nil {
+		fatal("AGENT_LOG_FORMAT must be text or json", "error", err)
+	}
+	slog.SetDefault(logger)
+}
+
+// fatal logs an error and exits, like log.Fatal
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// clientTLSConfig trusts AGENT_TLS_CA_FILE for the server and presents
+// AGENT_TLS_CERT_FILE to services that require mutual TLS; nil uses the defaults
+func clientTLSConfig() *tls.Config {
+	caFile := os.Getenv("AGENT_TLS_CA_FILE")
+	certFile := os.Getenv("AGENT_TLS_CERT_FILE")
+	if caFile == "" && certFile == "" {
+		return nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			fatal("Failed to read AGENT_TLS_CA_FILE", "error", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			fatal("No certificates found in AGENT_TLS_CA_FILE", "file", caFile)
+		}
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, os.Getenv("AGENT_TLS_KEY_FILE"))
+		if err != nil {
+			fatal("Failed to load the agent client certificate", "error", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig
+}
+
+// envFloat reads a numeric environment variable, returning 0 when unset
+func envFloat(key string) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 {
+		fatal(key+" must be a non-negative number", "value", value)
+	}
+	return parsed
+}
+
+// envDuration reads a duration environment variable such as "500ms", returning 0 when unset
+func envDuration(key string) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		fatal(key+" must be a non-negative duration", "value", value)
+	}
+	return parsed
+}
+
+// envUUID reads a UUID environment variable, returning uuid.Nil when unset
+func envUUID(key string) uuid.UUID {
+	value := os.Getenv(key)
+	if value == "" {
+		return uuid.Nil
+	}
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		fatal(key+" must be a UUID", "value", value)
+	}
+	return parsed
+}
+
+// envList reads a comma-separated environment variable, returning nil when unset
+func envList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// envLabels reads comma-separated name=value pairs
+func envLabels(key string) map[string]string {
+	labels := make(map[string]string)
+	for _, pair := range envList(key) {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			fatal(key+" must contain name=value pairs", "value", pair)
+		}
+		labels[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return labels
+}
+
+func envRegexp(key, pattern string) *regexp.Regexp {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		fatal(key+" must be a regular expression", "error", err)
+	}
+	return re
+}
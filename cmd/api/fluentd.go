@@ -0,0 +1,45 @@
+package main
+
+import (
+	"crypto/tls"
+	"log/slog"
+
+	applicationLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/config"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/fluentd"
+)
+
+// startFluentdListener starts the forward protocol listener when
+// fluentd.forward_addr is set, using TLS when a certificate is configured
+func startFluentdListener(fc config.FluentdConfig, uc applicationLog.LogUsecaseInterface) *fluentd.Listener {
+	if fc.ForwardAddr == "" {
+		return nil
+	}
+
+	cfg := fluentd.Config{
+		Address: fc.ForwardAddr,
+		Fields: fluentd.FieldMapping{
+			Message:     fc.MessageFields,
+			Level:       fc.LevelFields,
+			Source:      fc.SourceFields,
+			Application: fc.ApplicationFields,
+			User:        fc.UserFields,
+		},
+		ApplicationID: fc.ApplicationID,
+		UserID:        fc.UserID,
+	}
+	if fc.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(fc.TLSCertFile, fc.TLSKeyFile)
+		if err != nil {
+			fatal("Failed to load Fluentd TLS certificate", "error", err)
+		}
+		cfg.TLS = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	}
+
+	listener := fluentd.NewListener(cfg, uc)
+	if err := listener.Listen(); err != nil {
+		fatal("Failed to start Fluentd forward listener", "error", err)
+	}
+	slog.Info("Fluentd forward listener started", "addr", listener.Addr().String())
+	return listener
+}
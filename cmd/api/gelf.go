@@ -0,0 +1,44 @@
+package main
+
+import (
+	"log/slog"
+
+	applicationLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/config"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/gelf"
+)
+
+// startGELFListeners starts the UDP and TCP GELF listeners whose addresses
+// are set. Messages are stored under gelf.application_id and gelf.user_id
+// unless they carry _application_id / _user_id fields.
+func startGELFListeners(gc config.GELFConfig, uc applicationLog.LogUsecaseInterface) []*gelf.Listener {
+	configs := []struct {
+		name    string
+		network string
+		address string
+	}{
+		{name: "UDP", network: "udp", address: gc.UDPAddr},
+		{name: "TCP", network: "tcp", address: gc.TCPAddr},
+	}
+
+	var listeners []*gelf.Listener
+	for _, c := range configs {
+		if c.address == "" {
+			continue
+		}
+
+		listener := gelf.NewListener(gelf.Config{
+			Network:       c.network,
+			Address:       c.address,
+			ApplicationID: gc.ApplicationID,
+			UserID:        gc.UserID,
+		}, uc)
+		if err := listener.Listen(); err != nil {
+			fatal("Failed to start GELF listener", "transport", c.name, "error", err)
+		}
+		slog.Info("GELF listener started", "transport", c.name, "addr", listener.Addr().String())
+		listeners = append(listeners, listener)
+	}
+
+	return listeners
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"strconv"
+
+	applicationLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/grpcserver"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// startGRPCServer serves the gRPC log service on grpc.port. TailLogs streams
+// are fed by hub, which must be registered as a publisher of the usecase.
+// With tlsConfig the service is served over TLS, and under mutual TLS calls
+// are bound to applications by identities like HTTP requests.
+func startGRPCServer(grpcPort int, uc applicationLog.LogUsecaseInterface, hub *grpcserver.Hub, tlsConfig *tls.Config, identities grpcserver.ClientIdentities) *grpc.Server {
+	port := strconv.Itoa(grpcPort)
+
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		fatal("Failed to listen for gRPC", "port", grpcPort, "error", err)
+	}
+
+	opts := grpcserver.WithClientIdentities(identities)
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpcserver.NewServer(grpcserver.NewLogServer(uc, hub), opts...)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			slog.Error("gRPC server stopped", "error", err)
+		}
+	}()
+	slog.Info("Starting gRPC server", "port", grpcPort, "tls", tlsConfig != nil)
+	return server
+}
+
+// stopGRPCServer ends tail streams, then waits for in-flight calls until the
+// shutdown deadline, after which remaining connections are closed
+func stopGRPCServer(ctx context.Context, server *grpc.Server, hub *grpcserver.Hub) {
+	hub.Close()
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		slog.Warn("gRPC server did not stop in time, closing connections")
+		server.Stop()
+	}
+}
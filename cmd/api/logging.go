@@ -0,0 +1,31 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/config"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/logging"
+)
+
+// setupLogging sends the service's own logs, including those of libraries
+// using the standard log package, to stderr in log.format. The returned
+// level can be changed on reload.
+func setupLogging(c config.LogConfig) *slog.LevelVar {
+	level := new(slog.LevelVar)
+	if l, err := logging.ParseLevel(c.Level); err == nil {
+		level.Set(l)
+	}
+	logger, err := logging.New(os.Stderr, c.Format, level)
+	if err != nil {
+		fatal("Invalid log configuration", "error", err)
+	}
+	slog.SetDefault(logger)
+	return level
+}
+
+// fatal logs an error and exits, like log.Fatal
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
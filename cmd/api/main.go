@@ -7,63 +7,309 @@
 package main
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"io/fs"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	applicationLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/parser"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/pipeline"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/config"
 	domainLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
 	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/db/mongodb"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/grpcserver"
 	httpRoutes "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http"
+	httpControllersElasticsearch "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/elasticsearch"
+	httpControllersHealth "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/health"
 	httpControllersLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/log"
+	httpControllersLoki "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/loki"
+	httpControllersOTLP "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/otlp"
+	httpControllersPipeline "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/pipeline"
+	httpControllersRateLimit "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/ratelimit"
+	httpControllersReload "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/reload"
+	httpControllersText "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/text"
 	sse "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/sse"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/metrics"
 	repoLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/repository/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/spool"
+	"github.com/rubensantoniorosa2704/LoggingSSE/web"
 )
 
-const defaultPort = "8080"
-
 func main() {
 	// Load environment variables from .env
 	if err := godotenv.Load(); err != nil {
-		log.Println("Error loading .env file, relying on environment variables.")
+		slog.Info("No .env file loaded, relying on environment variables")
 	}
 
-	mongoURI := os.Getenv("MONGO_URI")
-	dbName := os.Getenv("MONGO_DB_NAME")
-
-	if mongoURI == "" || dbName == "" {
-		log.Fatal("MONGO_URI and MONGO_DB_NAME must be set.")
+	// Defaults, then CONFIG_FILE or -config, then environment variables, then flags
+	cfg, err := config.Load(os.Args[0], os.Args[1:], os.Stderr, os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fatal("Invalid configuration", "error", err)
 	}
+	logLevel := setupLogging(cfg.Log)
+	slog.Info("Effective configuration", "config", cfg.Redacted())
+
+	// Deferred calls run in reverse order on shutdown: gRPC and the listeners
+	// stop, the ingestion queue drains, the spool closes and MongoDB disconnects last.
+	// All of them share one deadline, started when the signal arrives.
+	shutdown := newShutdownDeadline(cfg.Server.ShutdownTimeout)
+	defer shutdown.Release()
+	dbName := cfg.Mongo.Database
 
 	// Connect to MongoDB
-	mongoClient, err := mongodb.Connect(mongoURI)
+	mongoClient, err := mongodb.Connect(cfg.Mongo.URI, mongodb.Options{
+		ConnectTimeout:         cfg.Mongo.ConnectTimeout,
+		ServerSelectionTimeout: cfg.Mongo.ServerSelectionTimeout,
+		MaxPoolSize:            uint64(cfg.Mongo.MaxPoolSize),
+		MinPoolSize:            uint64(cfg.Mongo.MinPoolSize),
+	})
 	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
+		fatal("Failed to connect to MongoDB", "error", err)
 	}
 	defer mongodb.Disconnect(mongoClient)
 
-	fmt.Println("MongoDB connection successful.")
+	// Prometheus metrics, labelled individually for up to metrics.max_applications applications
+	serviceMetrics := metrics.New(metrics.Config{
+		MaxApplications: cfg.Metrics.MaxApplications,
+	})
+
 	// Initialize repository, usecase, and controller with dependency injection
-	var logRepo domainLog.LogRepository = repoLog.NewLogRepository(mongoClient, dbName)
-	sseServer := sse.NewServer()
-	logUsecase := applicationLog.NewLogUsecase(logRepo, sseServer)
+	mongoLogRepo := repoLog.NewLogRepository(mongoClient, dbName)
+	indexCtx, cancelIndex := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := mongoLogRepo.EnsureIndexes(indexCtx); err != nil {
+		fatal("Failed to prepare log indexes", "error", err)
+	}
+	cancelIndex()
+	// Measured below the spool so that failed MongoDB inserts are counted even when spooled
+	var logRepo domainLog.LogRepository = metrics.NewRepository(mongoLogRepo, serviceMetrics)
+
+	// Components reported by the /readyz probe
+	readinessChecks := map[string]httpControllersHealth.Check{
+		"mongodb": httpControllersHealth.PingCheck(func(ctx context.Context) error {
+			return mongodb.Ping(ctx, mongoClient)
+		}),
+	}
+
+	// Optional disk spool: failed inserts are kept locally and replayed into MongoDB
+	if spoolDir := cfg.Spool.Dir; spoolDir != "" {
+		logSpool, err := spool.Open(spoolDir, spool.Config{
+			MaxBytes:     cfg.Spool.MaxBytes,
+			SegmentBytes: cfg.Spool.SegmentBytes,
+		})
+		if err != nil {
+			fatal("Failed to open spool", "error", err)
+		}
+		spoolingRepo := spool.NewRepository(logRepo, logSpool, cfg.Spool.ReplayInterval)
+		defer spoolingRepo.Close()
+		logRepo = spoolingRepo
+		readinessChecks["spool"] = httpControllersHealth.SpoolCheck(spoolingRepo)
+		// Writes are spooled while MongoDB is unreachable, so the instance stays in rotation
+		readinessChecks["mongodb"] = httpControllersHealth.DegradeOnFailure(readinessChecks["mongodb"], "writes are being spooled")
+		slog.Info("Spooling failed inserts to disk", "dir", spoolDir)
+	}
+
+	sseServer := sse.NewServer(
+		sse.WithMetrics(serviceMetrics.Transport("sse")),
+		sse.WithLimits(sseLimits(cfg.SSE)),
+	)
+	readinessChecks["sse"] = httpControllersHealth.ClosedCheck(sseServer)
+
+	// Default ingestion limits; per-application overrides are set at runtime
+	limiter := ratelimit.NewLimiter(rateLimitPolicy(cfg.RateLimit))
+
+	// Idempotency keys are remembered for a window and expired by a TTL index
+	idempotencyRepo := repoLog.NewIdempotencyRepository(mongoClient, dbName, cfg.Idempotency.Window)
+	indexCtx, cancelIndex = context.WithTimeout(context.Background(), 10*time.Second)
+	if err := idempotencyRepo.EnsureIndexes(indexCtx); err != nil {
+		fatal("Failed to prepare idempotency keys", "error", err)
+	}
+	cancelIndex()
+
+	usecaseOptions := []applicationLog.Option{
+		applicationLog.WithMetrics(serviceMetrics),
+		applicationLog.WithRateLimiter(limiter),
+		applicationLog.WithIdempotencyStore(idempotencyRepo),
+		applicationLog.WithTimestampPolicy(timestampPolicy(cfg.Timestamps)),
+	}
+	if orderBy := domainLog.TimeField(cfg.SSE.OrderBy); orderBy != "" {
+		usecaseOptions = append(usecaseOptions, applicationLog.WithPublishOrder(orderBy))
+	}
+
+	// Optional write-behind ingestion: logs are queued and bulk inserted
+	var ingestPipeline *pipeline.Pipeline
+	if cfg.Ingest.Mode == "async" {
+		ingestPipeline = pipeline.New(logRepo, pipeline.Config{
+			QueueSize:     cfg.Ingest.QueueSize,
+			BatchSize:     cfg.Ingest.BatchSize,
+			FlushInterval: cfg.Ingest.FlushInterval,
+			Workers:       cfg.Ingest.Workers,
+		})
+		defer func() {
+			if err := ingestPipeline.Close(shutdown.Context()); err != nil {
+				slog.Error("Ingestion pipeline did not drain", "error", err)
+			}
+		}()
+		usecaseOptions = append(usecaseOptions, applicationLog.WithQueue(ingestPipeline))
+		readinessChecks["queue"] = httpControllersHealth.QueueCheck(ingestPipeline)
+		slog.Info("Asynchronous ingestion pipeline enabled")
+	}
+
+	// gRPC tail streams receive persisted logs alongside SSE clients
+	grpcHub := grpcserver.NewHub(grpcserver.WithHubMetrics(serviceMetrics.Transport("grpc")))
+	usecaseOptions = append(usecaseOptions, applicationLog.WithPublisher(grpcHub))
+
+	logUsecase := applicationLog.NewLogUsecase(logRepo, sseServer, usecaseOptions...)
+
+	// Optional syslog, GELF and Fluentd listeners for shippers that cannot call the HTTP API
+	for _, listener := range startSyslogListeners(cfg.Syslog, logUsecase) {
+		defer listener.Close()
+	}
+	for _, listener := range startGELFListeners(cfg.GELF, logUsecase) {
+		defer listener.Close()
+	}
+	if listener := startFluentdListener(cfg.Fluentd, logUsecase); listener != nil {
+		defer listener.Close()
+	}
+
+	// Default parser for text lines; per-application parsers are set at runtime
+	parsers, err := parser.NewRegistry(parser.Config{
+		Format:       cfg.Text.Format,
+		Pattern:      cfg.Text.Pattern,
+		DefaultLevel: cfg.Text.DefaultLevel,
+	})
+	if err != nil {
+		fatal("Invalid text parser configuration", "error", err)
+	}
+
+	// Rate limits, SSE limits, timestamp bounds, CORS origins, client
+	// certificate bindings and the log level are reloaded on SIGHUP, from the
+	// API, or when the configuration file changes
+	origins := httpRoutes.NewOrigins(cfg.CORS.AllowedOrigins, cfg.CORS.Applications)
+	identities := httpRoutes.NewClientIdentities(cfg.TLS.ClientApplications, cfg.TLS.CertFile != "" && cfg.TLS.ClientCAFile != "")
+	reloader := newReloader(cfg, reloadable{
+		limiter:    limiter,
+		sseServer:  sseServer,
+		usecase:    logUsecase,
+		origins:    origins,
+		identities: identities,
+		logLevel:   logLevel,
+	}, config.WithReloadMetrics(serviceMetrics))
 
 	// Register routes and start server
 	routerConfig := httpRoutes.RouterConfig{
-		LogController: httpControllersLog.NewLogController(logUsecase),
-		SSEServer:     sseServer,
+		LogController:       httpControllersLog.NewLogController(logUsecase),
+		RateLimitController: httpControllersRateLimit.NewRateLimitController(limiter),
+		OTLPController:      httpControllersOTLP.NewOTLPController(logUsecase),
+		TextController:      httpControllersText.NewTextController(logUsecase, parsers),
+		LokiController: httpControllersLoki.NewLokiController(logUsecase, httpControllersLoki.Config{
+			ApplicationLabel: cfg.Loki.ApplicationLabel,
+			ApplicationID:    cfg.Loki.ApplicationID,
+			UserID:           cfg.Loki.UserID,
+		}),
+		ElasticsearchController: httpControllersElasticsearch.NewElasticsearchController(logUsecase, httpControllersElasticsearch.Config{
+			Fields: httpControllersElasticsearch.FieldMapping{
+				Message:     cfg.Elasticsearch.MessageFields,
+				Level:       cfg.Elasticsearch.LevelFields,
+				Timestamp:   cfg.Elasticsearch.TimestampFields,
+				Source:      cfg.Elasticsearch.SourceFields,
+				Application: cfg.Elasticsearch.ApplicationFields,
+				User:        cfg.Elasticsearch.UserFields,
+			},
+			ApplicationID: cfg.Elasticsearch.ApplicationID,
+			UserID:        cfg.Elasticsearch.UserID,
+		}),
+		Dashboard:        dashboardFiles(cfg.Server.WebDir),
+		Origins:          origins,
+		ClientIdentities: identities,
+		AdminToken:       cfg.Server.AdminToken,
+		ReloadController: httpControllersReload.NewReloadController(reloader),
+		HealthController: httpControllersHealth.NewHealthController(readinessChecks),
+		Metrics:          serviceMetrics,
+		SSEServer:        sseServer,
+	}
+	if ingestPipeline != nil {
+		routerConfig.PipelineController = httpControllersPipeline.NewPipelineController(ingestPipeline)
 	}
 	router := httpRoutes.RegisterRoutes(routerConfig)
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = defaultPort
+	port := strconv.Itoa(cfg.Server.Port)
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           router,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		// TLS handshake and connection errors
+		ErrorLog: slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
 	}
 
-	fmt.Printf("Starting server on port %s...\n", port)
-	if err := http.ListenAndServe(":"+port, router); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	// Native HTTPS, optionally verifying client certificates for mutual TLS
+	tlsConfig, certs, err := serverTLSConfig(cfg.TLS)
+	if err != nil {
+		fatal("Invalid TLS configuration", "error", err)
+	}
+	server.TLSConfig = tlsConfig
+
+	// gRPC API on its own port, sharing the usecase, TLS configuration and
+	// client certificate bindings with the HTTP API
+	grpcServer := startGRPCServer(cfg.GRPC.Port, logUsecase, grpcHub, tlsConfig, identities)
+	defer func() { stopGRPCServer(shutdown.Context(), grpcServer, grpcHub) }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var onHangup []func()
+	if certs != nil {
+		go certs.Watch(ctx, cfg.TLS.ReloadInterval)
+		onHangup = append(onHangup, certs.ReloadNow)
+	}
+	watchReloads(ctx, reloader, cfg, onHangup...)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if tlsConfig != nil {
+			serverErr <- server.ListenAndServeTLS("", "")
+			return
+		}
+		serverErr <- server.ListenAndServe()
+	}()
+	slog.Info("Starting HTTP server", "port", cfg.Server.Port, "tls", tlsConfig != nil)
+
+	select {
+	case err := <-serverErr:
+		fatal("Server failed to start", "error", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	slog.Info("Shutting down, draining in-flight requests")
+	shutdownCtx := shutdown.Context()
+	// Open event streams never become idle, so once the listener is closed
+	// clients are told to reconnect elsewhere and their streams are ended
+	server.RegisterOnShutdown(func() { sseServer.Shutdown(shutdownCtx) })
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server did not drain in time", "error", err)
+		server.Close()
+	}
+}
+
+// dashboardFiles returns the embedded dashboard, or the files in dir when
+// set so that the dashboard can be changed without rebuilding
+func dashboardFiles(dir string) fs.FS {
+	if dir != "" {
+		return os.DirFS(dir)
 	}
+	return web.Dashboard()
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	applicationLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/config"
+	domainLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+	httpRoutes "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/sse"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/logging"
+)
+
+// reloadable lists the components whose settings change without a restart
+type reloadable struct {
+	limiter    *ratelimit.Limiter
+	sseServer  *sse.Server
+	usecase    *applicationLog.LogUsecase
+	origins    *httpRoutes.Origins
+	identities *httpRoutes.ClientIdentities
+	logLevel   *slog.LevelVar
+}
+
+// newReloader loads the configuration from the same sources as at startup
+// and applies rate limits, SSE limits, timestamp bounds, CORS origins,
+// client certificate bindings and the log level
+func newReloader(cfg *config.Config, c reloadable, opts ...config.ReloaderOption) *config.Reloader {
+	reloader := config.NewReloader(cfg, func() (*config.Config, error) {
+		return config.Load(os.Args[0], os.Args[1:], io.Discard, os.Getenv)
+	}, append(opts, config.WithEnv(os.Getenv))...)
+
+	reloader.OnReload(func(cfg *config.Config) error {
+		return c.limiter.SetDefault(rateLimitPolicy(cfg.RateLimit))
+	})
+	reloader.OnReload(func(cfg *config.Config) error {
+		c.sseServer.SetLimits(sseLimits(cfg.SSE))
+		return nil
+	})
+	reloader.OnReload(func(cfg *config.Config) error {
+		c.usecase.SetTimestampPolicy(timestampPolicy(cfg.Timestamps))
+		return nil
+	})
+	reloader.OnReload(func(cfg *config.Config) error {
+		c.origins.Set(cfg.CORS.AllowedOrigins, cfg.CORS.Applications)
+		return nil
+	})
+	reloader.OnReload(func(cfg *config.Config) error {
+		c.identities.Set(cfg.TLS.ClientApplications)
+		return nil
+	})
+	reloader.OnReload(func(cfg *config.Config) error {
+		level, err := logging.ParseLevel(cfg.Log.Level)
+		if err != nil {
+			return err
+		}
+		c.logLevel.Set(level)
+		return nil
+	})
+	return reloader
+}
+
+// watchReloads reloads on SIGHUP, and when the configuration file changes if
+// reload.watch_interval is set, until ctx is done. onHangup runs on SIGHUP as
+// well, for resources outside the configuration such as certificates.
+func watchReloads(ctx context.Context, reloader *config.Reloader, cfg *config.Config, onHangup ...func()) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hangup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangup:
+				reloader.Reload(config.TriggerSignal)
+				for _, fn := range onHangup {
+					fn()
+				}
+			}
+		}
+	}()
+
+	if cfg.File != "" && cfg.Reload.WatchInterval > 0 {
+		go reloader.Watch(ctx, cfg.Reload.WatchInterval)
+		slog.Info("Watching the configuration file for changes", "file", cfg.File, "interval", cfg.Reload.WatchInterval)
+	}
+}
+
+func rateLimitPolicy(c config.RateLimitConfig) ratelimit.Policy {
+	return ratelimit.Policy{
+		EntriesPerSecond: c.EntriesPerSecond,
+		EntriesBurst:     c.EntriesBurst,
+		BytesPerSecond:   c.BytesPerSecond,
+		BytesBurst:       c.BytesBurst,
+		DailyQuota:       c.DailyQuota,
+	}
+}
+
+func sseLimits(c config.SSEConfig) sse.Limits {
+	return sse.Limits{
+		MaxClients:          c.MaxClients,
+		MaxClientsPerStream: c.MaxClientsPerApplication,
+		BufferSize:          c.BufferSize,
+	}
+}
+
+func timestampPolicy(c config.TimestampConfig) domainLog.TimestampPolicy {
+	return domainLog.TimestampPolicy{
+		MaxPast:   c.MaxPast,
+		MaxFuture: c.MaxFuture,
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// shutdownDeadline gives every shutdown phase the same deadline, so that
+// draining HTTP, gRPC and the ingestion queue together takes at most
+// server.shutdown_timeout rather than that long per phase
+type shutdownDeadline struct {
+	timeout time.Duration
+
+	once   sync.Once
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newShutdownDeadline(timeout time.Duration) *shutdownDeadline {
+	return &shutdownDeadline{timeout: timeout}
+}
+
+// Context starts the deadline on first use and returns it to every later phase
+func (d *shutdownDeadline) Context() context.Context {
+	d.once.Do(func() {
+		d.ctx, d.cancel = context.WithTimeout(context.Background(), d.timeout)
+	})
+	return d.ctx
+}
+
+// Release frees the deadline's timer once shutdown has finished
+func (d *shutdownDeadline) Release() {
+	d.Context()
+	d.cancel()
+}
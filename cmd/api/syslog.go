@@ -0,0 +1,62 @@
+package main
+
+import (
+	"crypto/tls"
+	"log/slog"
+
+	"github.com/google/uuid"
+	applicationLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/config"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/syslog"
+)
+
+// startSyslogListeners starts the UDP, TCP and TCP+TLS syslog listeners whose
+// addresses are set. Each listener routes to its own application ID, or
+// syslog.application_id, unless the sender is listed in syslog.senders.
+func startSyslogListeners(c config.SyslogConfig, uc applicationLog.LogUsecaseInterface) []*syslog.Listener {
+	configs := []struct {
+		name          string
+		network       string
+		address       string
+		applicationID uuid.UUID
+		tls           bool
+	}{
+		{name: "UDP", network: "udp", address: c.UDPAddr, applicationID: c.UDPApplicationID},
+		{name: "TCP", network: "tcp", address: c.TCPAddr, applicationID: c.TCPApplicationID},
+		{name: "TLS", network: "tcp", address: c.TLSAddr, applicationID: c.TLSApplicationID, tls: true},
+	}
+
+	var listeners []*syslog.Listener
+	for _, l := range configs {
+		if l.address == "" {
+			continue
+		}
+
+		cfg := syslog.Config{
+			Network:       l.network,
+			Address:       l.address,
+			ApplicationID: l.applicationID,
+			UserID:        c.UserID,
+			Senders:       c.Senders,
+		}
+		if cfg.ApplicationID == uuid.Nil {
+			cfg.ApplicationID = c.ApplicationID
+		}
+		if l.tls {
+			cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+			if err != nil {
+				fatal("Failed to load syslog TLS certificate", "error", err)
+			}
+			cfg.TLS = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+		}
+
+		listener := syslog.NewListener(cfg, uc)
+		if err := listener.Listen(); err != nil {
+			fatal("Failed to start syslog listener", "transport", l.name, "error", err)
+		}
+		slog.Info("Syslog listener started", "transport", l.name, "addr", listener.Addr().String())
+		listeners = append(listeners, listener)
+	}
+
+	return listeners
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/config"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/tlscert"
+)
+
+// serverTLSConfig builds the HTTPS configuration, serving the certificate from
+// a store that is reloaded when the files change, and verifying client
+// certificates against tls.client_ca_file when set. It returns nil without
+// tls.cert_file.
+func serverTLSConfig(c config.TLSConfig) (*tls.Config, *tlscert.Store, error) {
+	if c.CertFile == "" {
+		return nil, nil, nil
+	}
+
+	certs, err := tlscert.Load(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	tlsConfig := &tls.Config{
+		GetCertificate: certs.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+
+	if c.ClientCAFile != "" {
+		pem, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, nil, fmt.Errorf("no certificates found in client CA file %s", c.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		if c.ClientAuth == "require" {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return tlsConfig, certs, nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// api talks to the LoggingSSE server
+type api struct {
+	server string
+	apiKey string
+}
+
+// statusError is an unexpected HTTP response, carrying the server's error message
+type statusError struct {
+	Status  int
+	Message string
+}
+
+func (e *statusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("server responded %d %s", e.Status, http.StatusText(e.Status))
+	}
+	return fmt.Sprintf("server responded %d: %s", e.Status, e.Message)
+}
+
+// temporary reports whether retrying the request may succeed
+func (e *statusError) temporary() bool {
+	return e.Status == http.StatusTooManyRequests || e.Status >= 500
+}
+
+// apiKeyTransport adds the API key to every request, including those sent by pkg/client
+type apiKeyTransport struct {
+	apiKey string
+	base   http.RoundTripper
+}
+
+func (t apiKeyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if t.apiKey != "" {
+		r = r.Clone(r.Context())
+		r.Header.Set("X-API-Key", t.apiKey)
+	}
+	return t.base.RoundTrip(r)
+}
+
+// httpClient returns a client authenticating with the API key. A zero
+// timeout suits streams, which stay open until cancelled.
+func (a *api) httpClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: apiKeyTransport{apiKey: a.apiKey, base: http.DefaultTransport},
+	}
+}
+
+func (a *api) url(path string, query url.Values) string {
+	u := strings.TrimRight(a.server, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// getJSON decodes the JSON response of a GET request into out
+func (a *api) getJSON(ctx context.Context, path string, query url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url(path, query), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.httpClient(30 * time.Second).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return readStatusError(resp)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// stream reads the server-sent events at path, calling opened once the
+// server accepted the request and handle with the data of each event.
+// lastEventID resumes after an event already seen and is updated as events
+// arrive. It returns when the connection ends.
+func (a *api) stream(ctx context.Context, path string, lastEventID *string, opened func(), handle func(data []byte) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.url(path, nil), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if *lastEventID != "" {
+		req.Header.Set("Last-Event-ID", *lastEventID)
+	}
+
+	resp, err := a.httpClient(0).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return readStatusError(resp)
+	}
+	if opened != nil {
+		opened()
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	var data bytes.Buffer
+	var id, event string
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if err == io.EOF {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		line = bytes.TrimRight(line, "\r\n")
+
+		// A blank line dispatches the event; named events such as the server's
+		// shutdown notice are not logs, and the stream ending is handled by the caller
+		if len(line) == 0 {
+			if data.Len() > 0 && (event == "" || event == "message") {
+				if id != "" {
+					*lastEventID = id
+				}
+				if err := handle(bytes.TrimSuffix(data.Bytes(), []byte("\n"))); err != nil {
+					return err
+				}
+			}
+			data.Reset()
+			id, event = "", ""
+			continue
+		}
+
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(field) {
+		case "data":
+			data.Write(value)
+			data.WriteByte('\n')
+		case "id":
+			id = string(value)
+		case "event":
+			event = string(value)
+		}
+	}
+}
+
+func readStatusError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var payload struct {
+		Error string `json:"error"`
+	}
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &payload) == nil && payload.Error != "" {
+		message = payload.Error
+	}
+	return &statusError{Status: resp.StatusCode, Message: message}
+}
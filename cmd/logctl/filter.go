@@ -0,0 +1,155 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+// tagFlag collects repeated --tag key:value flags
+type tagFlag map[string]string
+
+func (t tagFlag) String() string {
+	pairs := make([]string, 0, len(t))
+	for key, value := range t {
+		pairs = append(pairs, key+":"+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (t tagFlag) Set(raw string) error {
+	key, value, ok := strings.Cut(raw, ":")
+	if !ok || key == "" {
+		return fmt.Errorf("tag must be formatted as key:value, got %q", raw)
+	}
+	t[key] = value
+	return nil
+}
+
+// filter selects logs by level, source and tags, the same way the list endpoint does
+type filter struct {
+	levels   string
+	minLevel string
+	source   string
+	tags     tagFlag
+}
+
+func (f *filter) register(fs *flag.FlagSet) {
+	f.tags = make(tagFlag)
+	fs.StringVar(&f.levels, "level", "", "comma-separated levels to include, such as WARN,ERROR")
+	fs.StringVar(&f.minLevel, "min-level", "", "include this level and more severe ones")
+	fs.StringVar(&f.source, "source", "", "include only logs from this source")
+	fs.Var(f.tags, "tag", "include only logs with this key:value tag (repeatable)")
+}
+
+// query adds the filter to the parameters of the list endpoint
+func (f *filter) query(q url.Values) {
+	if f.levels != "" {
+		q.Set("level", f.levels)
+	}
+	if f.minLevel != "" {
+		q.Set("min_level", f.minLevel)
+	}
+	if f.source != "" {
+		q.Set("source", f.source)
+	}
+	for key, value := range f.tags {
+		q.Add("tag", key+":"+value)
+	}
+}
+
+// matcher validates the filter and returns a function applying it to
+// streamed logs, which the server does not filter
+func (f *filter) matcher() (func(dto.LogOutput) bool, error) {
+	levels := make(map[valueobjects.LogLevel]bool)
+	for _, raw := range strings.Split(f.levels, ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		level, err := valueobjects.NewLogLevel(raw)
+		if err != nil {
+			return nil, err
+		}
+		levels[level] = true
+	}
+	var minLevel valueobjects.LogLevel
+	if f.minLevel != "" {
+		level, err := valueobjects.NewLogLevel(f.minLevel)
+		if err != nil {
+			return nil, err
+		}
+		minLevel = level
+	}
+
+	return func(l dto.LogOutput) bool {
+		level := valueobjects.LogLevel(l.Level)
+		if len(levels) > 0 && !levels[level] {
+			return false
+		}
+		if minLevel != "" && level.IsLessSevereThan(minLevel) {
+			return false
+		}
+		if f.source != "" && l.Source != f.source {
+			return false
+		}
+		for key, value := range f.tags {
+			if l.Tags[key] != value {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// String formats the filter in the syntax read by parseFilter
+func (f *filter) String() string {
+	var parts []string
+	for _, part := range []struct{ name, value string }{
+		{"level", f.levels},
+		{"min-level", f.minLevel},
+		{"source", f.source},
+	} {
+		if part.value != "" {
+			parts = append(parts, part.name+"="+part.value)
+		}
+	}
+	var tags []string
+	for key, value := range f.tags {
+		tags = append(tags, "tag="+key+":"+value)
+	}
+	sort.Strings(tags)
+	return strings.Join(append(parts, tags...), " ")
+}
+
+// parseFilter reads space-separated name=value terms using the flag names,
+// such as "min-level=WARN source=api tag=env:prod"
+func parseFilter(text string) (filter, error) {
+	f := filter{tags: make(tagFlag)}
+	for _, term := range strings.Fields(text) {
+		name, value, ok := strings.Cut(term, "=")
+		if !ok {
+			return f, fmt.Errorf("filter terms must be formatted as name=value, got %q", term)
+		}
+		switch name {
+		case "level":
+			f.levels = value
+		case "min-level":
+			f.minLevel = value
+		case "source":
+			f.source = value
+		case "tag":
+			if err := f.tags.Set(value); err != nil {
+				return f, err
+			}
+		default:
+			return f, fmt.Errorf("unknown filter %q, use level, min-level, source or tag", name)
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/pkg/client"
+)
+
+func TestAPI_Stream(t *testing.T) {
+	var apiKey, lastEventID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("X-API-Key")
+		lastEventID = r.Header.Get("Last-Event-ID")
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id: 1\ndata: {\"message\":\"first\"}\n\n: comment\n\nid: 2\ndata: line one\ndata: line two\n\nid: 3\ndata: {\"reconnect\":true}\nevent: shutdown\nretry: 1000\n\n")
+	}))
+	defer server.Close()
+
+	a := &api{server: server.URL, apiKey: "secret"}
+	var events []string
+	eventID := "7"
+	err := a.stream(context.Background(), "/api/v1/events/app", &eventID, nil, func(data []byte) error {
+		events = append(events, string(data))
+		return nil
+	})
+
+	if apiKey != "secret" || lastEventID != "7" {
+		t.Errorf("Expected API key and Last-Event-ID headers, got %q and %q", apiKey, lastEventID)
+	}
+	if len(events) != 2 || events[0] != `{"message":"first"}` || events[1] != "line one\nline two" {
+		t.Errorf("Unexpected events: %q", events)
+	}
+	if eventID != "2" {
+		t.Errorf("Expected last event ID 2, got %q", eventID)
+	}
+	if err == nil {
+		t.Error("Expected an error when the stream ends")
+	}
+}
+
+func TestAPI_StatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"application_id must be a valid UUID"}`)
+	}))
+	defer server.Close()
+
+	a := &api{server: server.URL}
+	err := a.getJSON(context.Background(), "/api/v1/logs", nil, &dto.ListLogsOutput{})
+
+	statusErr, ok := err.(*statusError)
+	if !ok || statusErr.Status != http.StatusBadRequest || statusErr.Message != "application_id must be a valid UUID" {
+		t.Fatalf("Expected status error with the server message, got %v", err)
+	}
+	if statusErr.temporary() {
+		t.Error("Expected 400 not to be retried")
+	}
+}
+
+func TestFilter_Matcher(t *testing.T) {
+	f := filter{minLevel: "warn", source: "api", tags: tagFlag{"env": "prod"}}
+	match, err := f.matcher()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		log      dto.LogOutput
+		expected bool
+	}{
+		{dto.LogOutput{Level: "ERROR", Source: "api", Tags: map[string]string{"env": "prod"}}, true},
+		{dto.LogOutput{Level: "INFO", Source: "api", Tags: map[string]string{"env": "prod"}}, false},
+		{dto.LogOutput{Level: "ERROR", Source: "worker", Tags: map[string]string{"env": "prod"}}, false},
+		{dto.LogOutput{Level: "ERROR", Source: "api"}, false},
+	}
+	for _, tt := range tests {
+		if got := match(tt.log); got != tt.expected {
+			t.Errorf("Expected %v for %+v, got %v", tt.expected, tt.log, got)
+		}
+	}
+
+	if _, err := (&filter{levels: "WARN,LOUD"}).matcher(); err == nil {
+		t.Error("Expected an error for an invalid level")
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	l := dto.LogOutput{
+		Timestamp: "2025-01-02T15:04:05Z",
+		Level:     "ERROR",
+		Source:    "api",
+		Message:   "boom\n  at main.go:1",
+		Tags:      map[string]string{"env": "prod", "az": "b"},
+	}
+
+	var text bytes.Buffer
+	newPrinter(&text, formatText, true).print(l)
+	if !strings.HasPrefix(text.String(), "2025-01-02T15:04:05Z \x1b[31mERROR\x1b[0m [api] boom") {
+		t.Errorf("Unexpected text output: %q", text.String())
+	}
+
+	var table bytes.Buffer
+	p := newPrinter(&table, formatTable, false)
+	p.print(l)
+	p.flush()
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `boom\n  at main.go:1`) || !strings.HasSuffix(lines[1], "az=b env=prod") {
+		t.Errorf("Unexpected table output: %q", table.String())
+	}
+}
+
+func TestPrinter_EscapesControlCharacters(t *testing.T) {
+	l := dto.LogOutput{
+		Timestamp: "2025-01-02T15:04:05Z",
+		Level:     "INFO",
+		Source:    "api\x1b[2J",
+		Message:   "clear\x1b]0;title\x07 screen\r",
+		Tags:      map[string]string{"env": "\x1b[31mprod"},
+	}
+
+	for _, format := range []string{formatText, formatTable} {
+		var out bytes.Buffer
+		p := newPrinter(&out, format, false)
+		p.print(l)
+		p.flush()
+		if strings.ContainsAny(out.String(), "\x1b\x07\r") {
+			t.Errorf("Expected control characters to be escaped in %s output, got %q", format, out.String())
+		}
+		if !strings.Contains(out.String(), `clear\x1b]0;title\x07 screen\x0d`) {
+			t.Errorf("Expected escaped message in %s output, got %q", format, out.String())
+		}
+	}
+}
+
+func TestReadEntries(t *testing.T) {
+	input := "{\"message\":\"json entry\",\"level\":\"warn\",\"tags\":{\"env\":\"prod\"}}\n\n{\"level\":\"info\"}\n"
+
+	var messages []string
+	err := readEntries(context.Background(), strings.NewReader(input), formatInputJSON, func(entry client.Entry) error {
+		messages = append(messages, entry.Level+" "+entry.Message)
+		return nil
+	})
+
+	if len(messages) != 1 || messages[0] != "WARN json entry" {
+		t.Errorf("Unexpected entries: %v", messages)
+	}
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected an error for line 3, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+// Command logctl tails, queries and sends logs from the command line.
+//
+//	logctl tail  --app <id> [--min-level WARN] [--source api] [--tag env:prod]
+//	logctl query --app <id> --since 1h [--output table|json|ndjson]
+//	logctl send  --app <id> --user <id> < app.log
+//	logctl ui    --app <id>[,<id>...]
+//
+// The server and API key are read from --server/--api-key or the
+// LOGCTL_SERVER and LOGCTL_API_KEY environment variables.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const defaultServerURL = "http://localhost:8080"
+
+const usage = `Usage: logctl <command> [flags]
+
+Commands:
+  tail    Stream an application's logs as they arrive
+  query   Search stored logs
+  send    Send log lines read from stdin
+  ui      Explore an application's logs live in a full-screen terminal UI
+
+Run "logctl <command> -h" for the flags of a command.
+
+Environment:
+  LOGCTL_SERVER          Server URL (default ` + defaultServerURL + `)
+  LOGCTL_API_KEY         API key sent in the X-API-Key header
+  LOGCTL_APPLICATION_ID  Default for --app
+  LOGCTL_USER_ID         Default for --user
+`
+
+var commands = map[string]func(ctx context.Context, args []string) error{
+	"tail":  runTail,
+	"query": runQuery,
+	"send":  runSend,
+	"ui":    runUI,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		fmt.Print(usage)
+		return
+	}
+	run, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "logctl: unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := run(ctx, os.Args[2:])
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "logctl %s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+// errUsage is returned once the flag set has already reported the problem
+var errUsage = errors.New("invalid usage")
+
+// newFlagSet returns a flag set with the connection flags shared by every command
+func newFlagSet(name, synopsis string) (*flag.FlagSet, *api) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: logctl %s\n\nFlags:\n", synopsis)
+		fs.PrintDefaults()
+	}
+
+	a := &api{}
+	fs.StringVar(&a.server, "server", envOr("LOGCTL_SERVER", defaultServerURL), "server URL")
+	fs.StringVar(&a.apiKey, "api-key", os.Getenv("LOGCTL_API_KEY"), "API key sent in the X-API-Key header")
+	return fs, a
+}
+
+// parseFlags parses args, turning flag errors into errUsage since the flag
+// set has already printed them
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "unexpected arguments: %v\n", fs.Args())
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+// Output formats
+const (
+	formatText   = "text"
+	formatTable  = "table"
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+)
+
+// levelColors are ANSI colors per level. All codes have the same length so
+// colored table columns stay aligned.
+var levelColors = map[valueobjects.LogLevel]string{
+	valueobjects.LogLevelTrace: "\x1b[90m",
+	valueobjects.LogLevelDebug: "\x1b[36m",
+	valueobjects.LogLevelInfo:  "\x1b[32m",
+	valueobjects.LogLevelWarn:  "\x1b[33m",
+	valueobjects.LogLevelError: "\x1b[31m",
+	valueobjects.LogLevelFatal: "\x1b[35m",
+}
+
+const colorReset = "\x1b[0m"
+
+// printer writes logs in one of the output formats
+type printer struct {
+	format string
+	color  bool
+	out    io.Writer
+	table  *tabwriter.Writer
+}
+
+func newPrinter(w io.Writer, format string, color bool) *printer {
+	p := &printer{format: format, color: color, out: w}
+	if format == formatTable {
+		p.table = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(p.table, "TIMESTAMP\tLEVEL\tSOURCE\tMESSAGE\tTAGS")
+		p.out = p.table
+	}
+	return p
+}
+
+func (p *printer) print(l dto.LogOutput) error {
+	switch p.format {
+	case formatJSON, formatNDJSON:
+		return json.NewEncoder(p.out).Encode(l)
+	case formatTable:
+		_, err := fmt.Fprintf(p.out, "%s\t%s\t%s\t%s\t%s\n",
+			singleLine(l.Timestamp), p.level(l.Level, 0), dash(singleLine(l.Source)), singleLine(l.Message), dash(formatTags(l.Tags)))
+		return err
+	default:
+		line := sanitize(l.Timestamp) + " " + p.level(l.Level, 5)
+		if l.Source != "" {
+			line += " [" + singleLine(l.Source) + "]"
+		}
+		line += " " + sanitize(l.Message)
+		if tags := formatTags(l.Tags); tags != "" {
+			line += " " + tags
+		}
+		_, err := fmt.Fprintln(p.out, line)
+		return err
+	}
+}
+
+// flush writes the buffered table
+func (p *printer) flush() error {
+	if p.table != nil {
+		return p.table.Flush()
+	}
+	return nil
+}
+
+// level pads the level to width and colors it
+func (p *printer) level(level string, width int) string {
+	padded := fmt.Sprintf("%-*s", width, singleLine(level))
+	if color, ok := levelColors[valueobjects.LogLevel(level)]; ok && p.color {
+		return color + padded + colorReset
+	}
+	return padded
+}
+
+// useColor reports whether to color output written to stdout, honouring the
+// NO_COLOR convention and disabling color when stdout is not a terminal
+func useColor(disabled bool) bool {
+	if disabled || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func validFormat(format string, allowed ...string) error {
+	for _, f := range allowed {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("output must be one of %s, got %q", strings.Join(allowed, ", "), format)
+}
+
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, singleLine(key+"="+value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+// singleLine keeps multi-line messages such as stack traces on one table row
+func singleLine(s string) string {
+	return sanitize(strings.NewReplacer("\r\n", `\n`, "\n", `\n`, "\t", " ").Replace(s))
+}
+
+// sanitize escapes control characters other than newline and tab, so that
+// text from untrusted logs cannot inject terminal escape sequences
+func sanitize(s string) string {
+	if !strings.ContainsFunc(s, isEscaped) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if isEscaped(r) {
+			fmt.Fprintf(&b, `\x%02x`, r)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// isEscaped reports C0 and C1 control characters and DEL, except newline and tab
+func isEscaped(r rune) bool {
+	return r != '\n' && r != '\t' && unicode.IsControl(r)
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
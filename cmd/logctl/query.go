@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+)
+
+// maxPageSize is the largest limit the list endpoint accepts
+const maxPageSize = 1000
+
+func runQuery(ctx context.Context, args []string) error {
+	fs, a := newFlagSet("query", "query --app <id> [flags]")
+	app := fs.String("app", os.Getenv("LOGCTL_APPLICATION_ID"), "application ID to search")
+	since := fs.Duration("since", 0, "only logs from this long ago onwards, such as 15m or 24h")
+	from := fs.String("from", "", "only logs at or after this RFC3339 time")
+	to := fs.String("to", "", "only logs before this RFC3339 time")
+	search := fs.String("q", "", "full-text search in messages")
+	timeField := fs.String("time-field", "", "time to filter and sort by: timestamp or ingested_at")
+	order := fs.String("order", "", "sort order: asc or desc")
+	limit := fs.Int("limit", 100, "maximum number of logs")
+	offset := fs.Int("offset", 0, "number of logs to skip")
+	output := fs.String("output", formatTable, "output format: table, json or ndjson")
+	noColor := fs.Bool("no-color", false, "disable colored levels")
+	var f filter
+	f.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	applicationID, err := uuid.Parse(*app)
+	if err != nil {
+		return fmt.Errorf("--app must be an application UUID, got %q", *app)
+	}
+	if err := validFormat(*output, formatTable, formatJSON, formatNDJSON); err != nil {
+		return err
+	}
+	if *since > 0 && *from != "" {
+		return fmt.Errorf("--since and --from cannot be combined")
+	}
+	if *limit <= 0 {
+		return fmt.Errorf("--limit must be positive")
+	}
+
+	query := url.Values{"application_id": {applicationID.String()}}
+	f.query(query)
+	if *since > 0 {
+		query.Set("from", time.Now().Add(-*since).UTC().Format(time.RFC3339Nano))
+	}
+	for param, value := range map[string]string{"from": *from, "to": *to} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+			return fmt.Errorf("--%s must be an RFC3339 time such as 2025-01-02T15:04:05Z, got %q", param, value)
+		}
+		query.Set(param, value)
+	}
+	for param, value := range map[string]string{"q": *search, "time_field": *timeField, "order": *order} {
+		if value != "" {
+			query.Set(param, value)
+		}
+	}
+
+	// Limits above a page are fetched page by page
+	result := dto.ListLogsOutput{Logs: []dto.LogOutput{}}
+	for remaining, next := *limit, *offset; remaining > 0; {
+		query.Set("limit", strconv.Itoa(min(remaining, maxPageSize)))
+		query.Set("offset", strconv.Itoa(next))
+
+		var page dto.ListLogsOutput
+		if err := a.getJSON(ctx, "/api/v1/logs", query, &page); err != nil {
+			return err
+		}
+		result.Logs = append(result.Logs, page.Logs...)
+		result.TimeField, result.Order, result.HasMore = page.TimeField, page.Order, page.HasMore
+		remaining -= len(page.Logs)
+		next += len(page.Logs)
+		if !page.HasMore || len(page.Logs) == 0 {
+			break
+		}
+	}
+	result.Limit = *limit
+	result.Offset = *offset
+
+	if *output == formatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+	p := newPrinter(os.Stdout, *output, useColor(*noColor))
+	for _, l := range result.Logs {
+		if err := p.print(l); err != nil {
+			return err
+		}
+	}
+	if err := p.flush(); err != nil {
+		return err
+	}
+	if result.HasMore && *output == formatTable {
+		fmt.Fprintf(os.Stderr, "More logs match; continue with --offset %d\n", *offset+len(result.Logs))
+	}
+	return nil
+}
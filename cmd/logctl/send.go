@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+	"github.com/rubensantoniorosa2704/LoggingSSE/pkg/client"
+)
+
+const (
+	formatInputText = "text"
+	formatInputJSON = "json"
+
+	maxInputLine = 1024 * 1024
+	closeTimeout = 30 * time.Second
+	// flushEvery stays well below the client's default buffer of 10000 logs
+	flushEvery = 1000
+)
+
+// jsonLine is an entry read with --format json; unset fields take the flag values
+type jsonLine struct {
+	Message   string                 `json:"message"`
+	Level     string                 `json:"level"`
+	Source    string                 `json:"source"`
+	Tags      map[string]string      `json:"tags"`
+	Metadata  map[string]interface{} `json:"metadata"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+func runSend(ctx context.Context, args []string) error {
+	fs, a := newFlagSet("send", "send --app <id> --user <id> [flags] < file")
+	app := fs.String("app", os.Getenv("LOGCTL_APPLICATION_ID"), "application ID the logs belong to")
+	user := fs.String("user", os.Getenv("LOGCTL_USER_ID"), "user ID the logs belong to")
+	level := fs.String("level", client.LevelInfo, "level of each entry")
+	source := fs.String("source", "logctl", "source of each entry")
+	format := fs.String("format", formatInputText, "input format: text (one message per line) or json (one object per line)")
+	tags := make(tagFlag)
+	fs.Var(tags, "tag", "key:value tag added to each entry (repeatable)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	applicationID, err := uuid.Parse(*app)
+	if err != nil {
+		return fmt.Errorf("--app must be an application UUID, got %q", *app)
+	}
+	userID, err := uuid.Parse(*user)
+	if err != nil {
+		return fmt.Errorf("--user must be a user UUID, got %q", *user)
+	}
+	defaultLevel, err := valueobjects.NewLogLevel(*level)
+	if err != nil {
+		return err
+	}
+	if *format != formatInputText && *format != formatInputJSON {
+		return fmt.Errorf("format must be text or json, got %q", *format)
+	}
+
+	c, err := client.New(client.Config{
+		URL:           a.server,
+		ApplicationID: applicationID,
+		UserID:        userID,
+		Source:        *source,
+		HTTPClient:    a.httpClient(10 * time.Second),
+		OnError: func(err error) {
+			fmt.Fprintf(os.Stderr, "logctl: %v\n", err)
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	read := 0
+	readErr := readEntries(ctx, os.Stdin, *format, func(entry client.Entry) error {
+		if entry.Level == "" {
+			entry.Level = defaultLevel.String()
+		}
+		for key, value := range tags {
+			if _, ok := entry.Tags[key]; !ok {
+				if entry.Tags == nil {
+					entry.Tags = make(map[string]string)
+				}
+				entry.Tags[key] = value
+			}
+		}
+		if err := c.Log(entry); err != nil {
+			return err
+		}
+		// Wait for delivery regularly so stdin cannot outrun the buffer
+		if read++; read%flushEvery == 0 {
+			return c.Flush(ctx)
+		}
+		return nil
+	})
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	closeErr := c.Close(closeCtx)
+
+	stats := c.Stats()
+	fmt.Fprintf(os.Stderr, "Sent %d logs", stats.Sent)
+	if stats.Dropped > 0 {
+		fmt.Fprintf(os.Stderr, ", dropped %d", stats.Dropped)
+	}
+	fmt.Fprintln(os.Stderr)
+
+	switch {
+	case readErr != nil:
+		return readErr
+	case closeErr != nil:
+		return closeErr
+	case stats.Dropped > 0:
+		return fmt.Errorf("%d logs could not be delivered", stats.Dropped)
+	}
+	return nil
+}
+
+// readEntries calls add with an entry for each non-empty line of r
+func readEntries(ctx context.Context, r io.Reader, format string, add func(client.Entry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxInputLine)
+	for number := 1; scanner.Scan(); number++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		entry := client.Entry{Message: line}
+		if format == formatInputJSON {
+			var parsed jsonLine
+			if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+				return fmt.Errorf("line %d: invalid JSON: %w", number, err)
+			}
+			if parsed.Message == "" {
+				return fmt.Errorf("line %d: message is required", number)
+			}
+			entry = client.Entry{
+				Message:   parsed.Message,
+				Level:     strings.ToUpper(parsed.Level),
+				Source:    parsed.Source,
+				Tags:      parsed.Tags,
+				Metadata:  parsed.Metadata,
+				Timestamp: parsed.Timestamp,
+			}
+		}
+		if err := add(entry); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+	return nil
+}
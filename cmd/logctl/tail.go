@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+)
+
+const (
+	minReconnectWait = time.Second
+	maxReconnectWait = 30 * time.Second
+)
+
+func runTail(ctx context.Context, args []string) error {
+	fs, a := newFlagSet("tail", "tail --app <id> [flags]")
+	app := fs.String("app", os.Getenv("LOGCTL_APPLICATION_ID"), "application ID to stream")
+	output := fs.String("output", formatText, "output format: text or ndjson")
+	noColor := fs.Bool("no-color", false, "disable colored levels")
+	var f filter
+	f.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	applicationID, err := uuid.Parse(*app)
+	if err != nil {
+		return fmt.Errorf("--app must be an application UUID, got %q", *app)
+	}
+	if err := validFormat(*output, formatText, formatNDJSON); err != nil {
+		return err
+	}
+	match, err := f.matcher()
+	if err != nil {
+		return err
+	}
+
+	p := newPrinter(os.Stdout, *output, useColor(*noColor))
+	return follow(ctx, a, applicationID, streamHandler{
+		log: func(l dto.LogOutput) error {
+			if !match(l) {
+				return nil
+			}
+			return p.print(l)
+		},
+		interrupted: func(err error, wait time.Duration) {
+			fmt.Fprintf(os.Stderr, "logctl: stream interrupted (%v), reconnecting in %s\n", err, wait)
+		},
+	})
+}
+
+// streamHandler receives what follow reads; nil callbacks are skipped
+type streamHandler struct {
+	log         func(dto.LogOutput) error
+	connected   func()
+	interrupted func(err error, wait time.Duration)
+}
+
+// follow streams the logs of an application until ctx ends, reconnecting
+// with backoff and resuming after the last event received. Errors that
+// retrying cannot fix, such as an invalid application ID, end it.
+func follow(ctx context.Context, a *api, applicationID uuid.UUID, h streamHandler) error {
+	path := "/api/v1/events/" + applicationID.String()
+	var lastEventID string
+	wait := minReconnectWait
+
+	for {
+		err := a.stream(ctx, path, &lastEventID, h.connected, func(data []byte) error {
+			// A delivered event means the connection is healthy again
+			wait = minReconnectWait
+
+			var l dto.LogOutput
+			if err := json.Unmarshal(data, &l); err != nil {
+				// The server only publishes log outputs, so anything else is skipped
+				return nil
+			}
+			return h.log(l)
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && !statusErr.temporary() {
+			return err
+		}
+
+		if h.interrupted != nil {
+			h.interrupted(err, wait)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, maxReconnectWait)
+	}
+}
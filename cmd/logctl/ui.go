@@ -0,0 +1,654 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+// maxUILogs is the default number of logs the UI keeps; the oldest are dropped first
+const maxUILogs = 10000
+
+const uiHelp = "q quit  space pause  ↑↓ move  enter details  / search  n/N next/prev  f filter  tab/a application  c clear"
+
+const (
+	reverseVideo = "\x1b[7m"
+	bold         = "\x1b[1m"
+)
+
+// key is a decoded key press: a printable rune or one of the named keys
+type key struct {
+	r    rune
+	name string
+}
+
+// Named keys
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyPageUp    = "pgup"
+	keyPageDown  = "pgdn"
+	keyHome      = "home"
+	keyEnd       = "end"
+	keyEnter     = "enter"
+	keyEscape    = "esc"
+	keyBackspace = "backspace"
+	keyTab       = "tab"
+	keyCtrlC     = "ctrl-c"
+)
+
+// escapeKeys are the terminal sequences of the named keys
+var escapeKeys = map[string]string{
+	"\x1b[A": keyUp, "\x1bOA": keyUp,
+	"\x1b[B": keyDown, "\x1bOB": keyDown,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+	"\x1b[H":  keyHome, "\x1bOH": keyHome, "\x1b[1~": keyHome,
+	"\x1b[F": keyEnd, "\x1bOF": keyEnd, "\x1b[4~": keyEnd,
+}
+
+// decodeKeys splits the bytes of one terminal read into key presses.
+// Unknown escape sequences are dropped.
+func decodeKeys(b []byte) []key {
+	var keys []key
+	for len(b) > 0 {
+		if b[0] == 0x1b {
+			if len(b) == 1 {
+				return append(keys, key{name: keyEscape})
+			}
+			matched := false
+			for seq, name := range escapeKeys {
+				if strings.HasPrefix(string(b), seq) {
+					keys = append(keys, key{name: name})
+					b = b[len(seq):]
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				// Skip an unknown CSI sequence up to its final byte
+				end := 1
+				if b[1] == '[' || b[1] == 'O' {
+					end = 2
+					for end < len(b) && (b[end] < 0x40 || b[end] > 0x7e) {
+						end++
+					}
+					end++
+				}
+				b = b[min(end, len(b)):]
+			}
+			continue
+		}
+
+		switch b[0] {
+		case '\r', '\n':
+			keys = append(keys, key{name: keyEnter})
+		case 0x7f, 0x08:
+			keys = append(keys, key{name: keyBackspace})
+		case '\t':
+			keys = append(keys, key{name: keyTab})
+		case 0x03:
+			keys = append(keys, key{name: keyCtrlC})
+		default:
+			r, size := utf8.DecodeRune(b)
+			if r >= ' ' {
+				keys = append(keys, key{r: r})
+			}
+			b = b[size:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
+
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptSearch
+	promptFilter
+	promptApp
+)
+
+var promptLabels = map[promptKind]string{
+	promptSearch: "search",
+	promptFilter: "filter (level= min-level= source= tag=key:value)",
+	promptApp:    "application ID",
+}
+
+// uiAction tells the terminal loop what to do after a key press
+type uiAction int
+
+const (
+	uiNone uiAction = iota
+	uiQuit
+	uiSwitchApp
+)
+
+// uiUpdate carries a log or a connection status from the stream of one
+// application; gen tells apart updates of a stream replaced since
+type uiUpdate struct {
+	gen    int
+	log    *dto.LogOutput
+	status string
+}
+
+// uiModel is the state of the terminal UI. It is only used from the
+// terminal loop, which feeds it keys and logs and draws what render returns.
+type uiModel struct {
+	apps  []uuid.UUID
+	app   int
+	limit int
+	color bool
+
+	logs    []dto.LogOutput
+	pending []dto.LogOutput // received while paused
+	paused  bool
+
+	filterText string
+	match      func(dto.LogOutput) bool
+	view       []int // indexes of the logs passing the filter
+
+	selected int // position in view
+	top      int
+	follow   bool
+	expanded bool
+	search   string
+
+	prompt promptKind
+	input  string
+
+	status string
+	notice string
+
+	width, height int
+}
+
+func newUIModel(apps []uuid.UUID, filterText string, limit int, color bool) (*uiModel, error) {
+	m := &uiModel{
+		apps:   apps,
+		limit:  max(limit, 1),
+		color:  color,
+		follow: true,
+		status: "connecting",
+		width:  80,
+		height: 24,
+	}
+	if err := m.setFilter(filterText); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *uiModel) add(l dto.LogOutput) {
+	if m.paused {
+		m.pending = append(m.pending, l)
+		if len(m.pending) > m.limit {
+			m.pending = m.pending[len(m.pending)-m.limit:]
+		}
+		return
+	}
+
+	m.logs = append(m.logs, l)
+	if m.match(l) {
+		m.view = append(m.view, len(m.logs)-1)
+	}
+	// Trim in chunks so that the view is not rebuilt for every log
+	if len(m.logs) > m.limit+m.limit/10 {
+		drop := len(m.logs) - m.limit
+		anchor := m.selectedIndex() - drop
+		m.logs = append([]dto.LogOutput(nil), m.logs[drop:]...)
+		m.refilter(anchor)
+	}
+	if m.follow {
+		m.selected = max(len(m.view)-1, 0)
+	}
+}
+
+// selectedIndex returns the index in logs of the selected log, or -1
+func (m *uiModel) selectedIndex() int {
+	if m.selected < 0 || m.selected >= len(m.view) {
+		return -1
+	}
+	return m.view[m.selected]
+}
+
+// refilter rebuilds the view, selecting the first visible log at or after anchor
+func (m *uiModel) refilter(anchor int) {
+	m.view = m.view[:0]
+	for i, l := range m.logs {
+		if m.match(l) {
+			m.view = append(m.view, i)
+		}
+	}
+	m.selected = sort.SearchInts(m.view, anchor)
+	if m.follow || m.selected >= len(m.view) {
+		m.selected = max(len(m.view)-1, 0)
+	}
+}
+
+func (m *uiModel) setFilter(text string) error {
+	f, err := parseFilter(text)
+	if err != nil {
+		return err
+	}
+	match, err := f.matcher()
+	if err != nil {
+		return err
+	}
+	m.filterText = f.String()
+	m.match = match
+	m.refilter(m.selectedIndex())
+	return nil
+}
+
+func (m *uiModel) handleKey(k key) uiAction {
+	m.notice = ""
+	if m.prompt != promptNone {
+		return m.promptKey(k)
+	}
+
+	_, body := m.layout()
+	switch {
+	case k.name == keyCtrlC || k.r == 'q':
+		return uiQuit
+	case k.r == ' ' || k.r == 'p':
+		m.togglePause()
+	case k.name == keyUp || k.r == 'k':
+		m.move(-1)
+	case k.name == keyDown || k.r == 'j':
+		m.move(1)
+	case k.name == keyPageUp:
+		m.move(-body)
+	case k.name == keyPageDown:
+		m.move(body)
+	case k.name == keyHome || k.r == 'g':
+		m.move(-len(m.view))
+	case k.name == keyEnd || k.r == 'G':
+		m.move(len(m.view))
+	case k.name == keyEnter:
+		m.expanded = !m.expanded
+	case k.name == keyEscape:
+		if m.expanded {
+			m.expanded = false
+		} else {
+			m.search = ""
+		}
+	case k.r == '/':
+		m.prompt, m.input = promptSearch, ""
+	case k.r == 'n':
+		m.find(1, false)
+	case k.r == 'N':
+		m.find(-1, false)
+	case k.r == 'f':
+		m.prompt, m.input = promptFilter, m.filterText
+	case k.r == 'a':
+		m.prompt, m.input = promptApp, ""
+	case k.name == keyTab:
+		if len(m.apps) > 1 {
+			m.switchApp((m.app + 1) % len(m.apps))
+			return uiSwitchApp
+		}
+		m.notice = "Only one application; press a to add another"
+	case k.r == 'c':
+		m.logs, m.pending, m.view = nil, nil, nil
+		m.selected, m.top = 0, 0
+	}
+	return uiNone
+}
+
+func (m *uiModel) promptKey(k key) uiAction {
+	switch {
+	case k.name == keyEscape || k.name == keyCtrlC:
+		m.prompt = promptNone
+	case k.name == keyBackspace:
+		if _, size := utf8.DecodeLastRuneInString(m.input); size > 0 {
+			m.input = m.input[:len(m.input)-size]
+		}
+	case k.name == keyEnter:
+		kind, input := m.prompt, strings.TrimSpace(m.input)
+		m.prompt = promptNone
+		return m.submit(kind, input)
+	case k.r != 0:
+		m.input += string(k.r)
+	}
+	return uiNone
+}
+
+func (m *uiModel) submit(kind promptKind, input string) uiAction {
+	switch kind {
+	case promptSearch:
+		m.search = input
+		if input != "" {
+			m.find(1, true)
+		}
+	case promptFilter:
+		if err := m.setFilter(input); err != nil {
+			m.notice = err.Error()
+		}
+	case promptApp:
+		if input == "" {
+			return uiNone
+		}
+		id, err := uuid.Parse(input)
+		if err != nil {
+			m.notice = fmt.Sprintf("%q is not an application UUID", input)
+			return uiNone
+		}
+		for i, app := range m.apps {
+			if app == id {
+				m.switchApp(i)
+				return uiSwitchApp
+			}
+		}
+		m.apps = append(m.apps, id)
+		m.switchApp(len(m.apps) - 1)
+		return uiSwitchApp
+	}
+	return uiNone
+}
+
+func (m *uiModel) switchApp(i int) {
+	m.app = i
+	m.logs, m.pending, m.view = nil, nil, nil
+	m.selected, m.top = 0, 0
+	m.paused, m.follow, m.expanded = false, true, false
+	m.status = "connecting"
+}
+
+func (m *uiModel) togglePause() {
+	m.paused = !m.paused
+	if !m.paused {
+		pending := m.pending
+		m.pending = nil
+		for _, l := range pending {
+			m.add(l)
+		}
+	}
+}
+
+// move changes the selection; reaching the newest log follows new ones again
+func (m *uiModel) move(delta int) {
+	if len(m.view) == 0 {
+		return
+	}
+	m.selected = min(max(m.selected+delta, 0), len(m.view)-1)
+	m.follow = m.selected == len(m.view)-1
+}
+
+// find selects the next log matching the search in direction dir, starting
+// with the selected one when inclusive
+func (m *uiModel) find(dir int, inclusive bool) {
+	if m.search == "" {
+		m.notice = "Press / to search"
+		return
+	}
+	n := len(m.view)
+	start := 1
+	if inclusive {
+		start = 0
+	}
+	for step := start; step < n+start; step++ {
+		i := ((m.selected+dir*step)%n + n) % n
+		if m.matchesSearch(m.logs[m.view[i]]) {
+			m.selected = i
+			m.follow = false
+			return
+		}
+	}
+	m.notice = fmt.Sprintf("No match for %q", m.search)
+}
+
+func (m *uiModel) matchesSearch(l dto.LogOutput) bool {
+	if m.search == "" {
+		return false
+	}
+	search := strings.ToLower(m.search)
+	if strings.Contains(strings.ToLower(l.Message), search) || strings.Contains(strings.ToLower(l.Source), search) {
+		return true
+	}
+	for key, value := range l.Tags {
+		if strings.Contains(strings.ToLower(key+":"+value), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// layout returns the heights of the log list and the details pane
+func (m *uiModel) layout() (list, details int) {
+	body := max(m.height-2, 1)
+	if !m.expanded {
+		return body, 0
+	}
+	details = body / 2
+	return body - details, details
+}
+
+// render returns the screen as exactly height lines
+func (m *uiModel) render() []string {
+	listHeight, detailsHeight := m.layout()
+	if m.selected < m.top {
+		m.top = m.selected
+	}
+	if m.selected >= m.top+listHeight {
+		m.top = m.selected - listHeight + 1
+	}
+	m.top = max(min(m.top, len(m.view)-listHeight), 0)
+
+	lines := []string{m.bar(m.header())}
+	for i := m.top; i < m.top+listHeight; i++ {
+		if i < len(m.view) {
+			lines = append(lines, m.row(m.logs[m.view[i]], i == m.selected))
+		} else {
+			lines = append(lines, "")
+		}
+	}
+	if detailsHeight > 0 {
+		details := []string{m.bar("Details (enter or esc to close)")}
+		if l, ok := m.selectedLog(); ok {
+			for _, line := range detailLines(l) {
+				details = append(details, truncate(line, m.width))
+			}
+		}
+		for i := 0; i < detailsHeight; i++ {
+			if i < len(details) {
+				lines = append(lines, details[i])
+			} else {
+				lines = append(lines, "")
+			}
+		}
+	}
+	lines = append(lines, m.footer())
+	return lines[:max(m.height, 1)]
+}
+
+func (m *uiModel) selectedLog() (dto.LogOutput, bool) {
+	if i := m.selectedIndex(); i >= 0 {
+		return m.logs[i], true
+	}
+	return dto.LogOutput{}, false
+}
+
+func (m *uiModel) header() string {
+	parts := []string{"logctl ui"}
+	if len(m.apps) > 0 {
+		app := "app " + m.apps[m.app].String()
+		if len(m.apps) > 1 {
+			app += fmt.Sprintf(" (%d/%d)", m.app+1, len(m.apps))
+		}
+		parts = append(parts, app)
+	}
+	parts = append(parts, m.status, fmt.Sprintf("%d/%d logs", len(m.view), len(m.logs)))
+	switch {
+	case m.paused:
+		parts = append(parts, fmt.Sprintf("PAUSED (+%d)", len(m.pending)))
+	case m.follow:
+		parts = append(parts, "FOLLOW")
+	}
+	if m.filterText != "" {
+		parts = append(parts, "filter: "+m.filterText)
+	}
+	if m.search != "" {
+		parts = append(parts, "search: "+m.search)
+	}
+	return strings.Join(parts, " | ")
+}
+
+func (m *uiModel) footer() string {
+	if m.prompt != promptNone {
+		return truncateLeft(promptLabels[m.prompt]+": "+m.input+"_", m.width)
+	}
+	if m.notice != "" {
+		return truncate(m.notice, m.width)
+	}
+	return truncate(uiHelp, m.width)
+}
+
+// bar renders a full-width line in reverse video
+func (m *uiModel) bar(text string) string {
+	return reverseVideo + pad(truncate(text, m.width), m.width) + colorReset
+}
+
+// row renders a log on one line. Search matches are marked in the gutter and
+// the selected row is shown in reverse video.
+func (m *uiModel) row(l dto.LogOutput, selected bool) string {
+	gutter := " "
+	if m.matchesSearch(l) {
+		gutter = "*"
+	}
+	prefix := gutter + shortTime(l.Timestamp) + " "
+	level := fmt.Sprintf("%-5s", singleLine(l.Level))
+	rest := " "
+	if l.Source != "" {
+		rest += "[" + singleLine(l.Source) + "] "
+	}
+	rest += singleLine(l.Message)
+	if tags := formatTags(l.Tags); tags != "" {
+		rest += "  " + tags
+	}
+
+	width := m.width - utf8.RuneCountInString(prefix) - utf8.RuneCountInString(level)
+	rest = truncate(rest, max(width, 0))
+	if selected {
+		return reverseVideo + pad(truncate(prefix+level+rest, m.width), m.width) + colorReset
+	}
+	if color, ok := levelColors[valueobjects.LogLevel(l.Level)]; ok && m.color {
+		level = color + level + colorReset
+	}
+	line := prefix + level + rest
+	if gutter == "*" && m.color {
+		line = bold + line + colorReset
+	}
+	return line
+}
+
+// detailLines describes a log with its tags and metadata. Control
+// characters are escaped so that log text cannot break the layout.
+func detailLines(l dto.LogOutput) []string {
+	lines := []string{
+		"ID:          " + l.ID.String(),
+		"Timestamp:   " + singleLine(l.Timestamp),
+		"Ingested at: " + singleLine(l.IngestedAt),
+		"Level:       " + singleLine(l.Level),
+		"Source:      " + dash(singleLine(l.Source)),
+		"Message:",
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(l.Message, "\r\n", "\n"), "\n") {
+		lines = append(lines, "  "+sanitize(strings.ReplaceAll(line, "\t", "    ")))
+	}
+
+	if len(l.Tags) > 0 {
+		lines = append(lines, "Tags:")
+		for _, tag := range strings.Split(formatTags(l.Tags), " ") {
+			lines = append(lines, "  "+tag)
+		}
+	}
+	if len(l.Metadata) > 0 {
+		lines = append(lines, "Metadata:")
+		metadata, err := json.MarshalIndent(l.Metadata, "  ", "  ")
+		if err != nil {
+			metadata = []byte(fmt.Sprint(l.Metadata))
+		}
+		for _, line := range strings.Split("  "+string(metadata), "\n") {
+			lines = append(lines, sanitize(line))
+		}
+	}
+	return lines
+}
+
+// shortTime shows the local time of day of an RFC3339 timestamp
+func shortTime(timestamp string) string {
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return pad(truncate(singleLine(timestamp), 12), 12)
+	}
+	return t.Local().Format("15:04:05.000")
+}
+
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// truncateLeft keeps the end of s, where the cursor of a prompt is
+func truncateLeft(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	return "…" + string(runes[len(runes)-width+1:])
+}
+
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// streamUpdates follows an application for the UI, sending its logs and
+// connection status tagged with gen
+func streamUpdates(ctx context.Context, a *api, app uuid.UUID, gen int, updates chan<- uiUpdate) {
+	send := func(u uiUpdate) {
+		u.gen = gen
+		select {
+		case updates <- u:
+		case <-ctx.Done():
+		}
+	}
+
+	err := follow(ctx, a, app, streamHandler{
+		log: func(l dto.LogOutput) error {
+			send(uiUpdate{log: &l})
+			return nil
+		},
+		connected: func() {
+			send(uiUpdate{status: "connected"})
+		},
+		interrupted: func(err error, wait time.Duration) {
+			send(uiUpdate{status: fmt.Sprintf("reconnecting in %s: %v", wait, err)})
+		},
+	})
+	if err != nil {
+		send(uiUpdate{status: "stopped: " + err.Error()})
+	}
+}
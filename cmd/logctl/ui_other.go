@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+func runUI(ctx context.Context, args []string) error {
+	return errors.New("the terminal UI is only available on Linux, macOS and the BSDs; use tail instead")
+}
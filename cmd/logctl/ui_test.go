@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+)
+
+func typeKeys(m *uiModel, input string) uiAction {
+	var action uiAction
+	for _, k := range decodeKeys([]byte(input)) {
+		action = m.handleKey(k)
+	}
+	return action
+}
+
+func newTestModel(t *testing.T, apps ...uuid.UUID) *uiModel {
+	if len(apps) == 0 {
+		apps = []uuid.UUID{uuid.New()}
+	}
+	m, err := newUIModel(apps, "", 100, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m.width, m.height = 120, 20
+	return m
+}
+
+func TestDecodeKeys(t *testing.T) {
+	keys := decodeKeys([]byte("\x1b[Aj\r\x1b[5~\x1b[1;5Cx\x7f\x1b"))
+
+	expected := []key{{name: keyUp}, {r: 'j'}, {name: keyEnter}, {name: keyPageUp}, {r: 'x'}, {name: keyBackspace}, {name: keyEscape}}
+	if len(keys) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, keys)
+	}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Errorf("Expected %v at %d, got %v", expected[i], i, keys[i])
+		}
+	}
+}
+
+func TestUIModel_FollowAndPause(t *testing.T) {
+	m := newTestModel(t)
+	m.add(dto.LogOutput{Message: "one", Level: "INFO"})
+	m.add(dto.LogOutput{Message: "two", Level: "INFO"})
+	if l, _ := m.selectedLog(); l.Message != "two" {
+		t.Errorf("Expected the newest log to be followed, got %q", l.Message)
+	}
+
+	typeKeys(m, " ")
+	m.add(dto.LogOutput{Message: "three", Level: "INFO"})
+	if len(m.logs) != 2 || len(m.pending) != 1 {
+		t.Fatalf("Expected the log to wait while paused, got %d shown and %d pending", len(m.logs), len(m.pending))
+	}
+	if !strings.Contains(m.header(), "PAUSED (+1)") {
+		t.Errorf("Expected the header to show the pause, got %q", m.header())
+	}
+
+	typeKeys(m, " ")
+	if l, _ := m.selectedLog(); len(m.logs) != 3 || l.Message != "three" {
+		t.Errorf("Expected pending logs to be shown on resume, got %d logs", len(m.logs))
+	}
+
+	typeKeys(m, "k")
+	m.add(dto.LogOutput{Message: "four", Level: "INFO"})
+	if l, _ := m.selectedLog(); l.Message != "two" {
+		t.Errorf("Expected the selection to stay put after moving up, got %q", l.Message)
+	}
+}
+
+func TestUIModel_FilterAndSearch(t *testing.T) {
+	m := newTestModel(t)
+	for i, level := range []string{"INFO", "ERROR", "WARN", "ERROR"} {
+		m.add(dto.LogOutput{Message: fmt.Sprintf("message %d", i), Level: level, Tags: map[string]string{"order": fmt.Sprint(i)}})
+	}
+
+	typeKeys(m, "fmin-level=error\r")
+	if len(m.view) != 2 || m.filterText != "min-level=error" {
+		t.Fatalf("Expected 2 logs to pass the filter, got %d (%q)", len(m.view), m.filterText)
+	}
+	typeKeys(m, "fsev=1\r")
+	if m.notice == "" || len(m.view) != 2 {
+		t.Errorf("Expected an invalid filter to be reported and ignored, got %q", m.notice)
+	}
+
+	typeKeys(m, "f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\r")
+	if len(m.view) != 4 {
+		t.Fatalf("Expected the cleared filter to show all logs, got %d", len(m.view))
+	}
+
+	typeKeys(m, "g/MESSAGE 2\r")
+	if l, _ := m.selectedLog(); l.Message != "message 2" || m.follow {
+		t.Errorf("Expected the search to select the match, got %q", l.Message)
+	}
+	typeKeys(m, "/order:3\r")
+	if l, _ := m.selectedLog(); l.Message != "message 3" {
+		t.Errorf("Expected tags to be searched, got %q", l.Message)
+	}
+	typeKeys(m, "/nothing\r")
+	if !strings.Contains(m.notice, "No match") {
+		t.Errorf("Expected a missing match to be reported, got %q", m.notice)
+	}
+}
+
+func TestUIModel_Render(t *testing.T) {
+	m := newTestModel(t)
+	m.color = true
+	m.height = 40
+	m.add(dto.LogOutput{
+		Message:   "payment failed\n\tat checkout",
+		Level:     "ERROR",
+		Source:    "billing",
+		Timestamp: "2025-01-02T15:04:05Z",
+		Tags:      map[string]string{"env": "prod"},
+		Metadata:  map[string]interface{}{"order": map[string]interface{}{"id": "o-42"}},
+	})
+	m.add(dto.LogOutput{Message: "ok", Level: "INFO"})
+	typeKeys(m, "k")
+
+	lines := m.render()
+	if len(lines) != m.height {
+		t.Fatalf("Expected %d lines, got %d", m.height, len(lines))
+	}
+	if !strings.Contains(lines[1], "[billing] payment failed\\n at checkout  env=prod") || !strings.HasPrefix(lines[1], reverseVideo) {
+		t.Errorf("Expected the selected log on one reversed line, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], levelColors["INFO"]+"INFO ") {
+		t.Errorf("Expected a colored level, got %q", lines[2])
+	}
+
+	typeKeys(m, "\r")
+	screen := strings.Join(m.render(), "\n")
+	for _, expected := range []string{"Source:      billing", "  env=prod", `"id": "o-42"`, "      at checkout"} {
+		if !strings.Contains(screen, expected) {
+			t.Errorf("Expected details to contain %q, got:\n%s", expected, screen)
+		}
+	}
+}
+
+func TestUIModel_EscapesControlCharacters(t *testing.T) {
+	m := newTestModel(t)
+	m.add(dto.LogOutput{
+		Message:  "line one\x1b[2J\r\nline two\x1b[H",
+		Level:    "INFO\x1b[8m",
+		Source:   "api\rspoof",
+		Metadata: map[string]interface{}{"note": "\u009b31m"},
+	})
+	typeKeys(m, "\r")
+
+	for _, line := range m.render() {
+		plain := strings.NewReplacer(reverseVideo, "", colorReset, "", bold, "").Replace(line)
+		if strings.ContainsAny(plain, "\x1b\r\u009b") {
+			t.Errorf("Expected control characters to be escaped, got %q", line)
+		}
+	}
+	screen := strings.Join(m.render(), "\n")
+	for _, expected := range []string{`  line one\x1b[2J`, `  line two\x1b[H`, `Source:      api\x0dspoof`} {
+		if !strings.Contains(screen, expected) {
+			t.Errorf("Expected details to contain %q, got:\n%s", expected, screen)
+		}
+	}
+}
+
+func TestUIModel_SwitchApp(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	m := newTestModel(t, first, second)
+	m.add(dto.LogOutput{Message: "from first", Level: "INFO"})
+
+	if action := typeKeys(m, "\t"); action != uiSwitchApp || m.apps[m.app] != second {
+		t.Fatalf("Expected tab to switch to the second application, got %v", m.apps[m.app])
+	}
+	if len(m.logs) != 0 || m.status != "connecting" {
+		t.Errorf("Expected the buffer to be cleared, got %d logs", len(m.logs))
+	}
+
+	if action := typeKeys(m, "anot-a-uuid\r"); action != uiNone || m.notice == "" {
+		t.Errorf("Expected an invalid ID to be reported, got %q", m.notice)
+	}
+	third := uuid.New()
+	if action := typeKeys(m, "a"+third.String()+"\r"); action != uiSwitchApp || len(m.apps) != 3 || m.app != 2 {
+		t.Errorf("Expected a new application to be added, got %v", m.apps)
+	}
+}
+
+func TestUIModel_BoundsBuffer(t *testing.T) {
+	m := newTestModel(t)
+	m.limit = 10
+	for i := 0; i < 25; i++ {
+		m.add(dto.LogOutput{Message: fmt.Sprint(i), Level: "INFO"})
+	}
+
+	if len(m.logs) > 11 || len(m.view) != len(m.logs) {
+		t.Errorf("Expected the buffer to be bounded, got %d logs and %d in view", len(m.logs), len(m.view))
+	}
+	if l, _ := m.selectedLog(); l.Message != "24" {
+		t.Errorf("Expected the newest log to stay selected, got %q", l.Message)
+	}
+}
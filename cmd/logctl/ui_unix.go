@@ -0,0 +1,202 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sys/unix"
+)
+
+// redrawInterval batches the redraws caused by incoming logs
+const redrawInterval = 50 * time.Millisecond
+
+func runUI(ctx context.Context, args []string) error {
+	fs, a := newFlagSet("ui", "ui --app <id>[,<id>...] [flags]")
+	apps := fs.String("app", os.Getenv("LOGCTL_APPLICATION_ID"), "comma-separated application IDs; tab switches between them")
+	limit := fs.Int("buffer", maxUILogs, "number of logs kept in memory")
+	noColor := fs.Bool("no-color", false, "disable colored levels")
+	var f filter
+	f.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var ids []uuid.UUID
+	for _, raw := range strings.Split(*apps, ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("--app must list application UUIDs, got %q", raw)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return errors.New("--app is required")
+	}
+	m, err := newUIModel(ids, f.String(), *limit, !*noColor && os.Getenv("NO_COLOR") == "")
+	if err != nil {
+		return err
+	}
+
+	t, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer t.restore()
+	m.width, m.height = t.size()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	updates := make(chan uiUpdate, 1024)
+	gen := 0
+	stopStream := func() {}
+	startStream := func() {
+		stopStream()
+		gen++
+		streamCtx, stop := context.WithCancel(ctx)
+		stopStream = stop
+		go streamUpdates(streamCtx, a, m.apps[m.app], gen, updates)
+	}
+	startStream()
+
+	keys := make(chan []byte)
+	go t.readKeys(keys)
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, syscall.SIGWINCH)
+	defer signal.Stop(resized)
+	ticker := time.NewTicker(redrawInterval)
+	defer ticker.Stop()
+
+	t.draw(m.render())
+	dirty := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case u := <-updates:
+			if u.gen != gen {
+				continue
+			}
+			if u.log != nil {
+				m.add(*u.log)
+			}
+			if u.status != "" {
+				m.status = u.status
+			}
+			dirty = true
+		case b, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, k := range decodeKeys(b) {
+				switch m.handleKey(k) {
+				case uiQuit:
+					return nil
+				case uiSwitchApp:
+					startStream()
+				}
+			}
+			t.draw(m.render())
+			dirty = false
+		case <-resized:
+			m.width, m.height = t.size()
+			t.draw(m.render())
+			dirty = false
+		case <-ticker.C:
+			if dirty {
+				t.draw(m.render())
+				dirty = false
+			}
+		}
+	}
+}
+
+// terminal is the controlling terminal in raw mode, showing the alternate screen
+type terminal struct {
+	fd    int
+	saved unix.Termios
+	out   *bufio.Writer
+}
+
+func openTerminal() (*terminal, error) {
+	fd := int(os.Stdin.Fd())
+	saved, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, errors.New("the terminal UI needs an interactive terminal")
+	}
+
+	// Raw mode as in cfmakeraw(3): keys arrive unbuffered and unechoed, and
+	// Ctrl-C is read as a key rather than raising SIGINT
+	raw := *saved
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, fmt.Errorf("failed to switch the terminal to raw mode: %w", err)
+	}
+
+	t := &terminal{fd: fd, saved: *saved, out: bufio.NewWriterSize(os.Stdout, 64*1024)}
+	// Switch to the alternate screen and hide the cursor
+	t.out.WriteString("\x1b[?1049h\x1b[?25l")
+	t.out.Flush()
+	return t, nil
+}
+
+func (t *terminal) restore() {
+	t.out.WriteString("\x1b[?25h\x1b[?1049l")
+	t.out.Flush()
+	unix.IoctlSetTermios(t.fd, ioctlSetTermios, &t.saved)
+}
+
+func (t *terminal) size() (width, height int) {
+	ws, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 {
+		return 80, 24
+	}
+	return int(ws.Col), int(ws.Row)
+}
+
+// draw replaces the screen with lines. Raw mode disables output processing,
+// so lines end with an explicit carriage return.
+func (t *terminal) draw(lines []string) {
+	t.out.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			t.out.WriteString("\r\n")
+		}
+		t.out.WriteString(line)
+		t.out.WriteString("\x1b[K")
+	}
+	t.out.WriteString("\x1b[J")
+	t.out.Flush()
+}
+
+// readKeys sends the bytes of each read from the terminal until it fails
+func (t *terminal) readKeys(keys chan<- []byte) {
+	defer close(keys)
+	buf := make([]byte, 256)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return
+		}
+		keys <- append([]byte(nil), buf[:n]...)
+	}
+}
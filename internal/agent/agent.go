@@ -0,0 +1,337 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// shutdownTimeout bounds the final delivery of entries read before the agent stopped
+const shutdownTimeout = 10 * time.Second
+
+// Entry is a line, or several joined lines, read from a file
+type Entry struct {
+	Path string
+	Line string
+	Time time.Time // When the first line was read
+
+	end    int64
+	source *tailer
+}
+
+// Config describes the files to follow and how their lines are batched
+type Config struct {
+	Paths          []string // Glob patterns, evaluated again on every poll
+	CheckpointFile string
+	PollInterval   time.Duration
+	BatchSize      int
+	BatchWait      time.Duration // Maximum time an entry waits for its batch to fill
+	Multiline      Multiline
+}
+
+// DefaultConfig polls every second and ships up to 500 entries at a time
+func DefaultConfig() Config {
+	return Config{
+		CheckpointFile: "agent-checkpoint.json",
+		PollInterval:   time.Second,
+		BatchSize:      500,
+		BatchWait:      time.Second,
+		Multiline:      DefaultMultiline(),
+	}
+}
+
+// Agent tails files matching glob patterns and ships their lines in batches.
+// Offsets are checkpointed once the lines before them were delivered, so a
+// restarted agent continues where it stopped. Rotated files are followed to
+// their end, truncated files are read again from the start, and a renamed file
+// is recognised by its fingerprint when its new path matches a pattern too.
+type Agent struct {
+	cfg        Config
+	shipper    *Shipper
+	checkpoint *Checkpoint
+
+	tailers map[string]*tailer
+	// known holds the positions of files that are not open: those saved by
+	// the previous run and those left behind by rotations
+	known   map[string]Position
+	skipped map[string]bool
+
+	batch      []Entry
+	batchStart time.Time
+}
+
+func New(cfg Config, shipper *Shipper) (*Agent, error) {
+	if len(cfg.Paths) == 0 {
+		return nil, errors.New("agent: at least one path is required")
+	}
+	for _, pattern := range cfg.Paths {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("agent: invalid path pattern %q: %w", pattern, err)
+		}
+	}
+
+	defaults := DefaultConfig()
+	if cfg.CheckpointFile == "" {
+		cfg.CheckpointFile = defaults.CheckpointFile
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaults.PollInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaults.BatchSize
+	}
+	if cfg.BatchWait <= 0 {
+		cfg.BatchWait = defaults.BatchWait
+	}
+	if cfg.Multiline.MaxLines <= 0 {
+		cfg.Multiline.MaxLines = defaults.Multiline.MaxLines
+	}
+	if cfg.Multiline.MaxWait <= 0 {
+		cfg.Multiline.MaxWait = defaults.Multiline.MaxWait
+	}
+
+	checkpoint := NewCheckpoint(cfg.CheckpointFile)
+	known, err := checkpoint.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Agent{
+		cfg:        cfg,
+		shipper:    shipper,
+		checkpoint: checkpoint,
+		tailers:    make(map[string]*tailer),
+		known:      known,
+		skipped:    make(map[string]bool),
+	}, nil
+}
+
+// Run tails the files until the context is cancelled, then ships what was
+// read and saves the checkpoint. Entries still waiting for continuation lines
+// are read again by the next run.
+func (a *Agent) Run(ctx context.Context) error {
+	defer a.close()
+
+	ticker := time.NewTicker(a.cfg.PollInterval)
+	defer ticker.Stop()
+	for ctx.Err() == nil {
+		a.poll(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	a.ship(shutdownCtx)
+	return a.save()
+}
+
+// poll picks up new files, reads every file to its end and ships full or expired batches
+func (a *Agent) poll(ctx context.Context, now time.Time) {
+	a.discover()
+
+	paths := make([]string, 0, len(a.tailers))
+	for path := range a.tailers {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			return
+		}
+		a.follow(ctx, a.tailers[path], now)
+	}
+
+	if len(a.batch) > 0 && now.Sub(a.batchStart) >= a.cfg.BatchWait {
+		a.ship(ctx)
+	}
+}
+
+func (a *Agent) discover() {
+	for _, pattern := range a.cfg.Paths {
+		matches, _ := filepath.Glob(pattern)
+		for _, path := range matches {
+			if _, ok := a.tailers[path]; ok || a.isOpen(path) {
+				continue
+			}
+			t, err := a.open(path)
+			if err != nil {
+				if !a.skipped[path] {
+					slog.Warn("Skipping file", "path", path, "error", err)
+					a.skipped[path] = true
+				}
+				continue
+			}
+			delete(a.skipped, path)
+			a.tailers[path] = t
+		}
+	}
+}
+
+// isOpen reports whether the file at path is still followed under the name it
+// had before a rotation. It is picked up by its new name once the old one was
+// read to its end.
+func (a *Agent) isOpen(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	for _, t := range a.tailers {
+		if openInfo, err := t.file.Stat(); err == nil && os.SameFile(info, openInfo) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Agent) open(path string) (*tailer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		file.Close()
+		return nil, errors.New("not a regular file")
+	}
+
+	t := newTailer(path, file, a.cfg.Multiline)
+	if pos, ok := a.resume(path, file, info.Size()); ok {
+		if err := t.seek(pos); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// resume finds the saved position of a file, first under its own path and
+// then under the path it had before it was renamed
+func (a *Agent) resume(path string, file *os.File, size int64) (Position, bool) {
+	if pos, ok := a.known[path]; ok && pos.matches(file, size) {
+		delete(a.known, path)
+		return pos, true
+	}
+	for knownPath, pos := range a.known {
+		if pos.matches(file, size) {
+			delete(a.known, knownPath)
+			slog.Info("File was renamed, continuing at its offset", "from", knownPath, "path", path, "offset", pos.Offset)
+			return pos, true
+		}
+	}
+	return Position{}, false
+}
+
+func (a *Agent) follow(ctx context.Context, t *tailer, now time.Time) {
+	emit := func(entry Entry) {
+		entry.Path = t.path
+		entry.source = t
+		if len(a.batch) == 0 {
+			a.batchStart = now
+		}
+		a.batch = append(a.batch, entry)
+	}
+
+	truncated, moved, err := t.status()
+	if err != nil {
+		slog.Error("Failed to stat file", "path", t.path, "error", err)
+		return
+	}
+	if truncated {
+		slog.Info("File was truncated, reading it from the start", "path", t.path)
+		t.joiner.flush(emit)
+		a.ship(ctx)
+		if err := t.seek(Position{}); err != nil {
+			slog.Error("Failed to rewind file", "path", t.path, "error", err)
+			a.retire(t)
+			return
+		}
+	}
+
+	for {
+		lines, err := t.read(a.cfg.BatchSize)
+		for _, l := range lines {
+			t.joiner.add(l, now, emit)
+		}
+		if len(a.batch) >= a.cfg.BatchSize {
+			a.ship(ctx)
+		}
+		if err != nil {
+			slog.Error("Failed to read file", "path", t.path, "error", err)
+			break
+		}
+		if len(lines) == 0 || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if !moved {
+		t.joiner.expire(now, emit)
+		return
+	}
+
+	// The rotated file was read to its end; its path is opened again by the next discovery
+	if l, ok := t.rest(); ok {
+		t.joiner.add(l, now, emit)
+	}
+	t.joiner.flush(emit)
+	a.ship(ctx)
+	a.retire(t)
+}
+
+func (a *Agent) retire(t *tailer) {
+	a.known[t.path] = t.position()
+	t.close()
+	delete(a.tailers, t.path)
+}
+
+// ship delivers the batch and commits the offsets of its entries. Batches the
+// service refused, or that failed after the configured retries, are dropped;
+// a batch interrupted by shutdown is kept uncommitted and read again later.
+func (a *Agent) ship(ctx context.Context) {
+	if len(a.batch) == 0 {
+		return
+	}
+
+	err := a.shipper.Ship(ctx, a.batch)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		slog.Error("Dropping entries", "entries", len(a.batch), "error", err)
+	}
+	for _, entry := range a.batch {
+		entry.source.committed = entry.end
+	}
+	a.batch = a.batch[:0]
+
+	if err := a.save(); err != nil {
+		slog.Error("Failed to save positions", "error", err)
+	}
+}
+
+func (a *Agent) save() error {
+	positions := make(map[string]Position, len(a.tailers))
+	for path, t := range a.tailers {
+		positions[path] = t.position()
+	}
+	return a.checkpoint.Save(positions)
+}
+
+func (a *Agent) close() {
+	for _, t := range a.tailers {
+		t.close()
+	}
+}
@@ -0,0 +1,252 @@
+package agent
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fake push endpoint recording the lines it received by file
+type pushServer struct {
+	mu       sync.Mutex
+	lines    map[string][]string
+	labels   map[string]string
+	headers  http.Header
+	failures int
+}
+
+func newPushServer(t *testing.T) (*pushServer, *httptest.Server) {
+	s := &pushServer{lines: make(map[string][]string)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.failures > 0 {
+			s.failures--
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("Expected gzip body: %v", err)
+			return
+		}
+		var request pushRequest
+		if err := json.NewDecoder(gz).Decode(&request); err != nil {
+			t.Errorf("Invalid push body: %v", err)
+			return
+		}
+		s.headers = r.Header
+		for _, stream := range request.Streams {
+			s.labels = stream.Stream
+			for _, value := range stream.Values {
+				s.lines[stream.Stream["filename"]] = append(s.lines[stream.Stream["filename"]], value[1])
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func (s *pushServer) received(path string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.lines[path]...)
+}
+
+func newTestAgent(t *testing.T, url, dir string, patterns ...string) *Agent {
+	shipper := NewShipper(ShipperConfig{
+		URL:           url,
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+		Hostname:      "web-1",
+		Labels:        map[string]string{"job": "nginx"},
+		MinBackoff:    time.Millisecond,
+	})
+	cfg := DefaultConfig()
+	cfg.Paths = patterns
+	cfg.CheckpointFile = filepath.Join(dir, "checkpoint.json")
+	a, err := New(cfg, shipper)
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	t.Cleanup(a.close)
+	return a
+}
+
+// pollNow reads the files and ships everything read, including entries
+// that are still waiting for continuation lines
+func pollNow(a *Agent) {
+	readAll(a, context.Background())
+	a.ship(context.Background())
+}
+
+// clock advances past MaxWait on every poll so that pending entries expire
+var clock = time.Now()
+
+func readAll(a *Agent, ctx context.Context) {
+	for range 2 {
+		clock = clock.Add(time.Hour)
+		a.poll(ctx, clock)
+	}
+}
+
+func appendFile(t *testing.T, path, content string) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func assertLines(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("Expected lines %q, got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected lines %q, got %q", want, got)
+		}
+	}
+}
+
+func TestAgent_ShipsLinesWithLabels(t *testing.T) {
+	push, server := newPushServer(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, "first\r\nsecond\npartial")
+
+	a := newTestAgent(t, server.URL, dir, filepath.Join(dir, "*.log"))
+	pollNow(a)
+
+	assertLines(t, push.received(path), "first", "second")
+	if push.labels["hostname"] != "web-1" || push.labels["job"] != "nginx" {
+		t.Errorf("Unexpected labels: %v", push.labels)
+	}
+	if push.headers.Get("X-Scope-OrgID") == "" || push.headers.Get("X-User-ID") == "" {
+		t.Errorf("Expected application and user headers, got %v", push.headers)
+	}
+
+	appendFile(t, path, " line\n")
+	pollNow(a)
+	assertLines(t, push.received(path), "first", "second", "partial line")
+}
+
+func TestAgent_ResumesFromCheckpoint(t *testing.T) {
+	push, server := newPushServer(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, "before restart\n")
+
+	a := newTestAgent(t, server.URL, dir, path)
+	pollNow(a)
+	a.close()
+
+	appendFile(t, path, "after restart\n")
+	a = newTestAgent(t, server.URL, dir, path)
+	pollNow(a)
+
+	assertLines(t, push.received(path), "before restart", "after restart")
+}
+
+func TestAgent_FollowsRotation(t *testing.T) {
+	push, server := newPushServer(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	rotated := filepath.Join(dir, "app.log.1")
+	appendFile(t, path, "old 1\n")
+
+	a := newTestAgent(t, server.URL, dir, filepath.Join(dir, "app.log*"))
+	pollNow(a)
+
+	appendFile(t, path, "old 2\n")
+	if err := os.Rename(path, rotated); err != nil {
+		t.Fatalf("Failed to rotate: %v", err)
+	}
+	appendFile(t, path, "new 1\n")
+	pollNow(a)
+	pollNow(a)
+
+	assertLines(t, push.received(path), "old 1", "old 2", "new 1")
+	if lines := push.received(rotated); len(lines) != 0 {
+		t.Errorf("Expected the rotated file not to be shipped again, got %q", lines)
+	}
+}
+
+func TestAgent_RereadsTruncatedFile(t *testing.T) {
+	push, server := newPushServer(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, "a long line before truncation\n")
+
+	a := newTestAgent(t, server.URL, dir, path)
+	pollNow(a)
+
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatalf("Failed to truncate: %v", err)
+	}
+	appendFile(t, path, "short\n")
+	pollNow(a)
+
+	assertLines(t, push.received(path), "a long line before truncation", "short")
+}
+
+func TestAgent_RetriesThrottledBatches(t *testing.T) {
+	push, server := newPushServer(t)
+	push.failures = 2
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, "eventually delivered\n")
+
+	a := newTestAgent(t, server.URL, dir, path)
+	pollNow(a)
+
+	assertLines(t, push.received(path), "eventually delivered")
+	positions, err := a.checkpoint.Load()
+	if err != nil {
+		t.Fatalf("Failed to load checkpoint: %v", err)
+	}
+	if positions[path].Offset != int64(len("eventually delivered\n")) {
+		t.Errorf("Expected the offset to be committed, got %+v", positions[path])
+	}
+}
+
+func TestAgent_KeepsBatchWhenStopped(t *testing.T) {
+	push, server := newPushServer(t)
+	push.failures = 1000
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, "not delivered\n")
+
+	a := newTestAgent(t, server.URL, dir, path)
+	readAll(a, context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	a.ship(ctx)
+
+	if len(a.batch) != 1 {
+		t.Errorf("Expected the batch to be kept, got %d entries", len(a.batch))
+	}
+	if err := a.save(); err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+	positions, _ := a.checkpoint.Load()
+	if positions[path].Offset != 0 {
+		t.Errorf("Expected no committed offset, got %+v", positions[path])
+	}
+}
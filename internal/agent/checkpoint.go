@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// fingerprintBytes is how much of the start of a file identifies it across renames
+const fingerprintBytes = 1024
+
+// Position is the shipped offset of a file. The fingerprint hashes the first
+// FingerprintSize bytes so that the offset is only reused for the same content,
+// whether the file kept its path or was renamed by a rotation.
+type Position struct {
+	Offset          int64  `json:"offset"`
+	Fingerprint     string `json:"fingerprint,omitempty"`
+	FingerprintSize int64  `json:"fingerprint_size,omitempty"`
+}
+
+// Checkpoint persists the positions of tailed files between runs
+type Checkpoint struct {
+	path string
+}
+
+func NewCheckpoint(path string) *Checkpoint {
+	return &Checkpoint{path: path}
+}
+
+// Load reads the saved positions by file path; a missing file means a first run
+func (c *Checkpoint) Load() (map[string]Position, error) {
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Position{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("agent: failed to read checkpoint: %w", err)
+	}
+
+	positions := map[string]Position{}
+	if err := json.Unmarshal(data, &positions); err != nil {
+		return nil, fmt.Errorf("agent: invalid checkpoint %s: %w", c.path, err)
+	}
+	return positions, nil
+}
+
+// Save atomically replaces the checkpoint file
+func (c *Checkpoint) Save(positions map[string]Position) error {
+	data, err := json.MarshalIndent(positions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("agent: failed to encode checkpoint: %w", err)
+	}
+
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("agent: failed to create checkpoint directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(c.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("agent: failed to write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("agent: failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("agent: failed to sync checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("agent: failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("agent: failed to replace checkpoint: %w", err)
+	}
+	return nil
+}
+
+// fingerprint hashes the first size bytes of a file
+func fingerprint(r io.ReaderAt, size int64) (string, error) {
+	buf := make([]byte, size)
+	if _, err := r.ReadAt(buf, 0); err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(buf)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// matches reports whether the position was recorded for the content of this file
+func (p Position) matches(r io.ReaderAt, size int64) bool {
+	if p.FingerprintSize == 0 || size < p.Offset || size < p.FingerprintSize {
+		return false
+	}
+	sum, err := fingerprint(r, p.FingerprintSize)
+	return err == nil && sum == p.Fingerprint
+}
@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultContinuation matches indented lines and chained causes, as found in Java
+// and Python stack traces
+var DefaultContinuation = regexp.MustCompile(`^(\s|Caused by:)`)
+
+// Multiline describes how lines belonging to one entry, such as a stack trace, are joined
+type Multiline struct {
+	// FirstLine matches the first line of every entry; any other line continues the previous entry
+	FirstLine *regexp.Regexp
+	// Continuation matches lines that continue the previous entry, used when FirstLine is nil
+	Continuation *regexp.Regexp
+	// MaxLines cuts entries that grow longer; 1 disables joining
+	MaxLines int
+	// MaxWait ships a pending entry when no further line arrived for this long
+	MaxWait time.Duration
+}
+
+// DefaultMultiline joins indented lines into the line before them
+func DefaultMultiline() Multiline {
+	return Multiline{
+		Continuation: DefaultContinuation,
+		MaxLines:     500,
+		MaxWait:      3 * time.Second,
+	}
+}
+
+// joiner accumulates the lines of one file into entries
+type joiner struct {
+	cfg     Multiline
+	lines   []string
+	end     int64
+	started time.Time
+	updated time.Time
+}
+
+func newJoiner(cfg Multiline) *joiner {
+	return &joiner{cfg: cfg}
+}
+
+// add appends a line and emits the entries it completes
+func (j *joiner) add(l line, now time.Time, emit func(Entry)) {
+	if len(j.lines) > 0 && !j.continues(l.text) {
+		emit(j.take())
+	}
+	if len(j.lines) == 0 {
+		j.started = now
+	}
+	j.lines = append(j.lines, l.text)
+	j.end = l.end
+	j.updated = now
+
+	if len(j.lines) >= j.cfg.MaxLines {
+		emit(j.take())
+	}
+}
+
+// expire emits the pending entry once no line followed it for MaxWait
+func (j *joiner) expire(now time.Time, emit func(Entry)) {
+	if len(j.lines) > 0 && now.Sub(j.updated) >= j.cfg.MaxWait {
+		emit(j.take())
+	}
+}
+
+// flush emits the pending entry, when the file will not grow any further
+func (j *joiner) flush(emit func(Entry)) {
+	if len(j.lines) > 0 {
+		emit(j.take())
+	}
+}
+
+func (j *joiner) continues(text string) bool {
+	if j.cfg.FirstLine != nil {
+		return !j.cfg.FirstLine.MatchString(text)
+	}
+	return j.cfg.Continuation != nil && j.cfg.Continuation.MatchString(text)
+}
+
+func (j *joiner) take() Entry {
+	entry := Entry{
+		Line: strings.Join(j.lines, "\n"),
+		Time: j.started,
+		end:  j.end,
+	}
+	j.lines = j.lines[:0]
+	return entry
+}
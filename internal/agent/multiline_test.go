@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func joinLines(cfg Multiline, texts ...string) []Entry {
+	j := newJoiner(cfg)
+	var entries []Entry
+	emit := func(e Entry) { entries = append(entries, e) }
+	now := time.Now()
+	for i, text := range texts {
+		j.add(line{text: text, end: int64(i + 1)}, now, emit)
+	}
+	j.flush(emit)
+	return entries
+}
+
+func TestJoiner_DefaultContinuation(t *testing.T) {
+	entries := joinLines(DefaultMultiline(),
+		"ERROR request failed",
+		"java.lang.IllegalStateException: boom",
+		"\tat com.example.Service.run(Service.java:42)",
+		"Caused by: java.io.IOException: closed",
+		"\t... 3 more",
+		"INFO next request",
+	)
+
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %+v", len(entries), entries)
+	}
+	if entries[1].Line != "java.lang.IllegalStateException: boom\n\tat com.example.Service.run(Service.java:42)\nCaused by: java.io.IOException: closed\n\t... 3 more" {
+		t.Errorf("Unexpected joined entry: %q", entries[1].Line)
+	}
+	if entries[1].end != 5 {
+		t.Errorf("Expected entry to end after line 5, got %d", entries[1].end)
+	}
+}
+
+func TestJoiner_FirstLine(t *testing.T) {
+	cfg := DefaultMultiline()
+	cfg.FirstLine = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`)
+
+	entries := joinLines(cfg,
+		"2025-10-28 10:00:00 panic: runtime error",
+		"goroutine 1 [running]:",
+		"main.main()",
+		"2025-10-28 10:00:01 restarted",
+	)
+
+	if len(entries) != 2 || entries[0].Line != "2025-10-28 10:00:00 panic: runtime error\ngoroutine 1 [running]:\nmain.main()" {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+}
+
+func TestJoiner_MaxLines(t *testing.T) {
+	cfg := DefaultMultiline()
+	cfg.MaxLines = 2
+
+	entries := joinLines(cfg, "first", " a", " b", " c")
+	if len(entries) != 2 || entries[0].Line != "first\n a" || entries[1].Line != " b\n c" {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+
+	cfg.MaxLines = 1
+	if entries := joinLines(cfg, "first", " a"); len(entries) != 2 {
+		t.Errorf("Expected joining to be disabled, got %+v", entries)
+	}
+}
+
+func TestJoiner_Expire(t *testing.T) {
+	j := newJoiner(DefaultMultiline())
+	var entries []Entry
+	emit := func(e Entry) { entries = append(entries, e) }
+	start := time.Now()
+
+	j.add(line{text: "waiting for more", end: 17}, start, emit)
+	j.expire(start.Add(time.Second), emit)
+	if len(entries) != 0 {
+		t.Fatal("Expected the entry to wait for continuation lines")
+	}
+
+	j.expire(start.Add(3*time.Second), emit)
+	if len(entries) != 1 || entries[0].Line != "waiting for more" {
+		t.Errorf("Expected the entry after MaxWait, got %+v", entries)
+	}
+}
@@ -0,0 +1,199 @@
+package agent
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// pushPath is the service's Loki-compatible push endpoint, which accepts labelled batches
+const pushPath = "/loki/api/v1/push"
+
+// ErrRejected is returned when the service refuses a batch in a way that retrying cannot fix
+var ErrRejected = errors.New("batch rejected")
+
+// ShipperConfig describes where batches are sent and how failed requests are retried
+type ShipperConfig struct {
+	URL           string // Base URL of the service, such as http://localhost:8080
+	ApplicationID uuid.UUID
+	UserID        uuid.UUID
+	Hostname      string
+	Labels        map[string]string // Static labels, stored as tags on every entry
+	Timeout       time.Duration     // Per request
+	MinBackoff    time.Duration
+	MaxBackoff    time.Duration
+	MaxRetries    int // 0 keeps retrying until the batch is delivered or the agent stops
+	// TLS sets the CA trusted for the server and the client certificate sent for mutual TLS
+	TLS *tls.Config
+}
+
+// DefaultShipperConfig returns the request timeout and backoff bounds
+func DefaultShipperConfig() ShipperConfig {
+	return ShipperConfig{
+		Timeout:    10 * time.Second,
+		MinBackoff: 500 * time.Millisecond,
+		MaxBackoff: 30 * time.Second,
+	}
+}
+
+// Shipper sends batches of entries to the service. Each stream is labelled
+// with the hostname and file path, which the service stores as tags.
+type Shipper struct {
+	cfg    ShipperConfig
+	client *http.Client
+}
+
+func NewShipper(cfg ShipperConfig) *Shipper {
+	defaults := DefaultShipperConfig()
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaults.MinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = max(defaults.MaxBackoff, cfg.MinBackoff)
+	}
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+
+	client := &http.Client{Timeout: cfg.Timeout}
+	if cfg.TLS != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = cfg.TLS
+		client.Transport = transport
+	}
+	return &Shipper{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+type pushRequest struct {
+	Streams []pushStream `json:"streams"`
+}
+
+type pushStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// Ship delivers a batch, retrying network errors, 429 and 5xx answers with
+// exponential backoff. The same body is sent on every attempt so that the
+// service recognises entries it already stored.
+func (s *Shipper) Ship(ctx context.Context, entries []Entry) error {
+	body, err := s.encode(entries)
+	if err != nil {
+		return err
+	}
+
+	backoff := s.cfg.MinBackoff
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := s.send(ctx, body)
+		if err == nil || errors.Is(err, ErrRejected) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if s.cfg.MaxRetries > 0 && attempt > s.cfg.MaxRetries {
+			return fmt.Errorf("agent: giving up after %d attempts: %w", attempt, err)
+		}
+
+		wait := max(backoff, retryAfter)
+		slog.Warn("Failed to ship entries, retrying", "entries", len(entries), "retry_in", wait, "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, s.cfg.MaxBackoff)
+	}
+}
+
+func (s *Shipper) encode(entries []Entry) ([]byte, error) {
+	request := pushRequest{}
+	streams := make(map[string]int)
+	for _, entry := range entries {
+		i, ok := streams[entry.Path]
+		if !ok {
+			i = len(request.Streams)
+			streams[entry.Path] = i
+			request.Streams = append(request.Streams, pushStream{Stream: s.labels(entry.Path)})
+		}
+		request.Streams[i].Values = append(request.Streams[i].Values, [2]string{
+			strconv.FormatInt(entry.Time.UnixNano(), 10),
+			entry.Line,
+		})
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(request); err != nil {
+		return nil, fmt.Errorf("agent: failed to encode batch: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("agent: failed to compress batch: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Shipper) labels(path string) map[string]string {
+	labels := make(map[string]string, len(s.cfg.Labels)+2)
+	for name, value := range s.cfg.Labels {
+		labels[name] = value
+	}
+	if s.cfg.Hostname != "" {
+		labels["hostname"] = s.cfg.Hostname
+	}
+	labels["filename"] = path
+	return labels
+}
+
+// send makes one attempt, returning the delay the service asked for when it is throttling
+func (s *Shipper) send(ctx context.Context, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL+pushPath, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("X-Scope-OrgID", s.cfg.ApplicationID.String())
+	req.Header.Set("X-User-ID", s.cfg.UserID.String())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return retryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("server answered %s: %s", resp.Status, bytes.TrimSpace(message))
+	default:
+		return 0, fmt.Errorf("%w: server answered %s: %s", ErrRejected, resp.Status, bytes.TrimSpace(message))
+	}
+}
+
+// retryAfter reads a Retry-After header given in seconds
+func retryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// maxLineBytes splits longer lines so that a file without newlines cannot exhaust memory
+	maxLineBytes = 256 << 10
+	readChunk    = 64 << 10
+)
+
+// line is a complete line and the file offset just past it
+type line struct {
+	text string
+	end  int64
+}
+
+// tailer follows the file currently open at a path
+type tailer struct {
+	path   string
+	file   *os.File
+	joiner *joiner
+
+	offset    int64  // Just past the last line returned by read
+	buf       []byte // Bytes read past offset that do not form a line yet
+	chunk     []byte
+	committed int64 // Just past the last shipped entry
+
+	fingerprint     string
+	fingerprintSize int64
+}
+
+func newTailer(path string, file *os.File, multiline Multiline) *tailer {
+	return &tailer{
+		path:   path,
+		file:   file,
+		joiner: newJoiner(multiline),
+		chunk:  make([]byte, readChunk),
+	}
+}
+
+// seek resumes reading at a saved position
+func (t *tailer) seek(pos Position) error {
+	if _, err := t.file.Seek(pos.Offset, io.SeekStart); err != nil {
+		return err
+	}
+	t.offset, t.committed = pos.Offset, pos.Offset
+	t.buf = t.buf[:0]
+	t.fingerprint, t.fingerprintSize = pos.Fingerprint, pos.FingerprintSize
+	return nil
+}
+
+// read returns up to max complete lines. A trailing line without a newline is
+// kept until the rest of it is written.
+func (t *tailer) read(max int) ([]line, error) {
+	var lines []line
+	for len(lines) < max {
+		if i := bytes.IndexByte(t.buf, '\n'); i >= 0 {
+			lines = append(lines, t.take(i, i+1))
+			continue
+		}
+		if len(t.buf) >= maxLineBytes {
+			lines = append(lines, t.take(maxLineBytes, maxLineBytes))
+			continue
+		}
+
+		n, err := t.file.Read(t.chunk)
+		t.buf = append(t.buf, t.chunk[:n]...)
+		if errors.Is(err, io.EOF) || (err == nil && n == 0) {
+			break
+		}
+		if err != nil {
+			return lines, err
+		}
+	}
+	return lines, nil
+}
+
+// rest returns the trailing line without a newline, once the file will not grow any further
+func (t *tailer) rest() (line, bool) {
+	if len(t.buf) == 0 {
+		return line{}, false
+	}
+	return t.take(len(t.buf), len(t.buf)), true
+}
+
+func (t *tailer) take(n, consumed int) line {
+	text := strings.TrimSuffix(string(t.buf[:n]), "\r")
+	t.buf = t.buf[consumed:]
+	t.offset += int64(consumed)
+	return line{text: text, end: t.offset}
+}
+
+// status reports whether the file was truncated, and whether the path was
+// removed or now points to a different file
+func (t *tailer) status() (truncated, moved bool, err error) {
+	info, err := t.file.Stat()
+	if err != nil {
+		return false, false, err
+	}
+	truncated = info.Size() < t.offset+int64(len(t.buf))
+
+	current, err := os.Stat(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return truncated, true, nil
+	}
+	if err != nil {
+		return truncated, false, err
+	}
+	return truncated, !os.SameFile(info, current), nil
+}
+
+// position returns the shipped offset, fingerprinting the start of the file once enough of it was read
+func (t *tailer) position() Position {
+	if t.fingerprintSize < fingerprintBytes && t.committed > t.fingerprintSize {
+		size := min(t.committed, fingerprintBytes)
+		if sum, err := fingerprint(t.file, size); err == nil {
+			t.fingerprint, t.fingerprintSize = sum, size
+		}
+	}
+	return Position{
+		Offset:          t.committed,
+		Fingerprint:     t.fingerprint,
+		FingerprintSize: t.fingerprintSize,
+	}
+}
+
+func (t *tailer) close() error {
+	return t.file.Close()
+}
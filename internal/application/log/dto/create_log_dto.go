@@ -1,8 +1,13 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type CreateLogInput struct {
+	ID            *uuid.UUID             `json:"id,omitempty"` // Optional: client-generated ID, makes retries idempotent
 	ApplicationID uuid.UUID              `json:"application_id"`
 	UserID        uuid.UUID              `json:"user_id"`
 	Message       string                 `json:"message"`
@@ -10,6 +15,10 @@ type CreateLogInput struct {
 	Source        string                 `json:"source,omitempty"`
 	Tags          map[string]string      `json:"tags,omitempty"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Timestamp     *time.Time             `json:"timestamp,omitempty"` // Optional: event time (RFC3339 with nanoseconds), defaults to ingestion time
+
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-"`
 }
 
 type CreateLogOutput struct {
@@ -22,6 +31,12 @@ type CreateLogOutput struct {
 	Tags          map[string]string      `json:"tags,omitempty"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 	Timestamp     string                 `json:"timestamp"`
+	IngestedAt    string                 `json:"ingested_at"`
+
+	// Queued reports that the log was accepted for asynchronous persistence
+	Queued bool `json:"-"`
+	// Replayed reports that the output belongs to a log created by an earlier request
+	Replayed bool `json:"-"`
 }
 
 type LogOutput struct {
@@ -34,4 +49,5 @@ type LogOutput struct {
 	Tags          map[string]string      `json:"tags,omitempty"`
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 	Timestamp     string                 `json:"timestamp"`
+	IngestedAt    string                 `json:"ingested_at"`
 }
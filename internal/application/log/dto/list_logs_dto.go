@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ListLogsInput struct {
+	ApplicationID uuid.UUID
+	From          *time.Time
+	To            *time.Time
+	Levels        []string
+	MinLevel      string
+	Source        string
+	Tags          map[string]string
+	Search        string
+	TimeField     string // "timestamp" (event time, default) or "ingested_at"
+	Order         string // "desc" (default) or "asc"
+	Limit         int
+	Offset        int
+}
+
+type ListLogsOutput struct {
+	Logs      []LogOutput `json:"logs"`
+	TimeField string      `json:"time_field"`
+	Order     string      `json:"order"`
+	Limit     int         `json:"limit"`
+	Offset    int         `json:"offset"`
+	HasMore   bool        `json:"has_more"`
+}
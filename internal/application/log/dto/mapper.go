@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
 	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
 )
@@ -20,6 +21,9 @@ func ToDomainLog(input CreateLogInput) (*log.Log, error) {
 	}
 
 	// Set optional fields
+	if input.ID != nil && *input.ID != (uuid.UUID{}) {
+		domainLog.ID = *input.ID
+	}
 	if input.Source != "" {
 		domainLog.Source = input.Source
 	}
@@ -29,6 +33,9 @@ func ToDomainLog(input CreateLogInput) (*log.Log, error) {
 	if input.Metadata != nil {
 		domainLog.Metadata = input.Metadata
 	}
+	if input.Timestamp != nil && !input.Timestamp.IsZero() {
+		domainLog.Timestamp = *input.Timestamp
+	}
 
 	return domainLog, nil
 }
@@ -44,7 +51,8 @@ func LogToCreateLogOutput(l *log.Log) CreateLogOutput {
 		Source:        l.Source,
 		Tags:          l.Tags,
 		Metadata:      l.Metadata,
-		Timestamp:     l.Timestamp.Format(time.RFC3339),
+		Timestamp:     l.Timestamp.Format(time.RFC3339Nano),
+		IngestedAt:    l.IngestedAt.Format(time.RFC3339Nano),
 	}
 }
 
@@ -59,6 +67,71 @@ func LogToLogOutput(l *log.Log) LogOutput {
 		Source:        l.Source,
 		Tags:          l.Tags,
 		Metadata:      l.Metadata,
-		Timestamp:     l.Timestamp.Format(time.RFC3339),
+		Timestamp:     l.Timestamp.Format(time.RFC3339Nano),
+		IngestedAt:    l.IngestedAt.Format(time.RFC3339Nano),
+	}
+}
+
+// ToDomainQuery converts ListLogsInput DTO to a domain query
+func ToDomainQuery(input ListLogsInput) (log.Query, error) {
+	query := log.Query{
+		ApplicationID: input.ApplicationID,
+		Source:        input.Source,
+		Tags:          input.Tags,
+		Search:        input.Search,
+		TimeField:     log.TimeField(input.TimeField),
+		Descending:    input.Order != "asc",
+		Limit:         input.Limit,
+		Offset:        input.Offset,
+	}
+
+	if input.Order != "" && input.Order != "asc" && input.Order != "desc" {
+		return log.Query{}, log.ErrInvalidSortOrder
+	}
+	if input.From != nil {
+		query.From = *input.From
+	}
+	if input.To != nil {
+		query.To = *input.To
+	}
+
+	for _, raw := range input.Levels {
+		level, err := valueobjects.NewLogLevel(raw)
+		if err != nil {
+			return log.Query{}, err
+		}
+		query.Levels = append(query.Levels, level)
+	}
+
+	// A minimum level expands to every level at least as severe
+	if input.MinLevel != "" {
+		minLevel, err := valueobjects.NewLogLevel(input.MinLevel)
+		if err != nil {
+			return log.Query{}, err
+		}
+		var levels []valueobjects.LogLevel
+		for _, level := range valueobjects.ValidLogLevels() {
+			if level.Priority() >= minLevel.Priority() && (len(query.Levels) == 0 || containsLevel(query.Levels, level)) {
+				levels = append(levels, level)
+			}
+		}
+		if len(levels) == 0 {
+			return log.Query{}, log.ErrLevelRequired
+		}
+		query.Levels = levels
+	}
+
+	if err := query.Validate(); err != nil {
+		return log.Query{}, err
+	}
+	return query, nil
+}
+
+func containsLevel(levels []valueobjects.LogLevel, level valueobjects.LogLevel) bool {
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
 	}
+	return false
 }
@@ -331,3 +331,98 @@ func TestToDomainLog_EmptyOptionalFields(t *testing.T) {
 		t.Errorf("Expected initialized Metadata map, got nil")
 	}
 }
+
+func TestToDomainLog_ClientSuppliedID(t *testing.T) {
+	clientID := uuid.New()
+
+	input := CreateLogInput{
+		ID:            &clientID,
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+		Message:       "Test message",
+		Level:         "INFO",
+	}
+
+	result, err := ToDomainLog(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.ID != clientID {
+		t.Errorf("Expected client-supplied ID %s, got %s", clientID, result.ID)
+	}
+
+	// A zero ID is ignored and a fresh one is generated
+	zero := uuid.UUID{}
+	input.ID = &zero
+	result, err = ToDomainLog(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.ID == (uuid.UUID{}) {
+		t.Error("Expected generated ID for zero client ID")
+	}
+}
+
+func TestToDomainLog_ClientSuppliedTimestamp(t *testing.T) {
+	eventTime := time.Date(2025, 10, 28, 10, 0, 0, 123456789, time.UTC)
+
+	input := CreateLogInput{
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+		Message:       "Test message",
+		Level:         "INFO",
+		Timestamp:     &eventTime,
+	}
+
+	result, err := ToDomainLog(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.Timestamp.Equal(eventTime) {
+		t.Errorf("Expected event timestamp %v, got %v", eventTime, result.Timestamp)
+	}
+	if result.IngestedAt.Equal(eventTime) {
+		t.Error("Expected ingestion time to be set by the server")
+	}
+
+	output := LogToLogOutput(result)
+	if output.Timestamp != "2025-10-28T10:00:00.123456789Z" {
+		t.Errorf("Expected nanosecond precision timestamp, got %s", output.Timestamp)
+	}
+}
+
+func TestToDomainQuery_MinLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    ListLogsInput
+		expected []valueobjects.LogLevel
+	}{
+		{
+			name:     "Min level expands to higher levels",
+			input:    ListLogsInput{ApplicationID: uuid.New(), MinLevel: "error"},
+			expected: []valueobjects.LogLevel{valueobjects.LogLevelError, valueobjects.LogLevelFatal},
+		},
+		{
+			name:     "Min level narrows explicit levels",
+			input:    ListLogsInput{ApplicationID: uuid.New(), Levels: []string{"debug", "warn"}, MinLevel: "info"},
+			expected: []valueobjects.LogLevel{valueobjects.LogLevelWarn},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := ToDomainQuery(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(query.Levels) != len(tt.expected) {
+				t.Fatalf("Expected levels %v, got %v", tt.expected, query.Levels)
+			}
+			for i, level := range tt.expected {
+				if query.Levels[i] != level {
+					t.Errorf("Expected level %s at %d, got %s", level, i, query.Levels[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/pipeline"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+)
+
+// CreateErrorKind tells clients how to react to a CreateLog error
+type CreateErrorKind int
+
+const (
+	CreateErrorInternal CreateErrorKind = iota
+	CreateErrorInvalid
+	CreateErrorForbidden
+	CreateErrorDuplicate
+	CreateErrorRateLimited
+	CreateErrorUnavailable
+)
+
+// CreateError describes a CreateLog error independently of the transport, so
+// that every ingestion API answers the same failure with the same message
+type CreateError struct {
+	Kind CreateErrorKind
+	// Message is safe to return to clients
+	Message string
+	// RetryAfter is how long clients should wait before retrying a rate
+	// limited or unavailable entry
+	RetryAfter time.Duration
+	// Exceeded is set for rate limit errors
+	Exceeded *ratelimit.ExceededError
+}
+
+// Retryable reports whether the same entry may succeed when sent again later
+func (e CreateError) Retryable() bool {
+	return e.Kind == CreateErrorRateLimited || e.Kind == CreateErrorUnavailable
+}
+
+// RetryAfterSeconds rounds RetryAfter up to whole seconds, at least 1, for
+// the Retry-After header; it is 0 when the error is not retryable
+func (e CreateError) RetryAfterSeconds() int64 {
+	if !e.Retryable() {
+		return 0
+	}
+	return max(int64(math.Ceil(e.RetryAfter.Seconds())), 1)
+}
+
+// ClassifyCreateError maps an error returned by CreateLog to its CreateError
+func ClassifyCreateError(err error) CreateError {
+	var exceeded *ratelimit.ExceededError
+	switch {
+	case errors.As(err, &exceeded):
+		return CreateError{
+			Kind:       CreateErrorRateLimited,
+			Message:    "Rate limit exceeded for this application.",
+			RetryAfter: exceeded.RetryAfter,
+			Exceeded:   exceeded,
+		}
+	case errors.Is(err, pipeline.ErrQueueFull), errors.Is(err, pipeline.ErrClosed):
+		return CreateError{Kind: CreateErrorUnavailable, Message: "The ingestion queue is unavailable, please retry.", RetryAfter: time.Second}
+	case errors.Is(err, log.ErrApplicationForbidden):
+		return CreateError{Kind: CreateErrorForbidden, Message: "The client certificate is not authorized for this application."}
+	case errors.Is(err, log.ErrTimestampOutOfRange):
+		return CreateError{Kind: CreateErrorInvalid, Message: "Log timestamp is outside the accepted clock skew."}
+	case errors.Is(err, log.ErrIdempotencyKeyLength):
+		return CreateError{Kind: CreateErrorInvalid, Message: "Idempotency-Key must be at most 255 characters."}
+	case errors.Is(err, log.ErrDuplicateLog):
+		return CreateError{Kind: CreateErrorDuplicate, Message: "A log with this ID already exists."}
+	case errors.Is(err, ErrInvalidLog):
+		return CreateError{Kind: CreateErrorInvalid, Message: err.Error()}
+	default:
+		return CreateError{Kind: CreateErrorInternal, Message: "An internal error occurred while creating the log."}
+	}
+}
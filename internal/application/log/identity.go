@@ -0,0 +1,22 @@
+package log
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type boundApplicationKey struct{}
+
+// BindApplication restricts the logs created with ctx to one application,
+// typically the one an authenticated client, such as a TLS client
+// certificate, is allowed to ingest for
+func BindApplication(ctx context.Context, applicationID uuid.UUID) context.Context {
+	return context.WithValue(ctx, boundApplicationKey{}, applicationID)
+}
+
+// BoundApplication returns the application ctx is restricted to, if any
+func BoundApplication(ctx context.Context) (uuid.UUID, bool) {
+	applicationID, ok := ctx.Value(boundApplicationKey{}).(uuid.UUID)
+	return applicationID, ok
+}
@@ -0,0 +1,20 @@
+package log
+
+import "context"
+
+// RequestIDField is the metadata field that records the request a log was
+// ingested with, so that it can be correlated with the service's own logs
+const RequestIDField = "request_id"
+
+type requestIDKey struct{}
+
+// WithRequestID attaches the ID of the request being served to ctx
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the ID of the request ctx belongs to, if any
+func RequestID(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok && requestID != ""
+}
@@ -3,14 +3,34 @@ package log
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
 	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+// reserveTimeout bounds idempotency key reservations, so that an unreachable
+// key store does not hold up ingestion while logs are spooled
+const reserveTimeout = 2 * time.Second
+
+var (
+	// ErrInvalidLog wraps every validation failure of CreateLog
+	ErrInvalidLog = errors.New("invalid log data")
+	// ErrInvalidQuery wraps every validation failure of ListLogs
+	ErrInvalidQuery = errors.New("invalid query")
 )
 
 type LogUsecaseInterface interface {
 	CreateLog(ctx context.Context, input dto.CreateLogInput) (*dto.CreateLogOutput, error)
+	ListLogs(ctx context.Context, input dto.ListLogsInput) (*dto.ListLogsOutput, error)
 }
 
 // SSEPublisher interface for SSE server abstraction
@@ -19,37 +39,380 @@ type SSEPublisher interface {
 	Publish(channel string, data []byte)
 }
 
+// RateLimiter interface for per-application ingestion limits
+type RateLimiter interface {
+	Allow(applicationID string, size int) error
+	// Refund returns the quota of an allowed entry that was not stored
+	Refund(applicationID string)
+}
+
+// LogQueue interface for asynchronous write-behind ingestion
+type LogQueue interface {
+	Enqueue(l *log.Log) error
+	OnFlushed(fn func([]*log.Log))
+	OnFailed(fn func([]*log.Log))
+}
+
+// Metrics interface for ingestion instrumentation, such as Prometheus counters
+type Metrics interface {
+	LogIngested(applicationID, level string)
+	LogRejected(reason string)
+}
+
 type LogUsecase struct {
-	repo   log.LogRepository
-	sseSrv SSEPublisher
+	repo        log.LogRepository
+	sseSrv      SSEPublisher
+	publishers  []SSEPublisher
+	limiter     RateLimiter
+	queue       LogQueue
+	idempotency log.IdempotencyStore
+	// pendingKeys maps queued log IDs to their reserved idempotency keys
+	// until the batch is flushed, so that keys of failed batches are released
+	pendingKeys    sync.Map
+	reserveTimeout time.Duration
+	timestamps     atomic.Pointer[log.TimestampPolicy]
+	orderBy        log.TimeField
+	metrics        Metrics
+}
+
+// Option configures optional LogUsecase collaborators
+type Option func(*LogUsecase)
+
+// WithRateLimiter enforces ingestion limits before logs reach the repository
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(uc *LogUsecase) {
+		uc.limiter = limiter
+	}
+}
+
+// WithQueue switches the usecase to write-behind mode: validated logs are
+// enqueued and published to SSE only once their batch has been persisted.
+func WithQueue(queue LogQueue) Option {
+	return func(uc *LogUsecase) {
+		uc.queue = queue
+		queue.OnFlushed(uc.flushed)
+		queue.OnFailed(uc.failed)
+	}
+}
+
+// WithIdempotencyStore deduplicates retried requests that carry an idempotency key or client-supplied ID
+func WithIdempotencyStore(store log.IdempotencyStore) Option {
+	return func(uc *LogUsecase) {
+		uc.idempotency = store
+	}
+}
+
+// WithTimestampPolicy bounds the clock skew accepted for client-supplied event timestamps
+func WithTimestampPolicy(policy log.TimestampPolicy) Option {
+	return func(uc *LogUsecase) {
+		uc.SetTimestampPolicy(policy)
+	}
+}
+
+// WithPublishOrder sorts each batch flushed by the queue by the given time
+// field before it is published to SSE. Logs are not reordered across batches,
+// and logs created without a queue are published one at a time, unsorted.
+func WithPublishOrder(field log.TimeField) Option {
+	return func(uc *LogUsecase) {
+		uc.orderBy = field
+	}
+}
+
+// WithPublisher notifies an additional subscriber channel, such as gRPC tail
+// streams, about persisted logs alongside the SSE server
+func WithPublisher(publisher SSEPublisher) Option {
+	return func(uc *LogUsecase) {
+		uc.publishers = append(uc.publishers, publisher)
+	}
+}
+
+// WithMetrics reports accepted logs and rejected entries by reason
+func WithMetrics(metrics Metrics) Option {
+	return func(uc *LogUsecase) {
+		uc.metrics = metrics
+	}
 }
 
 // NewLogUsecase creates a new LogUsecase. Optionally pass an SSE server for real-time notifications.
-func NewLogUsecase(repo log.LogRepository, sseSrv SSEPublisher) *LogUsecase {
-	return &LogUsecase{repo: repo, sseSrv: sseSrv}
+func NewLogUsecase(repo log.LogRepository, sseSrv SSEPublisher, opts ...Option) *LogUsecase {
+	uc := &LogUsecase{repo: repo, sseSrv: sseSrv, orderBy: log.TimeFieldIngestedAt, reserveTimeout: reserveTimeout}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// SetTimestampPolicy replaces the accepted clock skew at runtime
+func (uc *LogUsecase) SetTimestampPolicy(policy log.TimestampPolicy) {
+	uc.timestamps.Store(&policy)
+}
+
+func (uc *LogUsecase) timestampPolicy() log.TimestampPolicy {
+	if policy := uc.timestamps.Load(); policy != nil {
+		return *policy
+	}
+	return log.TimestampPolicy{}
 }
 
 func (uc *LogUsecase) CreateLog(ctx context.Context, input dto.CreateLogInput) (*dto.CreateLogOutput, error) {
+	output, err := uc.createLog(ctx, input)
+	if uc.metrics != nil {
+		switch {
+		case err == nil && !output.Replayed:
+			uc.metrics.LogIngested(output.ApplicationID.String(), output.Level)
+		case errors.Is(err, ErrInvalidLog):
+			uc.reject(rejectReason(err))
+		}
+	}
+	return output, err
+}
+
+func (uc *LogUsecase) createLog(ctx context.Context, input dto.CreateLogInput) (*dto.CreateLogOutput, error) {
 	// Convert DTO to domain entity
 	newLog, err := dto.ToDomainLog(input)
 	if err != nil {
-		return nil, fmt.Errorf("invalid log data: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLog, err)
+	}
+	if err := uc.timestampPolicy().Check(newLog); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLog, err)
+	}
+	if bound, ok := BoundApplication(ctx); ok && newLog.ApplicationID != bound {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLog, log.ErrApplicationForbidden)
+	}
+
+	key := idempotencyKey(input)
+	if len(key) > 255 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLog, log.ErrIdempotencyKeyLength)
+	}
+
+	// Rate limiting: reject before touching the repository
+	if uc.limiter != nil {
+		if err := uc.limiter.Allow(newLog.ApplicationID.String(), entrySize(input)); err != nil {
+			uc.reject("rate_limited")
+			return nil, err
+		}
+	}
+	tagRequest(ctx, newLog)
+
+	// Idempotency: a repeated key returns the original log without storing or publishing it again
+	reserved := false
+	if key != "" && uc.idempotency != nil {
+		reserveCtx, cancel := context.WithTimeout(ctx, uc.reserveTimeout)
+		existing, err := uc.idempotency.Reserve(reserveCtx, &log.IdempotencyRecord{
+			ApplicationID: newLog.ApplicationID,
+			Key:           key,
+			Log:           newLog,
+			CreatedAt:     time.Now(),
+		})
+		cancel()
+		// If the key store is unreachable the log is still accepted, so that
+		// spooling keeps working; client-supplied IDs remain deduplicated by the repository
+		if err == nil && existing != nil {
+			uc.refund(newLog)
+			output := dto.LogToCreateLogOutput(existing.Log)
+			output.Replayed = true
+			return &output, nil
+		}
+		reserved = err == nil
+	}
+
+	// Write-behind mode: hand the log to the queue and acknowledge immediately
+	if uc.queue != nil {
+		if reserved {
+			uc.pendingKeys.Store(newLog.ID, key)
+		}
+		if err := uc.queue.Enqueue(newLog); err != nil {
+			uc.pendingKeys.Delete(newLog.ID)
+			uc.release(reserved, newLog, key)
+			uc.refund(newLog)
+			return nil, fmt.Errorf("failed to enqueue log: %w", err)
+		}
+		output := dto.LogToCreateLogOutput(newLog)
+		output.Queued = true
+		return &output, nil
 	}
 
 	// Save to repository
 	if err := uc.repo.Create(ctx, newLog); err != nil {
+		uc.release(reserved, newLog, key)
+		uc.refund(newLog)
 		return nil, fmt.Errorf("failed to create log: %w", err)
 	}
 
-	// SSE notification: only if SSE server is present and there are clients for this application
-	if uc.sseSrv != nil {
-		channel := newLog.ApplicationID.String()
-		if uc.sseSrv.StreamExists(channel) {
-			payload, _ := json.Marshal(dto.LogToLogOutput(newLog))
-			uc.sseSrv.Publish(channel, payload)
-		}
-	}
+	uc.publish(newLog)
 
 	output := dto.LogToCreateLogOutput(newLog)
 	return &output, nil
 }
+
+// ListLogs searches an application's historical logs, ordered by event or ingestion time
+func (uc *LogUsecase) ListLogs(ctx context.Context, input dto.ListLogsInput) (*dto.ListLogsOutput, error) {
+	query, err := dto.ToDomainQuery(input)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+	}
+
+	// Fetch one extra entry to know whether another page exists
+	limit := query.Limit
+	query.Limit++
+	logs, err := uc.repo.Find(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list logs: %w", err)
+	}
+
+	output := &dto.ListLogsOutput{
+		Logs:      make([]dto.LogOutput, 0, min(len(logs), limit)),
+		TimeField: string(query.TimeField),
+		Order:     "asc",
+		Limit:     limit,
+		Offset:    query.Offset,
+		HasMore:   len(logs) > limit,
+	}
+	if query.Descending {
+		output.Order = "desc"
+	}
+	for i, l := range logs {
+		if i == limit {
+			break
+		}
+		output.Logs = append(output.Logs, dto.LogToLogOutput(l))
+	}
+	return output, nil
+}
+
+// PublishLogs notifies SSE subscribers about logs that have been persisted,
+// ordered by the configured time field
+func (uc *LogUsecase) PublishLogs(logs []*log.Log) {
+	ordered := make([]*log.Log, len(logs))
+	copy(ordered, logs)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return uc.orderBy.Of(ordered[i]).Before(uc.orderBy.Of(ordered[j]))
+	})
+
+	for _, l := range ordered {
+		uc.publish(l)
+	}
+}
+
+// flushed forgets the keys of a persisted batch and publishes it
+func (uc *LogUsecase) flushed(logs []*log.Log) {
+	for _, l := range logs {
+		uc.pendingKeys.Delete(l.ID)
+	}
+	uc.PublishLogs(logs)
+}
+
+// failed releases the idempotency keys of a batch that could not be
+// persisted, so that retries with the same key are stored rather than
+// reported as duplicates of a log that never existed
+func (uc *LogUsecase) failed(logs []*log.Log) {
+	for _, l := range logs {
+		uc.refund(l)
+		if key, ok := uc.pendingKeys.LoadAndDelete(l.ID); ok {
+			uc.release(true, l, key.(string))
+		}
+	}
+}
+
+// publish sends a notification to the SSE server and additional publishers,
+// only to those that are present and have clients for this application
+func (uc *LogUsecase) publish(l *log.Log) {
+	channel := l.ApplicationID.String()
+	var payload []byte
+	for _, publisher := range append([]SSEPublisher{uc.sseSrv}, uc.publishers...) {
+		if publisher == nil || !publisher.StreamExists(channel) {
+			continue
+		}
+		if payload == nil {
+			payload, _ = json.Marshal(dto.LogToLogOutput(l))
+		}
+		publisher.Publish(channel, payload)
+	}
+}
+
+// release frees a reserved idempotency key so that the client can retry
+func (uc *LogUsecase) release(reserved bool, l *log.Log, key string) {
+	if !reserved {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	uc.idempotency.Release(ctx, l.ApplicationID, key)
+}
+
+// refund returns the rate limit quota of a log that was allowed but not stored
+func (uc *LogUsecase) refund(l *log.Log) {
+	if uc.limiter != nil {
+		uc.limiter.Refund(l.ApplicationID.String())
+	}
+}
+
+// reject counts an entry refused before reaching the repository
+func (uc *LogUsecase) reject(reason string) {
+	if uc.metrics != nil {
+		uc.metrics.LogRejected(reason)
+	}
+}
+
+// rejectReason classifies a validation failure into a fixed set of metric labels
+func rejectReason(err error) string {
+	switch {
+	case errors.Is(err, log.ErrMessageRequired):
+		return "empty_message"
+	case errors.Is(err, log.ErrLevelRequired), errors.Is(err, valueobjects.ErrInvalidLogLevel):
+		return "invalid_level"
+	case errors.Is(err, log.ErrApplicationIDInvalid):
+		return "invalid_application_id"
+	case errors.Is(err, log.ErrUserIDInvalid):
+		return "invalid_user_id"
+	case errors.Is(err, log.ErrTimestampOutOfRange):
+		return "timestamp_out_of_range"
+	case errors.Is(err, log.ErrIdempotencyKeyLength):
+		return "idempotency_key_too_long"
+	case errors.Is(err, log.ErrApplicationForbidden):
+		return "application_forbidden"
+	default:
+		return "invalid"
+	}
+}
+
+// tagRequest records the ID of the request that carried a log in its
+// metadata, unless the client set that field itself. The metadata is copied
+// since it may still be referenced by the input.
+func tagRequest(ctx context.Context, l *log.Log) {
+	requestID, ok := RequestID(ctx)
+	if !ok {
+		return
+	}
+	if _, set := l.Metadata[RequestIDField]; set {
+		return
+	}
+	metadata := maps.Clone(l.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]interface{}, 1)
+	}
+	metadata[RequestIDField] = requestID
+	l.Metadata = metadata
+}
+
+// idempotencyKey prefers the Idempotency-Key header and falls back to the
+// client-supplied ID. A nil ID is replaced by a generated one, so it does not
+// identify the log and must not key it.
+func idempotencyKey(input dto.CreateLogInput) string {
+	if input.IdempotencyKey != "" {
+		return input.IdempotencyKey
+	}
+	if input.ID != nil && *input.ID != (uuid.UUID{}) {
+		return "id:" + input.ID.String()
+	}
+	return ""
+}
+
+// entrySize approximates the wire size of a log entry for byte-based limits
+func entrySize(input dto.CreateLogInput) int {
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return len(input.Message)
+	}
+	return len(payload)
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/pipeline"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
 	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
 )
 
@@ -15,6 +19,8 @@ import (
 type mockLogRepository struct {
 	createError bool
 	createdLogs []*log.Log
+	findResult  []*log.Log
+	lastQuery   log.Query
 }
 
 func (m *mockLogRepository) Create(ctx context.Context, l *log.Log) error {
@@ -25,6 +31,19 @@ func (m *mockLogRepository) Create(ctx context.Context, l *log.Log) error {
 	return nil
 }
 
+func (m *mockLogRepository) CreateMany(ctx context.Context, logs []*log.Log) error {
+	if m.createError {
+		return errors.New("repository error")
+	}
+	m.createdLogs = append(m.createdLogs, logs...)
+	return nil
+}
+
+func (m *mockLogRepository) Find(ctx context.Context, query log.Query) ([]*log.Log, error) {
+	m.lastQuery = query
+	return m.findResult, nil
+}
+
 type mockSSEServer struct {
 	streams      map[string]bool
 	publishCalls []SSEPublishCall
@@ -225,6 +244,33 @@ func TestLogUsecase_CreateLog_WithoutSSEServer(t *testing.T) {
 	}
 }
 
+func TestLogUsecase_CreateLog_WithPublisher(t *testing.T) {
+	repo := &mockLogRepository{}
+	applicationID := uuid.New()
+	sseServer := &mockSSEServer{}
+	tail := &mockSSEServer{streams: map[string]bool{applicationID.String(): true}}
+	usecase := NewLogUsecase(repo, sseServer, WithPublisher(tail))
+
+	input := dto.CreateLogInput{
+		ApplicationID: applicationID,
+		UserID:        uuid.New(),
+		Message:       "Test log message for tail subscribers",
+		Level:         "INFO",
+	}
+
+	if _, err := usecase.CreateLog(context.Background(), input); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Only publishers with clients for the application are notified
+	if len(sseServer.publishCalls) != 0 {
+		t.Errorf("Expected no SSE publish call, got %d", len(sseServer.publishCalls))
+	}
+	if len(tail.publishCalls) != 1 || tail.publishCalls[0].Channel != applicationID.String() {
+		t.Errorf("Expected 1 publish call on the additional publisher, got %+v", tail.publishCalls)
+	}
+}
+
 func TestLogUsecase_CreateLog_InvalidInput(t *testing.T) {
 	repo := &mockLogRepository{}
 	sseServer := &mockSSEServer{
@@ -355,3 +401,800 @@ func TestLogUsecase_CreateLog_ContextCancellation(t *testing.T) {
 	_ = output
 	_ = err
 }
+
+type mockRateLimiter struct {
+	err     error
+	calls   []string
+	refunds []string
+}
+
+func (m *mockRateLimiter) Allow(applicationID string, size int) error {
+	m.calls = append(m.calls, applicationID)
+	return m.err
+}
+
+func (m *mockRateLimiter) Refund(applicationID string) {
+	m.refunds = append(m.refunds, applicationID)
+}
+
+func TestLogUsecase_CreateLog_RateLimited(t *testing.T) {
+	repo := &mockLogRepository{}
+	sseServer := &mockSSEServer{
+		streams: make(map[string]bool),
+	}
+	limiter := &mockRateLimiter{err: errors.New("rate limited")}
+	usecase := NewLogUsecase(repo, sseServer, WithRateLimiter(limiter))
+
+	applicationID := uuid.New()
+	sseServer.streams[applicationID.String()] = true
+
+	input := dto.CreateLogInput{
+		ApplicationID: applicationID,
+		UserID:        uuid.New(),
+		Message:       "Test log message",
+		Level:         "INFO",
+	}
+
+	output, err := usecase.CreateLog(context.Background(), input)
+
+	if !errors.Is(err, limiter.err) {
+		t.Errorf("Expected rate limit error, got %v", err)
+	}
+	if output != nil {
+		t.Errorf("Expected nil output when rate limited, got %+v", output)
+	}
+	if len(limiter.calls) != 1 || limiter.calls[0] != applicationID.String() {
+		t.Errorf("Expected limiter to be called once for %s, got %v", applicationID, limiter.calls)
+	}
+	if len(repo.createdLogs) != 0 {
+		t.Errorf("Expected 0 logs in repository when rate limited, got %d", len(repo.createdLogs))
+	}
+	if len(sseServer.publishCalls) != 0 {
+		t.Errorf("Expected 0 SSE publish calls when rate limited, got %d", len(sseServer.publishCalls))
+	}
+}
+
+func TestLogUsecase_CreateLog_RefundsUnstoredLogs(t *testing.T) {
+	limiter := &mockRateLimiter{}
+	repo := &mockLogRepository{createError: true}
+	usecase := NewLogUsecase(repo, nil, WithRateLimiter(limiter))
+
+	input := dto.CreateLogInput{
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+		Message:       "Test log message",
+		Level:         "INFO",
+	}
+	if _, err := usecase.CreateLog(context.Background(), input); err == nil {
+		t.Fatal("Expected repository error, got none")
+	}
+	if len(limiter.refunds) != 1 || limiter.refunds[0] != input.ApplicationID.String() {
+		t.Errorf("Expected the quota of the unstored log to be refunded, got %v", limiter.refunds)
+	}
+
+	repo.createError = false
+	if _, err := usecase.CreateLog(context.Background(), input); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(limiter.refunds) != 1 {
+		t.Errorf("Expected stored logs to keep their quota, got %d refunds", len(limiter.refunds))
+	}
+}
+
+func TestLogUsecase_CreateLog_RateLimiterSkipsInvalidInput(t *testing.T) {
+	repo := &mockLogRepository{}
+	limiter := &mockRateLimiter{}
+	usecase := NewLogUsecase(repo, nil, WithRateLimiter(limiter))
+
+	input := dto.CreateLogInput{
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+		Message:       "",
+		Level:         "INFO",
+	}
+
+	if _, err := usecase.CreateLog(context.Background(), input); err == nil {
+		t.Error("Expected validation error, got none")
+	}
+	if len(limiter.calls) != 0 {
+		t.Errorf("Expected invalid input not to consume rate limit, got %d calls", len(limiter.calls))
+	}
+}
+
+type mockLogQueue struct {
+	enqueueError error
+	enqueued     []*log.Log
+	onFlushed    func([]*log.Log)
+	onFailed     func([]*log.Log)
+}
+
+func (m *mockLogQueue) Enqueue(l *log.Log) error {
+	if m.enqueueError != nil {
+		return m.enqueueError
+	}
+	m.enqueued = append(m.enqueued, l)
+	return nil
+}
+
+func (m *mockLogQueue) OnFlushed(fn func([]*log.Log)) {
+	m.onFlushed = fn
+}
+
+func (m *mockLogQueue) OnFailed(fn func([]*log.Log)) {
+	m.onFailed = fn
+}
+
+func TestLogUsecase_CreateLog_Queued(t *testing.T) {
+	repo := &mockLogRepository{}
+	sseServer := &mockSSEServer{
+		streams: make(map[string]bool),
+	}
+	queue := &mockLogQueue{}
+	usecase := NewLogUsecase(repo, sseServer, WithQueue(queue))
+
+	applicationID := uuid.New()
+	sseServer.streams[applicationID.String()] = true
+
+	input := dto.CreateLogInput{
+		ApplicationID: applicationID,
+		UserID:        uuid.New(),
+		Message:       "Queued log message",
+		Level:         "INFO",
+	}
+
+	output, err := usecase.CreateLog(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !output.Queued {
+		t.Error("Expected output to be marked as queued")
+	}
+	if len(queue.enqueued) != 1 || queue.enqueued[0].ID != output.ID {
+		t.Errorf("Expected the log to be enqueued with ID %s", output.ID)
+	}
+	if len(repo.createdLogs) != 0 {
+		t.Errorf("Expected repository not to be called synchronously, got %d logs", len(repo.createdLogs))
+	}
+	if len(sseServer.publishCalls) != 0 {
+		t.Errorf("Expected no SSE publish before flush, got %d", len(sseServer.publishCalls))
+	}
+
+	// Simulate the pipeline flushing the batch
+	if queue.onFlushed == nil {
+		t.Fatal("Expected usecase to register a flush callback")
+	}
+	queue.onFlushed(queue.enqueued)
+
+	if len(sseServer.publishCalls) != 1 {
+		t.Errorf("Expected 1 SSE publish after flush, got %d", len(sseServer.publishCalls))
+	}
+}
+
+func TestLogUsecase_CreateLog_QueueError(t *testing.T) {
+	queue := &mockLogQueue{enqueueError: errors.New("queue full")}
+	usecase := NewLogUsecase(&mockLogRepository{}, nil, WithQueue(queue))
+
+	input := dto.CreateLogInput{
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+		Message:       "Queued log message",
+		Level:         "INFO",
+	}
+
+	output, err := usecase.CreateLog(context.Background(), input)
+	if !errors.Is(err, queue.enqueueError) {
+		t.Errorf("Expected queue error, got %v", err)
+	}
+	if output != nil {
+		t.Errorf("Expected nil output when enqueue fails, got %+v", output)
+	}
+}
+
+type mockIdempotencyStore struct {
+	records    map[string]*log.IdempotencyRecord
+	reserveErr error
+	released   []string
+	// block makes Reserve wait for its context, like an unreachable database
+	block bool
+}
+
+func newMockIdempotencyStore() *mockIdempotencyStore {
+	return &mockIdempotencyStore{records: make(map[string]*log.IdempotencyRecord)}
+}
+
+func (m *mockIdempotencyStore) Reserve(ctx context.Context, record *log.IdempotencyRecord) (*log.IdempotencyRecord, error) {
+	if m.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if m.reserveErr != nil {
+		return nil, m.reserveErr
+	}
+	id := record.ApplicationID.String() + "/" + record.Key
+	if existing, ok := m.records[id]; ok {
+		return existing, nil
+	}
+	m.records[id] = record
+	return nil, nil
+}
+
+func (m *mockIdempotencyStore) Release(ctx context.Context, applicationID uuid.UUID, key string) error {
+	delete(m.records, applicationID.String()+"/"+key)
+	m.released = append(m.released, key)
+	return nil
+}
+
+func TestLogUsecase_CreateLog_UnreachableIdempotencyStore(t *testing.T) {
+	repo := &mockLogRepository{}
+	store := newMockIdempotencyStore()
+	store.block = true
+	usecase := NewLogUsecase(repo, nil, WithIdempotencyStore(store))
+	usecase.reserveTimeout = 10 * time.Millisecond
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := usecase.CreateLog(context.Background(), dto.CreateLogInput{
+			ApplicationID:  uuid.New(),
+			UserID:         uuid.New(),
+			Message:        "Logged during an outage",
+			Level:          "INFO",
+			IdempotencyKey: "outage-key",
+		})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected the log to be accepted, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected CreateLog not to wait for the idempotency store")
+	}
+	if len(repo.createdLogs) != 1 {
+		t.Errorf("Expected 1 stored log, got %d", len(repo.createdLogs))
+	}
+}
+
+func TestLogUsecase_CreateLog_IdempotentRetry(t *testing.T) {
+	repo := &mockLogRepository{}
+	sseServer := &mockSSEServer{
+		streams: make(map[string]bool),
+	}
+	store := newMockIdempotencyStore()
+	usecase := NewLogUsecase(repo, sseServer, WithIdempotencyStore(store))
+
+	applicationID := uuid.New()
+	sseServer.streams[applicationID.String()] = true
+
+	input := dto.CreateLogInput{
+		ApplicationID:  applicationID,
+		UserID:         uuid.New(),
+		Message:        "Retried log message",
+		Level:          "INFO",
+		IdempotencyKey: "retry-key-1",
+	}
+
+	first, err := usecase.CreateLog(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first.Replayed {
+		t.Error("Expected first request not to be a replay")
+	}
+
+	second, err := usecase.CreateLog(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error on retry: %v", err)
+	}
+	if !second.Replayed {
+		t.Error("Expected retry to be marked as replayed")
+	}
+	if second.ID != first.ID || second.Timestamp != first.Timestamp {
+		t.Errorf("Expected retry to return the original log %s, got %s", first.ID, second.ID)
+	}
+	if len(repo.createdLogs) != 1 {
+		t.Errorf("Expected 1 log in repository, got %d", len(repo.createdLogs))
+	}
+	if len(sseServer.publishCalls) != 1 {
+		t.Errorf("Expected duplicates not to be re-published, got %d publish calls", len(sseServer.publishCalls))
+	}
+}
+
+func TestLogUsecase_CreateLog_ClientSuppliedID(t *testing.T) {
+	repo := &mockLogRepository{}
+	store := newMockIdempotencyStore()
+	usecase := NewLogUsecase(repo, nil, WithIdempotencyStore(store))
+
+	clientID := uuid.New()
+	input := dto.CreateLogInput{
+		ID:            &clientID,
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+		Message:       "Client ID log message",
+		Level:         "INFO",
+	}
+
+	output, err := usecase.CreateLog(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output.ID != clientID {
+		t.Errorf("Expected client-supplied ID %s, got %s", clientID, output.ID)
+	}
+
+	retry, _ := usecase.CreateLog(context.Background(), input)
+	if !retry.Replayed {
+		t.Error("Expected repeated client ID to be replayed")
+	}
+	if len(repo.createdLogs) != 1 {
+		t.Errorf("Expected 1 log in repository, got %d", len(repo.createdLogs))
+	}
+}
+
+func TestLogUsecase_CreateLog_ReleasesKeyOnFailure(t *testing.T) {
+	repo := &mockLogRepository{createError: true}
+	store := newMockIdempotencyStore()
+	usecase := NewLogUsecase(repo, nil, WithIdempotencyStore(store))
+
+	input := dto.CreateLogInput{
+		ApplicationID:  uuid.New(),
+		UserID:         uuid.New(),
+		Message:        "Failing log message",
+		Level:          "INFO",
+		IdempotencyKey: "retry-key-2",
+	}
+
+	if _, err := usecase.CreateLog(context.Background(), input); err == nil {
+		t.Fatal("Expected repository error, got none")
+	}
+	if len(store.released) != 1 || store.released[0] != "retry-key-2" {
+		t.Errorf("Expected key to be released after failure, got %v", store.released)
+	}
+
+	// The retry is processed as a new request
+	repo.createError = false
+	output, err := usecase.CreateLog(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error on retry: %v", err)
+	}
+	if output.Replayed {
+		t.Error("Expected retry after a failure not to be a replay")
+	}
+}
+
+func TestLogUsecase_CreateLog_ReleasesKeyOnFailedFlush(t *testing.T) {
+	queue := &mockLogQueue{}
+	store := newMockIdempotencyStore()
+	usecase := NewLogUsecase(&mockLogRepository{}, nil, WithQueue(queue), WithIdempotencyStore(store))
+
+	input := dto.CreateLogInput{
+		ApplicationID:  uuid.New(),
+		UserID:         uuid.New(),
+		Message:        "Queued log message",
+		Level:          "INFO",
+		IdempotencyKey: "retry-key-4",
+	}
+
+	if _, err := usecase.CreateLog(context.Background(), input); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	queue.onFailed(queue.enqueued)
+	if len(store.released) != 1 || store.released[0] != "retry-key-4" {
+		t.Errorf("Expected key to be released after a failed flush, got %v", store.released)
+	}
+
+	output, err := usecase.CreateLog(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error on retry: %v", err)
+	}
+	if output.Replayed {
+		t.Error("Expected retry after a failed flush not to be a replay")
+	}
+
+	// Keys of persisted batches are kept
+	queue.onFlushed(queue.enqueued[1:])
+	queue.onFailed(queue.enqueued[1:])
+	if len(store.released) != 1 {
+		t.Errorf("Expected keys of flushed logs to be kept, got %v", store.released)
+	}
+}
+
+func TestLogUsecase_CreateLog_NilIDIsNotAKey(t *testing.T) {
+	repo := &mockLogRepository{}
+	store := newMockIdempotencyStore()
+	usecase := NewLogUsecase(repo, nil, WithIdempotencyStore(store))
+
+	applicationID := uuid.New()
+	for _, message := range []string{"first log", "second log"} {
+		nilID := uuid.UUID{}
+		output, err := usecase.CreateLog(context.Background(), dto.CreateLogInput{
+			ID:            &nilID,
+			ApplicationID: applicationID,
+			UserID:        uuid.New(),
+			Message:       message,
+			Level:         "INFO",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if output.Replayed {
+			t.Errorf("Expected %q not to be a replay of another log with a nil ID", message)
+		}
+	}
+
+	if len(repo.createdLogs) != 2 {
+		t.Errorf("Expected both logs to be stored, got %d", len(repo.createdLogs))
+	}
+	if len(store.records) != 0 {
+		t.Errorf("Expected no idempotency keys for nil IDs, got %v", store.records)
+	}
+}
+
+func TestLogUsecase_CreateLog_IdempotencyStoreUnavailable(t *testing.T) {
+	repo := &mockLogRepository{}
+	store := newMockIdempotencyStore()
+	store.reserveErr = errors.New("store unavailable")
+	usecase := NewLogUsecase(repo, nil, WithIdempotencyStore(store))
+
+	input := dto.CreateLogInput{
+		ApplicationID:  uuid.New(),
+		UserID:         uuid.New(),
+		Message:        "Log message",
+		Level:          "INFO",
+		IdempotencyKey: "retry-key-3",
+	}
+
+	if _, err := usecase.CreateLog(context.Background(), input); err != nil {
+		t.Errorf("Expected log to be accepted without the key store, got %v", err)
+	}
+	if len(repo.createdLogs) != 1 {
+		t.Errorf("Expected 1 log in repository, got %d", len(repo.createdLogs))
+	}
+}
+
+func TestLogUsecase_CreateLog_IdempotencyKeyTooLong(t *testing.T) {
+	usecase := NewLogUsecase(&mockLogRepository{}, nil, WithIdempotencyStore(newMockIdempotencyStore()))
+
+	input := dto.CreateLogInput{
+		ApplicationID:  uuid.New(),
+		UserID:         uuid.New(),
+		Message:        "Log message",
+		Level:          "INFO",
+		IdempotencyKey: string(make([]byte, 256)),
+	}
+
+	if _, err := usecase.CreateLog(context.Background(), input); !errors.Is(err, log.ErrIdempotencyKeyLength) {
+		t.Errorf("Expected ErrIdempotencyKeyLength, got %v", err)
+	}
+}
+
+func TestLogUsecase_CreateLog_EventTimestamp(t *testing.T) {
+	repo := &mockLogRepository{}
+	usecase := NewLogUsecase(repo, nil, WithTimestampPolicy(log.TimestampPolicy{
+		MaxPast:   time.Hour,
+		MaxFuture: time.Minute,
+	}))
+
+	eventTime := time.Now().Add(-30 * time.Minute).Round(0)
+	input := dto.CreateLogInput{
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+		Message:       "Buffered log message",
+		Level:         "INFO",
+		Timestamp:     &eventTime,
+	}
+
+	output, err := usecase.CreateLog(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stored := repo.createdLogs[0]
+	if !stored.Timestamp.Equal(eventTime) {
+		t.Errorf("Expected event timestamp %v, got %v", eventTime, stored.Timestamp)
+	}
+	if !stored.IngestedAt.After(eventTime) {
+		t.Errorf("Expected ingestion time after event time, got %v", stored.IngestedAt)
+	}
+	if output.Timestamp == output.IngestedAt {
+		t.Error("Expected output to report distinct event and ingestion times")
+	}
+}
+
+func TestLogUsecase_CreateLog_TimestampOutOfRange(t *testing.T) {
+	repo := &mockLogRepository{}
+	usecase := NewLogUsecase(repo, nil, WithTimestampPolicy(log.TimestampPolicy{
+		MaxPast:   time.Hour,
+		MaxFuture: time.Minute,
+	}))
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+	}{
+		{name: "Too far in the past", offset: -2 * time.Hour},
+		{name: "Too far in the future", offset: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventTime := time.Now().Add(tt.offset)
+			input := dto.CreateLogInput{
+				ApplicationID: uuid.New(),
+				UserID:        uuid.New(),
+				Message:       "Skewed log message",
+				Level:         "INFO",
+				Timestamp:     &eventTime,
+			}
+
+			_, err := usecase.CreateLog(context.Background(), input)
+			if !errors.Is(err, log.ErrTimestampOutOfRange) {
+				t.Errorf("Expected ErrTimestampOutOfRange, got %v", err)
+			}
+		})
+	}
+
+	if len(repo.createdLogs) != 0 {
+		t.Errorf("Expected 0 logs in repository, got %d", len(repo.createdLogs))
+	}
+}
+
+func TestLogUsecase_CreateLog_BoundApplication(t *testing.T) {
+	repo := &mockLogRepository{}
+	usecase := NewLogUsecase(repo, nil)
+	applicationID := uuid.New()
+	ctx := BindApplication(context.Background(), applicationID)
+
+	input := dto.CreateLogInput{
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+		Message:       "Log for another application",
+		Level:         "INFO",
+	}
+	_, err := usecase.CreateLog(ctx, input)
+	if !errors.Is(err, log.ErrApplicationForbidden) || !errors.Is(err, ErrInvalidLog) {
+		t.Errorf("Expected ErrApplicationForbidden, got %v", err)
+	}
+
+	input.ApplicationID = applicationID
+	if _, err := usecase.CreateLog(ctx, input); err != nil {
+		t.Errorf("Expected the bound application to be accepted, got %v", err)
+	}
+	if len(repo.createdLogs) != 1 {
+		t.Errorf("Expected 1 log in repository, got %d", len(repo.createdLogs))
+	}
+}
+
+func TestLogUsecase_CreateLog_RequestID(t *testing.T) {
+	repo := &mockLogRepository{}
+	usecase := NewLogUsecase(repo, nil)
+	ctx := WithRequestID(context.Background(), "req-42")
+
+	metadata := map[string]interface{}{"order": 7}
+	input := dto.CreateLogInput{
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+		Message:       "Order placed",
+		Level:         "INFO",
+		Metadata:      metadata,
+	}
+	if _, err := usecase.CreateLog(ctx, input); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := usecase.CreateLog(context.Background(), input); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	input.Metadata = map[string]interface{}{RequestIDField: "client-1"}
+	if _, err := usecase.CreateLog(ctx, input); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := repo.createdLogs[0].Metadata; got[RequestIDField] != "req-42" || got["order"] != 7 {
+		t.Errorf("Expected the request ID next to the client metadata, got %v", got)
+	}
+	if _, ok := metadata[RequestIDField]; ok {
+		t.Error("Expected the input metadata not to be modified")
+	}
+	if _, ok := repo.createdLogs[1].Metadata[RequestIDField]; ok {
+		t.Error("Expected no request ID outside of a request")
+	}
+	if got := repo.createdLogs[2].Metadata[RequestIDField]; got != "client-1" {
+		t.Errorf("Expected the client's request_id to be kept, got %v", got)
+	}
+}
+
+type mockMetrics struct {
+	ingested []string
+	rejected []string
+}
+
+func (m *mockMetrics) LogIngested(applicationID, level string) {
+	m.ingested = append(m.ingested, applicationID+"/"+level)
+}
+
+func (m *mockMetrics) LogRejected(reason string) {
+	m.rejected = append(m.rejected, reason)
+}
+
+func TestLogUsecase_CreateLog_Metrics(t *testing.T) {
+	repo := &mockLogRepository{}
+	metrics := &mockMetrics{}
+	limiter := &mockRateLimiter{}
+	usecase := NewLogUsecase(repo, nil,
+		WithMetrics(metrics),
+		WithRateLimiter(limiter),
+		WithIdempotencyStore(newMockIdempotencyStore()),
+		WithTimestampPolicy(log.TimestampPolicy{MaxFuture: time.Minute}),
+	)
+
+	applicationID := uuid.New()
+	valid := dto.CreateLogInput{ApplicationID: applicationID, UserID: uuid.New(), Message: "ok", Level: "warn", IdempotencyKey: "k"}
+	future := time.Now().Add(time.Hour)
+
+	inputs := []dto.CreateLogInput{
+		valid,
+		valid, // replayed, not counted twice
+		{ApplicationID: applicationID, UserID: uuid.New(), Message: "", Level: "INFO"},
+		{ApplicationID: applicationID, UserID: uuid.New(), Message: "m", Level: "LOUD"},
+		{ApplicationID: uuid.Nil, UserID: uuid.New(), Message: "m", Level: "INFO"},
+		{ApplicationID: applicationID, UserID: uuid.New(), Message: "m", Level: "INFO", Timestamp: &future},
+	}
+	for _, input := range inputs {
+		usecase.CreateLog(context.Background(), input)
+	}
+
+	limiter.err = errors.New("rate limited")
+	usecase.CreateLog(context.Background(), dto.CreateLogInput{ApplicationID: applicationID, UserID: uuid.New(), Message: "m", Level: "INFO"})
+
+	limiter.err = nil
+	repo.createError = true
+	usecase.CreateLog(context.Background(), dto.CreateLogInput{ApplicationID: applicationID, UserID: uuid.New(), Message: "m", Level: "INFO"})
+
+	if len(metrics.ingested) != 1 || metrics.ingested[0] != applicationID.String()+"/WARN" {
+		t.Errorf("Expected one WARN log to be counted as ingested, got %v", metrics.ingested)
+	}
+	expected := []string{"empty_message", "invalid_level", "invalid_application_id", "timestamp_out_of_range", "rate_limited"}
+	if fmt.Sprint(metrics.rejected) != fmt.Sprint(expected) {
+		t.Errorf("Expected rejects %v, got %v", expected, metrics.rejected)
+	}
+}
+
+func TestLogUsecase_PublishLogs_Ordering(t *testing.T) {
+	applicationID := uuid.New()
+	base := time.Now()
+
+	// Ingested in order, but with event times in reverse
+	logs := make([]*log.Log, 3)
+	for i := range logs {
+		l, _ := log.New("Message", "INFO", applicationID, uuid.New())
+		l.IngestedAt = base.Add(time.Duration(i) * time.Second)
+		l.Timestamp = base.Add(-time.Duration(i) * time.Second)
+		logs[i] = l
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []*log.Log
+	}{
+		{name: "Default ingestion order", expected: []*log.Log{logs[0], logs[1], logs[2]}},
+		{name: "Event time order", opts: []Option{WithPublishOrder(log.TimeFieldTimestamp)}, expected: []*log.Log{logs[2], logs[1], logs[0]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sseServer := &mockSSEServer{streams: map[string]bool{applicationID.String(): true}}
+			usecase := NewLogUsecase(&mockLogRepository{}, sseServer, tt.opts...)
+
+			usecase.PublishLogs(logs)
+
+			if len(sseServer.publishCalls) != len(tt.expected) {
+				t.Fatalf("Expected %d publish calls, got %d", len(tt.expected), len(sseServer.publishCalls))
+			}
+			for i, call := range sseServer.publishCalls {
+				var payload dto.LogOutput
+				json.Unmarshal(call.Data, &payload)
+				if payload.ID != tt.expected[i].ID {
+					t.Errorf("Expected log %s at position %d, got %s", tt.expected[i].ID, i, payload.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestLogUsecase_ListLogs(t *testing.T) {
+	applicationID := uuid.New()
+	repo := &mockLogRepository{}
+	for i := 0; i < 3; i++ {
+		l, _ := log.New("Stored message", "WARN", applicationID, uuid.New())
+		repo.findResult = append(repo.findResult, l)
+	}
+	usecase := NewLogUsecase(repo, nil)
+
+	output, err := usecase.ListLogs(context.Background(), dto.ListLogsInput{
+		ApplicationID: applicationID,
+		MinLevel:      "warn",
+		TimeField:     "ingested_at",
+		Order:         "asc",
+		Limit:         2,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if repo.lastQuery.Limit != 3 {
+		t.Errorf("Expected repository to be asked for limit+1 logs, got %d", repo.lastQuery.Limit)
+	}
+	if repo.lastQuery.TimeField != log.TimeFieldIngestedAt || repo.lastQuery.Descending {
+		t.Errorf("Expected ascending ingested_at query, got %+v", repo.lastQuery)
+	}
+	if len(repo.lastQuery.Levels) != 3 {
+		t.Errorf("Expected min_level WARN to expand to 3 levels, got %v", repo.lastQuery.Levels)
+	}
+	if len(output.Logs) != 2 || !output.HasMore {
+		t.Errorf("Expected 2 logs and another page, got %d logs, has_more=%v", len(output.Logs), output.HasMore)
+	}
+	if output.TimeField != "ingested_at" || output.Order != "asc" {
+		t.Errorf("Expected ingested_at/asc in output, got %s/%s", output.TimeField, output.Order)
+	}
+}
+
+func TestLogUsecase_ListLogs_InvalidQuery(t *testing.T) {
+	usecase := NewLogUsecase(&mockLogRepository{}, nil)
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		input dto.ListLogsInput
+	}{
+		{name: "Missing application", input: dto.ListLogsInput{}},
+		{name: "Invalid time field", input: dto.ListLogsInput{ApplicationID: uuid.New(), TimeField: "created"}},
+		{name: "Invalid order", input: dto.ListLogsInput{ApplicationID: uuid.New(), Order: "sideways"}},
+		{name: "Inverted range", input: dto.ListLogsInput{ApplicationID: uuid.New(), From: &now, To: &now}},
+		{name: "Limit too large", input: dto.ListLogsInput{ApplicationID: uuid.New(), Limit: 5000}},
+		{name: "Invalid level", input: dto.ListLogsInput{ApplicationID: uuid.New(), Levels: []string{"LOUD"}}},
+		{name: "Invalid tag key", input: dto.ListLogsInput{ApplicationID: uuid.New(), Tags: map[string]string{"$where": "1"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := usecase.ListLogs(context.Background(), tt.input); !errors.Is(err, ErrInvalidQuery) {
+				t.Errorf("Expected ErrInvalidQuery, got %v", err)
+			}
+		})
+	}
+}
+
+func TestClassifyCreateError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		kind       CreateErrorKind
+		retryAfter int64
+	}{
+		{name: "Rate limited", err: &ratelimit.ExceededError{RetryAfter: 1500 * time.Millisecond}, kind: CreateErrorRateLimited, retryAfter: 2},
+		{name: "Rate limited without delay", err: &ratelimit.ExceededError{}, kind: CreateErrorRateLimited, retryAfter: 1},
+		{name: "Queue full", err: pipeline.ErrQueueFull, kind: CreateErrorUnavailable, retryAfter: 1},
+		{name: "Pipeline closed", err: pipeline.ErrClosed, kind: CreateErrorUnavailable, retryAfter: 1},
+		{name: "Forbidden", err: fmt.Errorf("%w: %w", ErrInvalidLog, log.ErrApplicationForbidden), kind: CreateErrorForbidden},
+		{name: "Invalid", err: fmt.Errorf("%w: %w", ErrInvalidLog, log.ErrMessageRequired), kind: CreateErrorInvalid},
+		{name: "Duplicate", err: log.ErrDuplicateLog, kind: CreateErrorDuplicate},
+		{name: "Internal", err: errors.New("connection reset"), kind: CreateErrorInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyCreateError(tt.err)
+			if got.Kind != tt.kind {
+				t.Errorf("Expected kind %d, got %d", tt.kind, got.Kind)
+			}
+			if got.RetryAfterSeconds() != tt.retryAfter {
+				t.Errorf("Expected retry after %d, got %d", tt.retryAfter, got.RetryAfterSeconds())
+			}
+			if got.Message == "" || got.Message == "connection reset" {
+				t.Errorf("Expected a client-facing message, got %q", got.Message)
+			}
+		})
+	}
+}
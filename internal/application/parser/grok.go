@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// maxGrokDepth bounds nested pattern references, catching recursive definitions
+const maxGrokDepth = 16
+
+// grokReference matches %{SYNTAX}, %{SYNTAX:semantic} and %{SYNTAX:semantic:type}
+var grokReference = regexp.MustCompile(`%\{(\w+)(?::([^:}]+))?(?::(int|float))?\}`)
+
+// grokPatterns is the subset of the Logstash pattern library most log lines need
+var grokPatterns = map[string]string{
+	"USERNAME":          `[a-zA-Z0-9._-]+`,
+	"USER":              `%{USERNAME}`,
+	"INT":               `(?:[+-]?(?:[0-9]+))`,
+	"BASE10NUM":         `(?:[+-]?(?:[0-9]+(?:\.[0-9]+)?)|\.[0-9]+)`,
+	"NUMBER":            `(?:%{BASE10NUM})`,
+	"POSINT":            `\b(?:[1-9][0-9]*)\b`,
+	"NONNEGINT":         `\b(?:[0-9]+)\b`,
+	"WORD":              `\b\w+\b`,
+	"NOTSPACE":          `\S+`,
+	"SPACE":             `\s*`,
+	"DATA":              `.*?`,
+	"GREEDYDATA":        `.*`,
+	"QUOTEDSTRING":      `"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`,
+	"UUID":              `[A-Fa-f0-9]{8}-(?:[A-Fa-f0-9]{4}-){3}[A-Fa-f0-9]{12}`,
+	"IPV4":              `(?:(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)\.){3}(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)`,
+	"IPV6":              `(?:[0-9A-Fa-f]{0,4}:){2,7}[0-9A-Fa-f]{0,4}`,
+	"IP":                `(?:%{IPV6}|%{IPV4})`,
+	"HOSTNAME":          `\b(?:[0-9A-Za-z][0-9A-Za-z-]{0,62})(?:\.(?:[0-9A-Za-z][0-9A-Za-z-]{0,62}))*\b`,
+	"IPORHOST":          `(?:%{IP}|%{HOSTNAME})`,
+	"HOSTPORT":          `%{IPORHOST}:%{POSINT}`,
+	"PATH":              `(?:/[^\s]*)+`,
+	"URIPATH":           `(?:/[A-Za-z0-9$.+!*'(){},~:;=@#%&_\-]*)+`,
+	"URIPARAM":          `\?[A-Za-z0-9$.+!*'|(){},~@#%&/=:;_?\-\[\]<>]*`,
+	"URIPATHPARAM":      `%{URIPATH}(?:%{URIPARAM})?`,
+	"LOGLEVEL":          `(?:[Tt]race|TRACE|[Dd]ebug|DEBUG|[Nn]otice|NOTICE|[Ii]nfo(?:rmation)?|INFO(?:RMATION)?|[Ww]arn(?:ing)?|WARN(?:ING)?|[Ee]rr(?:or)?|ERR(?:OR)?|[Cc]rit(?:ical)?|CRIT(?:ICAL)?|[Ff]atal|FATAL|[Ss]evere|SEVERE|[Aa]lert|ALERT|[Ee]merg(?:ency)?|EMERG(?:ENCY)?)`,
+	"YEAR":              `(?:\d\d){1,2}`,
+	"MONTHNUM":          `(?:0?[1-9]|1[0-2])`,
+	"MONTHDAY":          `(?:(?:0[1-9])|(?:[12][0-9])|(?:3[01])|[1-9])`,
+	"MONTH":             `\b(?:[Jj]an(?:uary)?|[Ff]eb(?:ruary)?|[Mm]ar(?:ch)?|[Aa]pr(?:il)?|[Mm]ay|[Jj]un(?:e)?|[Jj]ul(?:y)?|[Aa]ug(?:ust)?|[Ss]ep(?:tember)?|[Oo]ct(?:ober)?|[Nn]ov(?:ember)?|[Dd]ec(?:ember)?)\b`,
+	"HOUR":              `(?:2[0123]|[01]?[0-9])`,
+	"MINUTE":            `(?:[0-5][0-9])`,
+	"SECOND":            `(?:(?:[0-5]?[0-9]|60)(?:[:.,][0-9]+)?)`,
+	"TIME":              `%{HOUR}:%{MINUTE}:%{SECOND}`,
+	"ISO8601_TIMEZONE":  `(?:Z|[+-]%{HOUR}(?::?%{MINUTE}))`,
+	"TIMESTAMP_ISO8601": `%{YEAR}-%{MONTHNUM}-%{MONTHDAY}[T ]%{HOUR}:?%{MINUTE}(?::?%{SECOND})?%{ISO8601_TIMEZONE}?`,
+	"SYSLOGTIMESTAMP":   `%{MONTH} +%{MONTHDAY} %{TIME}`,
+	"HTTPDATE":          `%{MONTHDAY}/%{MONTH}/%{YEAR}:%{TIME} %{INT}`,
+}
+
+type grokField struct {
+	name string
+	typ  string
+}
+
+// grok is a pattern expanded to a regular expression whose generated group
+// names map back to the semantic names of the pattern
+type grok struct {
+	re     *regexp.Regexp
+	fields map[string]grokField
+}
+
+// compileGrok expands the references of a pattern using the built-in library
+// and the custom patterns, which take precedence
+func compileGrok(pattern string, custom map[string]string) (*grok, error) {
+	if pattern == "" {
+		return nil, errors.New("grok format requires a pattern")
+	}
+
+	library := make(map[string]string, len(grokPatterns)+len(custom))
+	for name, definition := range grokPatterns {
+		library[name] = definition
+	}
+	for name, definition := range custom {
+		library[name] = definition
+	}
+
+	g := &grok{fields: make(map[string]grokField)}
+	var expandErr error
+	expanded := grokReference.ReplaceAllStringFunc(pattern, func(reference string) string {
+		match := grokReference.FindStringSubmatch(reference)
+		body, err := expandGrok(match[1], library, 0)
+		if err != nil {
+			expandErr = err
+			return ""
+		}
+		if match[2] == "" {
+			return "(?:" + body + ")"
+		}
+		group := fmt.Sprintf("grok%d", len(g.fields))
+		g.fields[group] = grokField{name: match[2], typ: match[3]}
+		return "(?P<" + group + ">" + body + ")"
+	})
+	if expandErr != nil {
+		return nil, expandErr
+	}
+
+	re, err := regexp.Compile(expanded)
+	if err != nil {
+		return nil, err
+	}
+	g.re = re
+	return g, nil
+}
+
+// expandGrok resolves a pattern name; references inside library patterns never capture
+func expandGrok(name string, library map[string]string, depth int) (string, error) {
+	if depth > maxGrokDepth {
+		return "", fmt.Errorf("grok pattern %s is nested too deeply or recursive", name)
+	}
+	definition, ok := library[name]
+	if !ok {
+		return "", fmt.Errorf("unknown grok pattern %s", name)
+	}
+
+	var expandErr error
+	expanded := grokReference.ReplaceAllStringFunc(definition, func(reference string) string {
+		match := grokReference.FindStringSubmatch(reference)
+		body, err := expandGrok(match[1], library, depth+1)
+		if err != nil {
+			expandErr = err
+			return ""
+		}
+		return "(?:" + body + ")"
+	})
+	return expanded, expandErr
+}
+
+// extract returns the captured semantics of a match, converted to their type
+func (g *grok) extract(line string) (map[string]interface{}, bool) {
+	match := g.re.FindStringSubmatch(line)
+	if match == nil {
+		return nil, false
+	}
+
+	fields := make(map[string]interface{})
+	for i, group := range g.re.SubexpNames() {
+		if group == "" || match[i] == "" {
+			continue
+		}
+		if field, ok := g.fields[group]; ok {
+			fields[field.name] = convert(match[i], field.typ)
+		} else {
+			// Named groups written directly in the pattern
+			fields[group] = match[i]
+		}
+	}
+	return fields, true
+}
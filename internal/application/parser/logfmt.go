@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"strconv"
+)
+
+// parseLogfmt reads key=value pairs. Values may be double-quoted with Go
+// escapes, and bare keys are recorded as "true". A line without any
+// key=value pair is not logfmt and is reported as unmatched.
+func parseLogfmt(line string) (map[string]interface{}, bool) {
+	fields := make(map[string]interface{})
+	pairs := 0
+
+	for i := 0; i < len(line); {
+		// Skip whitespace between pairs
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' && line[i] != '\t' {
+			i++
+		}
+		key := line[start:i]
+		if key == "" || key[0] == '"' {
+			return nil, false
+		}
+		if i == len(line) || line[i] != '=' {
+			fields[key] = "true"
+			continue
+		}
+		i++ // skip '='
+
+		var value string
+		if i < len(line) && line[i] == '"' {
+			end := closingQuote(line, i)
+			if end < 0 {
+				return nil, false
+			}
+			unquoted, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				unquoted = line[i+1 : end]
+			}
+			value = unquoted
+			i = end + 1
+		} else {
+			start := i
+			for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+				i++
+			}
+			value = line[start:i]
+		}
+		fields[key] = value
+		pairs++
+	}
+
+	if pairs == 0 {
+		return nil, false
+	}
+	return fields, true
+}
+
+// closingQuote returns the index of the quote ending the string opened at start
+func closingQuote(line string, start int) int {
+	escaped := false
+	for i := start + 1; i < len(line); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case line[i] == '\\':
+			escaped = true
+		case line[i] == '"':
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,232 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+// ErrInvalidConfig is returned when a parser configuration cannot be compiled
+var ErrInvalidConfig = errors.New("invalid parser configuration")
+
+// Supported line formats
+const (
+	FormatLogfmt = "logfmt"
+	FormatRegex  = "regex"
+	FormatGrok   = "grok"
+)
+
+// UnparsedField is set in the metadata of lines stored raw because they did not match
+const UnparsedField = "unparsed"
+
+// Config describes how the text lines of an application are parsed. Fields
+// extracted from a line (logfmt keys, named regex groups or grok semantics)
+// are assigned by name: the first present field of each list becomes the
+// message, level, source or timestamp, fields listed in TagFields become tags
+// and every other field is kept as metadata.
+type Config struct {
+	Format          string            `json:"format"`                     // logfmt (default), regex or grok
+	Pattern         string            `json:"pattern,omitempty"`          // Regular expression with named groups, or grok pattern
+	Patterns        map[string]string `json:"patterns,omitempty"`         // Additional grok patterns by name
+	MessageFields   []string          `json:"message_fields,omitempty"`   // Default: msg, message
+	LevelFields     []string          `json:"level_fields,omitempty"`     // Default: level, lvl, severity
+	SourceFields    []string          `json:"source_fields,omitempty"`    // Default: source, logger, component
+	TimestampFields []string          `json:"timestamp_fields,omitempty"` // Default: time, ts, timestamp
+	TimestampLayout string            `json:"timestamp_layout,omitempty"` // Go time layout, default RFC3339
+	TagFields       []string          `json:"tag_fields,omitempty"`
+	DefaultLevel    string            `json:"default_level,omitempty"` // Level of lines without a recognised level, default INFO
+}
+
+// Result is a parsed line
+type Result struct {
+	Message   string
+	Level     string
+	Source    string
+	Timestamp *time.Time
+	Tags      map[string]string
+	Metadata  map[string]interface{}
+	// Parsed is false when the line did not match and is stored raw
+	Parsed bool
+}
+
+// Parser is a compiled Config
+type Parser struct {
+	cfg     Config
+	extract func(line string) (map[string]interface{}, bool)
+	tags    map[string]bool
+}
+
+// Compile validates a configuration and prepares its pattern
+func Compile(cfg Config) (*Parser, error) {
+	cfg = cfg.withDefaults()
+	if _, err := valueobjects.NewLogLevel(cfg.DefaultLevel); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	p := &Parser{cfg: cfg, tags: make(map[string]bool, len(cfg.TagFields))}
+	for _, field := range cfg.TagFields {
+		p.tags[field] = true
+	}
+
+	switch cfg.Format {
+	case FormatLogfmt:
+		p.extract = parseLogfmt
+	case FormatRegex:
+		if cfg.Pattern == "" {
+			return nil, fmt.Errorf("%w: regex format requires a pattern", ErrInvalidConfig)
+		}
+		re, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+		p.extract = regexExtractor(re)
+	case FormatGrok:
+		g, err := compileGrok(cfg.Pattern, cfg.Patterns)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+		p.extract = g.extract
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidConfig, cfg.Format)
+	}
+	return p, nil
+}
+
+// Config returns the configuration the parser was compiled from, with defaults applied
+func (p *Parser) Config() Config {
+	return p.cfg
+}
+
+// Parse extracts the fields of a line. Lines that do not match are returned
+// as the message at the default level, flagged with the unparsed metadata field.
+func (p *Parser) Parse(line string) Result {
+	fields, ok := p.extract(line)
+	if !ok {
+		return Result{
+			Message:  line,
+			Level:    p.cfg.DefaultLevel,
+			Metadata: map[string]interface{}{UnparsedField: true},
+		}
+	}
+
+	result := Result{Message: line, Level: p.cfg.DefaultLevel, Parsed: true}
+	if message, ok := takeString(fields, p.cfg.MessageFields); ok {
+		result.Message = message
+	}
+	if source, ok := takeString(fields, p.cfg.SourceFields); ok {
+		result.Source = source
+	}
+	for _, field := range p.cfg.LevelFields {
+		raw, ok := fields[field].(string)
+		if !ok {
+			continue
+		}
+		// Unrecognised levels stay in the metadata
+		if level, ok := valueobjects.ParseLogLevelAlias(raw); ok {
+			result.Level = level.String()
+			delete(fields, field)
+		}
+		break
+	}
+	for _, field := range p.cfg.TimestampFields {
+		raw, ok := fields[field].(string)
+		if !ok {
+			continue
+		}
+		if timestamp, err := time.Parse(p.cfg.TimestampLayout, raw); err == nil {
+			result.Timestamp = &timestamp
+			delete(fields, field)
+		}
+		break
+	}
+
+	for name, value := range fields {
+		if p.tags[name] {
+			if result.Tags == nil {
+				result.Tags = make(map[string]string)
+			}
+			result.Tags[name] = fmt.Sprint(value)
+			continue
+		}
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]interface{})
+		}
+		result.Metadata[name] = value
+	}
+	return result
+}
+
+func (c Config) withDefaults() Config {
+	if c.Format == "" {
+		c.Format = FormatLogfmt
+	}
+	if len(c.MessageFields) == 0 {
+		c.MessageFields = []string{"msg", "message"}
+	}
+	if len(c.LevelFields) == 0 {
+		c.LevelFields = []string{"level", "lvl", "severity"}
+	}
+	if len(c.SourceFields) == 0 {
+		c.SourceFields = []string{"source", "logger", "component"}
+	}
+	if len(c.TimestampFields) == 0 {
+		c.TimestampFields = []string{"time", "ts", "timestamp"}
+	}
+	if c.TimestampLayout == "" {
+		c.TimestampLayout = time.RFC3339Nano
+	}
+	if c.DefaultLevel == "" {
+		c.DefaultLevel = valueobjects.LogLevelInfo.String()
+	}
+	c.DefaultLevel = strings.ToUpper(c.DefaultLevel)
+	return c
+}
+
+// regexExtractor returns the non-empty named groups of a match
+func regexExtractor(re *regexp.Regexp) func(string) (map[string]interface{}, bool) {
+	names := re.SubexpNames()
+	return func(line string) (map[string]interface{}, bool) {
+		match := re.FindStringSubmatch(line)
+		if match == nil {
+			return nil, false
+		}
+		fields := make(map[string]interface{})
+		for i, name := range names {
+			if name != "" && match[i] != "" {
+				fields[name] = match[i]
+			}
+		}
+		return fields, true
+	}
+}
+
+// takeString removes and returns the first non-empty string field
+func takeString(fields map[string]interface{}, names []string) (string, bool) {
+	for _, name := range names {
+		if value, ok := fields[name].(string); ok && value != "" {
+			delete(fields, name)
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// convert applies a grok type suffix (int or float), keeping the string when conversion fails
+func convert(value, typ string) interface{} {
+	switch typ {
+	case "int":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case "float":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return value
+}
@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustCompile(t *testing.T, cfg Config) *Parser {
+	p, err := Compile(cfg)
+	if err != nil {
+		t.Fatalf("Failed to compile %+v: %v", cfg, err)
+	}
+	return p
+}
+
+func TestParser_Logfmt(t *testing.T) {
+	p := mustCompile(t, Config{TagFields: []string{"env"}})
+
+	result := p.Parse(`ts=2025-10-28T10:00:00.5Z level=warn logger=billing msg="payment \"retry\" scheduled" env=prod attempt=3 dry_run`)
+
+	if !result.Parsed {
+		t.Fatal("Expected line to be parsed")
+	}
+	if result.Message != `payment "retry" scheduled` || result.Level != "WARN" || result.Source != "billing" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if result.Timestamp == nil || !result.Timestamp.Equal(time.Date(2025, 10, 28, 10, 0, 0, 500000000, time.UTC)) {
+		t.Errorf("Expected parsed timestamp, got %v", result.Timestamp)
+	}
+	if result.Tags["env"] != "prod" {
+		t.Errorf("Expected env tag, got %v", result.Tags)
+	}
+	if result.Metadata["attempt"] != "3" || result.Metadata["dry_run"] != "true" {
+		t.Errorf("Expected remaining fields in metadata, got %v", result.Metadata)
+	}
+}
+
+func TestParser_LogfmtWithoutMessage(t *testing.T) {
+	p := mustCompile(t, Config{})
+
+	line := "level=bogus status=200"
+	result := p.Parse(line)
+
+	if result.Message != line || result.Level != "INFO" {
+		t.Errorf("Expected the line as message at the default level, got %+v", result)
+	}
+	if result.Metadata["level"] != "bogus" {
+		t.Errorf("Expected unrecognised level to stay in metadata, got %v", result.Metadata)
+	}
+}
+
+func TestParser_Regex(t *testing.T) {
+	p := mustCompile(t, Config{
+		Format:          FormatRegex,
+		Pattern:         `^\[(?P<time>[^\]]+)\] (?P<level>\w+) (?P<component>\w+): (?P<msg>.*)$`,
+		TimestampLayout: "2006-01-02 15:04:05",
+	})
+
+	result := p.Parse("[2025-10-28 10:00:00] ERROR db: connection lost")
+
+	if result.Message != "connection lost" || result.Level != "ERROR" || result.Source != "db" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if result.Timestamp == nil || result.Timestamp.Hour() != 10 {
+		t.Errorf("Expected timestamp with custom layout, got %v", result.Timestamp)
+	}
+}
+
+func TestParser_Grok(t *testing.T) {
+	p := mustCompile(t, Config{
+		Format:   FormatGrok,
+		Pattern:  `%{IPORHOST:client} %{WORD:method} %{URIPATHPARAM:path} %{NUMBER:status:int} %{NUMBER:duration:float}ms %{SERVICE:component}`,
+		Patterns: map[string]string{"SERVICE": `svc-%{WORD}`},
+	})
+
+	result := p.Parse("10.0.0.7 GET /api/v1/logs?limit=10 200 12.5ms svc-api")
+
+	if !result.Parsed || result.Source != "svc-api" {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	if result.Metadata["client"] != "10.0.0.7" || result.Metadata["path"] != "/api/v1/logs?limit=10" {
+		t.Errorf("Expected captured fields in metadata, got %v", result.Metadata)
+	}
+	if result.Metadata["status"] != int64(200) || result.Metadata["duration"] != 12.5 {
+		t.Errorf("Expected typed fields, got %#v and %#v", result.Metadata["status"], result.Metadata["duration"])
+	}
+}
+
+func TestParser_GrokTimestampAndLevel(t *testing.T) {
+	p := mustCompile(t, Config{Format: FormatGrok, Pattern: `%{TIMESTAMP_ISO8601:timestamp} +%{LOGLEVEL:level} %{GREEDYDATA:message}`})
+
+	result := p.Parse("2025-10-28T10:00:00Z  Warning disk almost full")
+
+	if result.Message != "disk almost full" || result.Level != "WARN" || result.Timestamp == nil {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestParser_UnmatchedLinesAreKept(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		line string
+	}{
+		{name: "Plain text as logfmt", cfg: Config{DefaultLevel: "warn"}, line: "something happened"},
+		{name: "Unterminated quote", cfg: Config{DefaultLevel: "warn"}, line: `msg="broken`},
+		{name: "Regex without match", cfg: Config{Format: FormatRegex, Pattern: `^\d+$`, DefaultLevel: "warn"}, line: "abc"},
+		{name: "Grok without match", cfg: Config{Format: FormatGrok, Pattern: `%{IPV4:ip}`, DefaultLevel: "warn"}, line: "no address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := mustCompile(t, tt.cfg).Parse(tt.line)
+
+			if result.Parsed || result.Message != tt.line || result.Level != "WARN" {
+				t.Errorf("Expected raw line at the default level, got %+v", result)
+			}
+			if result.Metadata[UnparsedField] != true {
+				t.Errorf("Expected unparsed flag, got %v", result.Metadata)
+			}
+		})
+	}
+}
+
+func TestCompile_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "Unknown format", cfg: Config{Format: "xml"}},
+		{name: "Regex without pattern", cfg: Config{Format: FormatRegex}},
+		{name: "Invalid regex", cfg: Config{Format: FormatRegex, Pattern: `(`}},
+		{name: "Unknown grok pattern", cfg: Config{Format: FormatGrok, Pattern: `%{NOPE:x}`}},
+		{name: "Recursive grok pattern", cfg: Config{Format: FormatGrok, Pattern: `%{LOOP}`, Patterns: map[string]string{"LOOP": `a%{LOOP}`}}},
+		{name: "Invalid default level", cfg: Config{DefaultLevel: "LOUD"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(tt.cfg); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("Expected ErrInvalidConfig, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRegistry_PerApplicationOverrides(t *testing.T) {
+	r, err := NewRegistry(Config{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := r.SetConfig("app-a", Config{Format: FormatRegex, Pattern: `^(?P<msg>.*)$`}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.SetConfig("app-b", Config{Format: "xml"}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+
+	if cfg, override := r.Config("app-a"); !override || cfg.Format != FormatRegex {
+		t.Errorf("Expected regex override, got %+v (%v)", cfg, override)
+	}
+	if cfg, override := r.Config("app-b"); override || cfg.Format != FormatLogfmt {
+		t.Errorf("Expected logfmt default, got %+v (%v)", cfg, override)
+	}
+
+	r.ResetConfig("app-a")
+	if _, override := r.Config("app-a"); override {
+		t.Error("Expected override to be removed")
+	}
+	if r.Parser("app-a") != r.Parser("app-b") {
+		t.Error("Expected both applications to share the default parser")
+	}
+}
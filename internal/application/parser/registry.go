@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"sync"
+)
+
+// Registry holds the compiled parser of every application, falling back to a
+// default parser for applications without an override
+type Registry struct {
+	mu        sync.RWMutex
+	defaults  *Parser
+	overrides map[string]*Parser
+}
+
+// NewRegistry compiles the default configuration
+func NewRegistry(defaults Config) (*Registry, error) {
+	p, err := Compile(defaults)
+	if err != nil {
+		return nil, err
+	}
+	return &Registry{
+		defaults:  p,
+		overrides: make(map[string]*Parser),
+	}, nil
+}
+
+// Parser returns the parser applied to an application's lines
+func (r *Registry) Parser(applicationID string) *Parser {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, ok := r.overrides[applicationID]; ok {
+		return p
+	}
+	return r.defaults
+}
+
+// Config returns the effective configuration for an application and whether it is an override
+func (r *Registry) Config(applicationID string) (Config, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, ok := r.overrides[applicationID]; ok {
+		return p.Config(), true
+	}
+	return r.defaults.Config(), false
+}
+
+// SetConfig compiles and installs an override for an application. Overrides
+// live in memory only and are lost when the process restarts.
+func (r *Registry) SetConfig(applicationID string, cfg Config) error {
+	p, err := Compile(cfg)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides[applicationID] = p
+	return nil
+}
+
+// ResetConfig removes the override for an application, falling back to the default parser
+func (r *Registry) ResetConfig(applicationID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.overrides, applicationID)
+}
@@ -0,0 +1,268 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	domainLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+)
+
+var (
+	ErrQueueFull = errors.New("ingestion queue is full")
+	ErrClosed    = errors.New("ingestion pipeline is closed")
+)
+
+const flushTimeout = 30 * time.Second
+
+// BatchWriter persists a batch of logs in a single round-trip
+type BatchWriter interface {
+	CreateMany(ctx context.Context, logs []*domainLog.Log) error
+}
+
+// Config controls queue capacity and flush behaviour
+type Config struct {
+	QueueSize     int
+	BatchSize     int
+	FlushInterval time.Duration
+	Workers       int
+}
+
+// DefaultConfig returns sensible defaults for the pipeline
+func DefaultConfig() Config {
+	return Config{
+		QueueSize:     10000,
+		BatchSize:     500,
+		FlushInterval: time.Second,
+		Workers:       2,
+	}
+}
+
+// Stats is a snapshot of the pipeline's state
+type Stats struct {
+	QueueDepth         int     `json:"queue_depth"`
+	QueueCapacity      int     `json:"queue_capacity"`
+	Enqueued           uint64  `json:"enqueued"`
+	Rejected           uint64  `json:"rejected"`
+	Flushed            uint64  `json:"flushed"`
+	Failed             uint64  `json:"failed"`
+	Batches            uint64  `json:"batches"`
+	LastFlushLatencyMs float64 `json:"last_flush_latency_ms"`
+	MaxFlushLatencyMs  float64 `json:"max_flush_latency_ms"`
+	AvgFlushLatencyMs  float64 `json:"avg_flush_latency_ms"`
+}
+
+// Pipeline buffers validated logs in a bounded in-process queue and flushes
+// them to the repository in batches, by size or by interval, from a pool of workers.
+type Pipeline struct {
+	writer BatchWriter
+	cfg    Config
+	queue  chan *domainLog.Log
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+
+	handlerMu sync.RWMutex
+	onFlushed func([]*domainLog.Log)
+	onFailed  func([]*domainLog.Log)
+
+	enqueued  atomic.Uint64
+	rejected  atomic.Uint64
+	flushed   atomic.Uint64
+	failed    atomic.Uint64
+	batches   atomic.Uint64
+	lastFlush atomic.Int64
+	maxFlush  atomic.Int64
+	sumFlush  atomic.Int64
+}
+
+// New creates a Pipeline and starts its workers
+func New(writer BatchWriter, cfg Config) *Pipeline {
+	defaults := DefaultConfig()
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaults.QueueSize
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaults.BatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaults.FlushInterval
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaults.Workers
+	}
+
+	p := &Pipeline{
+		writer: writer,
+		cfg:    cfg,
+		queue:  make(chan *domainLog.Log, cfg.QueueSize),
+	}
+
+	p.wg.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+// OnFlushed registers a callback invoked with every successfully persisted batch
+func (p *Pipeline) OnFlushed(fn func([]*domainLog.Log)) {
+	p.handlerMu.Lock()
+	defer p.handlerMu.Unlock()
+	p.onFlushed = fn
+}
+
+// OnFailed registers a callback invoked with every batch that could not be persisted
+func (p *Pipeline) OnFailed(fn func([]*domainLog.Log)) {
+	p.handlerMu.Lock()
+	defer p.handlerMu.Unlock()
+	p.onFailed = fn
+}
+
+// Enqueue adds a log to the queue without blocking
+func (p *Pipeline) Enqueue(l *domainLog.Log) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	select {
+	case p.queue <- l:
+		p.enqueued.Add(1)
+		return nil
+	default:
+		p.rejected.Add(1)
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting logs and waits until the queue is drained or ctx expires
+func (p *Pipeline) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns a snapshot of queue depth, throughput and flush latency
+func (p *Pipeline) Stats() Stats {
+	batches := p.batches.Load()
+	var avg float64
+	if batches > 0 {
+		avg = durationMs(time.Duration(p.sumFlush.Load() / int64(batches)))
+	}
+
+	return Stats{
+		QueueDepth:         len(p.queue),
+		QueueCapacity:      cap(p.queue),
+		Enqueued:           p.enqueued.Load(),
+		Rejected:           p.rejected.Load(),
+		Flushed:            p.flushed.Load(),
+		Failed:             p.failed.Load(),
+		Batches:            batches,
+		LastFlushLatencyMs: durationMs(time.Duration(p.lastFlush.Load())),
+		MaxFlushLatencyMs:  durationMs(time.Duration(p.maxFlush.Load())),
+		AvgFlushLatencyMs:  avg,
+	}
+}
+
+func (p *Pipeline) worker() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*domainLog.Log, 0, p.cfg.BatchSize)
+	for {
+		select {
+		case l, ok := <-p.queue:
+			if !ok {
+				p.flush(batch)
+				return
+			}
+			batch = append(batch, l)
+			if len(batch) >= p.cfg.BatchSize {
+				p.flush(batch)
+				batch = make([]*domainLog.Log, 0, p.cfg.BatchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				p.flush(batch)
+				batch = make([]*domainLog.Log, 0, p.cfg.BatchSize)
+			}
+		}
+	}
+}
+
+func (p *Pipeline) flush(batch []*domainLog.Log) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := p.writer.CreateMany(ctx, batch)
+	p.recordLatency(time.Since(start))
+
+	// Unordered inserts store the rest of a batch past duplicate IDs, so a
+	// duplicate-only failure still means every log is persisted
+	if errors.Is(err, domainLog.ErrDuplicateLog) {
+		err = nil
+	}
+
+	if err != nil {
+		p.failed.Add(uint64(len(batch)))
+		slog.Error("Failed to flush logs", "logs", len(batch), "error", err)
+	} else {
+		p.flushed.Add(uint64(len(batch)))
+	}
+
+	p.handlerMu.RLock()
+	handler := p.onFlushed
+	if err != nil {
+		handler = p.onFailed
+	}
+	p.handlerMu.RUnlock()
+	if handler != nil {
+		handler(batch)
+	}
+}
+
+func (p *Pipeline) recordLatency(d time.Duration) {
+	p.batches.Add(1)
+	p.lastFlush.Store(int64(d))
+	p.sumFlush.Add(int64(d))
+	for {
+		current := p.maxFlush.Load()
+		if int64(d) <= current || p.maxFlush.CompareAndSwap(current, int64(d)) {
+			return
+		}
+	}
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
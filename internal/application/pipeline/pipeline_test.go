@@ -0,0 +1,228 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	domainLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+type mockBatchWriter struct {
+	mu      sync.Mutex
+	batches [][]*domainLog.Log
+	err     error
+	block   chan struct{}
+}
+
+func (m *mockBatchWriter) CreateMany(ctx context.Context, logs []*domainLog.Log) error {
+	if m.block != nil {
+		<-m.block
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.batches = append(m.batches, logs)
+	return nil
+}
+
+func (m *mockBatchWriter) total() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, b := range m.batches {
+		n += len(b)
+	}
+	return n
+}
+
+func newTestLog(t *testing.T) *domainLog.Log {
+	l, err := domainLog.New("Pipeline test", valueobjects.LogLevelInfo, uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	return l
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("Condition not met before deadline")
+}
+
+func TestPipeline_FlushesBySize(t *testing.T) {
+	writer := &mockBatchWriter{}
+	p := New(writer, Config{QueueSize: 100, BatchSize: 5, FlushInterval: time.Hour, Workers: 1})
+	defer p.Close(context.Background())
+
+	for i := 0; i < 10; i++ {
+		if err := p.Enqueue(newTestLog(t)); err != nil {
+			t.Fatalf("Unexpected enqueue error: %v", err)
+		}
+	}
+
+	waitFor(t, func() bool { return writer.total() == 10 })
+
+	writer.mu.Lock()
+	defer writer.mu.Unlock()
+	if len(writer.batches) != 2 {
+		t.Errorf("Expected 2 batches, got %d", len(writer.batches))
+	}
+}
+
+func TestPipeline_FlushesByInterval(t *testing.T) {
+	writer := &mockBatchWriter{}
+	p := New(writer, Config{QueueSize: 100, BatchSize: 100, FlushInterval: 20 * time.Millisecond, Workers: 1})
+	defer p.Close(context.Background())
+
+	p.Enqueue(newTestLog(t))
+
+	waitFor(t, func() bool { return writer.total() == 1 })
+}
+
+func TestPipeline_CloseDrainsQueue(t *testing.T) {
+	writer := &mockBatchWriter{}
+	p := New(writer, Config{QueueSize: 100, BatchSize: 1000, FlushInterval: time.Hour, Workers: 3})
+
+	for i := 0; i < 50; i++ {
+		p.Enqueue(newTestLog(t))
+	}
+
+	if err := p.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected close error: %v", err)
+	}
+	if writer.total() != 50 {
+		t.Errorf("Expected 50 logs flushed on close, got %d", writer.total())
+	}
+	if err := p.Enqueue(newTestLog(t)); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed after close, got %v", err)
+	}
+}
+
+func TestPipeline_CloseRespectsDeadline(t *testing.T) {
+	writer := &mockBatchWriter{block: make(chan struct{})}
+	defer close(writer.block)
+	p := New(writer, Config{QueueSize: 10, BatchSize: 1, FlushInterval: time.Hour, Workers: 1})
+
+	p.Enqueue(newTestLog(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestPipeline_QueueFull(t *testing.T) {
+	writer := &mockBatchWriter{block: make(chan struct{})}
+	p := New(writer, Config{QueueSize: 2, BatchSize: 1, FlushInterval: time.Hour, Workers: 1})
+	defer func() {
+		close(writer.block)
+		p.Close(context.Background())
+	}()
+
+	// The worker takes one log and blocks in CreateMany, leaving room for two more
+	p.Enqueue(newTestLog(t))
+	waitFor(t, func() bool { return p.Stats().QueueDepth == 0 })
+	p.Enqueue(newTestLog(t))
+	p.Enqueue(newTestLog(t))
+
+	if err := p.Enqueue(newTestLog(t)); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+
+	stats := p.Stats()
+	if stats.QueueDepth != 2 || stats.QueueCapacity != 2 {
+		t.Errorf("Expected queue depth 2/2, got %d/%d", stats.QueueDepth, stats.QueueCapacity)
+	}
+	if stats.Rejected != 1 {
+		t.Errorf("Expected 1 rejected log, got %d", stats.Rejected)
+	}
+}
+
+func TestPipeline_OnFlushedAndStats(t *testing.T) {
+	writer := &mockBatchWriter{}
+	p := New(writer, Config{QueueSize: 10, BatchSize: 2, FlushInterval: time.Hour, Workers: 1})
+
+	var mu sync.Mutex
+	var notified int
+	p.OnFlushed(func(logs []*domainLog.Log) {
+		mu.Lock()
+		defer mu.Unlock()
+		notified += len(logs)
+	})
+
+	p.Enqueue(newTestLog(t))
+	p.Enqueue(newTestLog(t))
+	p.Close(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	if notified != 2 {
+		t.Errorf("Expected 2 logs notified, got %d", notified)
+	}
+
+	stats := p.Stats()
+	if stats.Enqueued != 2 || stats.Flushed != 2 || stats.Batches != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestPipeline_FailedFlushIsNotNotified(t *testing.T) {
+	writer := &mockBatchWriter{err: errors.New("insert failed")}
+	p := New(writer, Config{QueueSize: 10, BatchSize: 10, FlushInterval: time.Hour, Workers: 1})
+
+	notified := false
+	p.OnFlushed(func(logs []*domainLog.Log) { notified = true })
+	var failedLogs []*domainLog.Log
+	p.OnFailed(func(logs []*domainLog.Log) { failedLogs = logs })
+
+	p.Enqueue(newTestLog(t))
+	p.Close(context.Background())
+
+	if notified {
+		t.Error("Expected failed batch not to be notified")
+	}
+	if len(failedLogs) != 1 {
+		t.Errorf("Expected the failed batch to be reported, got %d logs", len(failedLogs))
+	}
+	if failed := p.Stats().Failed; failed != 1 {
+		t.Errorf("Expected 1 failed log, got %d", failed)
+	}
+}
+
+func TestPipeline_DuplicateOnlyFlushIsNotified(t *testing.T) {
+	writer := &mockBatchWriter{err: fmt.Errorf("some of 2 logs already exist: %w", domainLog.ErrDuplicateLog)}
+	p := New(writer, Config{QueueSize: 10, BatchSize: 10, FlushInterval: time.Hour, Workers: 1})
+
+	var flushedLogs []*domainLog.Log
+	p.OnFlushed(func(logs []*domainLog.Log) { flushedLogs = logs })
+	failed := false
+	p.OnFailed(func(logs []*domainLog.Log) { failed = true })
+
+	p.Enqueue(newTestLog(t))
+	p.Enqueue(newTestLog(t))
+	p.Close(context.Background())
+
+	if failed {
+		t.Error("Expected a batch stored past duplicate IDs not to be reported as failed")
+	}
+	if len(flushedLogs) != 2 {
+		t.Errorf("Expected the batch to be notified as flushed, got %d logs", len(flushedLogs))
+	}
+	if stats := p.Stats(); stats.Flushed != 2 || stats.Failed != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,285 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrInvalidPolicy is returned when a policy contains negative values.
+var ErrInvalidPolicy = errors.New("rate limit policy values cannot be negative")
+
+// evictInterval is how often Allow drops the state of idle applications
+const evictInterval = time.Minute
+
+// Limit kinds reported by ExceededError
+const (
+	LimitEntries = "entries"
+	LimitBytes   = "bytes"
+	LimitDaily   = "daily_quota"
+)
+
+// Policy describes the ingestion limits applied to a single application.
+// A zero value for any rate or quota means that dimension is unlimited.
+type Policy struct {
+	EntriesPerSecond float64 `json:"entries_per_second"`
+	EntriesBurst     int     `json:"entries_burst,omitempty"`
+	BytesPerSecond   float64 `json:"bytes_per_second"`
+	BytesBurst       int     `json:"bytes_burst,omitempty"`
+	DailyQuota       int64   `json:"daily_quota"`
+}
+
+// Validate checks that every value of the policy is usable
+func (p Policy) Validate() error {
+	if p.EntriesPerSecond < 0 || p.EntriesBurst < 0 || p.BytesPerSecond < 0 || p.BytesBurst < 0 || p.DailyQuota < 0 {
+		return ErrInvalidPolicy
+	}
+	return nil
+}
+
+// ExceededError is returned by Allow when an application goes over one of its limits.
+type ExceededError struct {
+	ApplicationID string
+	Kind          string
+	Limit         int64
+	Remaining     int64
+	RetryAfter    time.Duration
+	Reset         time.Time
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for application %s: %s limit of %d reached, retry after %s",
+		e.ApplicationID, e.Kind, e.Limit, e.RetryAfter)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accumulated since the last call, capped at burst
+func (b *bucket) refill(now time.Time, rate float64, burst int) {
+	if b.last.IsZero() {
+		b.tokens = float64(burst)
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(burst), b.tokens+elapsed*rate)
+	}
+	b.last = now
+}
+
+// full reports whether the bucket would be back at burst by now, in which
+// case forgetting it changes nothing
+func (b *bucket) full(now time.Time, rate float64, burst int) bool {
+	return rate <= 0 || b.last.IsZero() || b.tokens+now.Sub(b.last).Seconds()*rate >= float64(burst)
+}
+
+type state struct {
+	entries bucket
+	bytes   bucket
+	day     time.Time
+	used    int64
+}
+
+// idle reports whether the state is equivalent to a fresh one under p: both
+// buckets have refilled and nothing was counted against today's quota
+func (st *state) idle(now time.Time, p Policy) bool {
+	return st.entries.full(now, p.EntriesPerSecond, burstFor(p.EntriesPerSecond, p.EntriesBurst)) &&
+		st.bytes.full(now, p.BytesPerSecond, burstFor(p.BytesPerSecond, p.BytesBurst)) &&
+		(st.used == 0 || !st.day.Equal(now.UTC().Truncate(24*time.Hour)))
+}
+
+// Limiter enforces per-application token buckets for entries and bytes
+// per second plus a daily entry quota. Policies can be changed at runtime.
+type Limiter struct {
+	mu        sync.Mutex
+	defaults  Policy
+	overrides map[string]Policy
+	states    map[string]*state
+	lastEvict time.Time
+	now       func() time.Time
+}
+
+// NewLimiter creates a Limiter that applies the given default policy to every
+// application without an explicit override.
+func NewLimiter(defaults Policy) *Limiter {
+	return &Limiter{
+		defaults:  defaults,
+		overrides: make(map[string]Policy),
+		states:    make(map[string]*state),
+		now:       time.Now,
+	}
+}
+
+// Default returns the policy applied to applications without an override
+func (l *Limiter) Default() Policy {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.defaults
+}
+
+// SetDefault replaces the default policy
+func (l *Limiter) SetDefault(p Policy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.defaults = p
+	return nil
+}
+
+// Policy returns the effective policy for an application and whether it is an override
+func (l *Limiter) Policy(applicationID string) (Policy, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if p, ok := l.overrides[applicationID]; ok {
+		return p, true
+	}
+	return l.defaults, false
+}
+
+// SetPolicy installs an override for an application. Existing bucket state is
+// kept so that changing a policy does not grant a fresh burst. Overrides live
+// in memory only and are lost when the process restarts.
+func (l *Limiter) SetPolicy(applicationID string, p Policy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.overrides[applicationID] = p
+	return nil
+}
+
+// ResetPolicy removes the override for an application, falling back to the default policy
+func (l *Limiter) ResetPolicy(applicationID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.overrides, applicationID)
+}
+
+// Allow records an entry of the given size for an application. It returns an
+// *ExceededError without consuming anything if any limit would be exceeded.
+func (l *Limiter) Allow(applicationID string, size int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	p, ok := l.overrides[applicationID]
+	if !ok {
+		p = l.defaults
+	}
+
+	now := l.now()
+	if now.Sub(l.lastEvict) >= evictInterval {
+		l.evictIdle(now)
+	}
+	st, ok := l.states[applicationID]
+	if !ok {
+		st = &state{}
+		l.states[applicationID] = st
+	}
+
+	entriesBurst := burstFor(p.EntriesPerSecond, p.EntriesBurst)
+	if p.EntriesPerSecond > 0 {
+		st.entries.refill(now, p.EntriesPerSecond, entriesBurst)
+		if st.entries.tokens < 1 {
+			wait := secondsToDuration((1 - st.entries.tokens) / p.EntriesPerSecond)
+			return &ExceededError{
+				ApplicationID: applicationID,
+				Kind:          LimitEntries,
+				Limit:         int64(entriesBurst),
+				Remaining:     0,
+				RetryAfter:    wait,
+				Reset:         now.Add(wait),
+			}
+		}
+	}
+
+	// An entry larger than the burst can only pass with a full bucket
+	bytesBurst := burstFor(p.BytesPerSecond, p.BytesBurst)
+	need := float64(min(size, bytesBurst))
+	if p.BytesPerSecond > 0 {
+		st.bytes.refill(now, p.BytesPerSecond, bytesBurst)
+		if st.bytes.tokens < need {
+			wait := secondsToDuration((need - st.bytes.tokens) / p.BytesPerSecond)
+			return &ExceededError{
+				ApplicationID: applicationID,
+				Kind:          LimitBytes,
+				Limit:         int64(bytesBurst),
+				Remaining:     int64(st.bytes.tokens),
+				RetryAfter:    wait,
+				Reset:         now.Add(wait),
+			}
+		}
+	}
+
+	if p.DailyQuota > 0 {
+		day := now.UTC().Truncate(24 * time.Hour)
+		if !st.day.Equal(day) {
+			st.day = day
+			st.used = 0
+		}
+		if st.used >= p.DailyQuota {
+			reset := day.Add(24 * time.Hour)
+			return &ExceededError{
+				ApplicationID: applicationID,
+				Kind:          LimitDaily,
+				Limit:         p.DailyQuota,
+				Remaining:     0,
+				RetryAfter:    reset.Sub(now),
+				Reset:         reset,
+			}
+		}
+		st.used++
+	}
+
+	if p.EntriesPerSecond > 0 {
+		st.entries.tokens--
+	}
+	if p.BytesPerSecond > 0 {
+		st.bytes.tokens -= need
+	}
+	return nil
+}
+
+// Refund gives back the daily quota an allowed entry used, for entries that
+// were not stored after all. Rate tokens are kept, since the attempt still
+// cost the service work.
+func (l *Limiter) Refund(applicationID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	st, ok := l.states[applicationID]
+	if ok && st.used > 0 && st.day.Equal(l.now().UTC().Truncate(24*time.Hour)) {
+		st.used--
+	}
+}
+
+// evictIdle drops the state of applications that would start afresh anyway,
+// so that application IDs seen once do not stay in memory
+func (l *Limiter) evictIdle(now time.Time) {
+	l.lastEvict = now
+	for applicationID, st := range l.states {
+		p, ok := l.overrides[applicationID]
+		if !ok {
+			p = l.defaults
+		}
+		if st.idle(now, p) {
+			delete(l.states, applicationID)
+		}
+	}
+}
+
+// burstFor defaults the burst to one second worth of tokens
+func burstFor(rate float64, burst int) int {
+	if burst > 0 {
+		return burst
+	}
+	return max(1, int(math.Ceil(rate)))
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
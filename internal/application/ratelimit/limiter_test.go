@@ -0,0 +1,229 @@
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestLimiter(p Policy) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 10, 28, 10, 0, 0, 0, time.UTC)}
+	l := NewLimiter(p)
+	l.now = clock.Now
+	return l, clock
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	l, _ := newTestLimiter(Policy{})
+
+	for i := 0; i < 1000; i++ {
+		if err := l.Allow("app", 10_000); err != nil {
+			t.Fatalf("Expected unlimited policy to allow entry %d, got %v", i, err)
+		}
+	}
+}
+
+func TestLimiter_EntriesPerSecond(t *testing.T) {
+	l, clock := newTestLimiter(Policy{EntriesPerSecond: 2, EntriesBurst: 2})
+
+	for i := 0; i < 2; i++ {
+		if err := l.Allow("app", 1); err != nil {
+			t.Fatalf("Unexpected error within burst: %v", err)
+		}
+	}
+
+	err := l.Allow("app", 1)
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("Expected ExceededError, got %v", err)
+	}
+	if exceeded.Kind != LimitEntries {
+		t.Errorf("Expected kind '%s', got '%s'", LimitEntries, exceeded.Kind)
+	}
+	if exceeded.RetryAfter != 500*time.Millisecond {
+		t.Errorf("Expected retry after 500ms, got %v", exceeded.RetryAfter)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if err := l.Allow("app", 1); err != nil {
+		t.Errorf("Expected entry to be allowed after refill, got %v", err)
+	}
+}
+
+func TestLimiter_BytesPerSecond(t *testing.T) {
+	l, clock := newTestLimiter(Policy{BytesPerSecond: 100, BytesBurst: 100})
+
+	if err := l.Allow("app", 80); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err := l.Allow("app", 40)
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("Expected ExceededError, got %v", err)
+	}
+	if exceeded.Kind != LimitBytes {
+		t.Errorf("Expected kind '%s', got '%s'", LimitBytes, exceeded.Kind)
+	}
+	if exceeded.Remaining != 20 {
+		t.Errorf("Expected 20 remaining bytes, got %d", exceeded.Remaining)
+	}
+
+	clock.Advance(200 * time.Millisecond)
+	if err := l.Allow("app", 40); err != nil {
+		t.Errorf("Expected entry to be allowed after refill, got %v", err)
+	}
+}
+
+func TestLimiter_OversizedEntryNeedsFullBucket(t *testing.T) {
+	l, _ := newTestLimiter(Policy{BytesPerSecond: 100, BytesBurst: 100})
+
+	if err := l.Allow("app", 500); err != nil {
+		t.Errorf("Expected oversized entry to pass with a full bucket, got %v", err)
+	}
+	if err := l.Allow("app", 1); err == nil {
+		t.Error("Expected bucket to be drained by the oversized entry")
+	}
+}
+
+func TestLimiter_DailyQuota(t *testing.T) {
+	l, clock := newTestLimiter(Policy{DailyQuota: 3})
+
+	for i := 0; i < 3; i++ {
+		if err := l.Allow("app", 1); err != nil {
+			t.Fatalf("Unexpected error within quota: %v", err)
+		}
+	}
+
+	err := l.Allow("app", 1)
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("Expected ExceededError, got %v", err)
+	}
+	if exceeded.Kind != LimitDaily {
+		t.Errorf("Expected kind '%s', got '%s'", LimitDaily, exceeded.Kind)
+	}
+	expectedReset := time.Date(2025, 10, 29, 0, 0, 0, 0, time.UTC)
+	if !exceeded.Reset.Equal(expectedReset) {
+		t.Errorf("Expected reset at %v, got %v", expectedReset, exceeded.Reset)
+	}
+
+	clock.Advance(14 * time.Hour)
+	if err := l.Allow("app", 1); err != nil {
+		t.Errorf("Expected quota to reset on a new day, got %v", err)
+	}
+}
+
+func TestLimiter_Refund(t *testing.T) {
+	l, clock := newTestLimiter(Policy{DailyQuota: 1})
+
+	if err := l.Allow("app", 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	l.Refund("app")
+	if err := l.Allow("app", 1); err != nil {
+		t.Errorf("Expected a refunded entry to free its quota, got %v", err)
+	}
+
+	// A refund from yesterday does not add to today's quota
+	clock.Advance(24 * time.Hour)
+	l.Refund("app")
+	l.Allow("app", 1)
+	if err := l.Allow("app", 1); err == nil {
+		t.Error("Expected the quota to be exhausted")
+	}
+}
+
+func TestLimiter_RejectionDoesNotConsume(t *testing.T) {
+	l, _ := newTestLimiter(Policy{EntriesPerSecond: 10, DailyQuota: 1})
+
+	if err := l.Allow("app", 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		l.Allow("app", 1)
+	}
+
+	l.states["app"].entries.refill(l.now(), 10, 10)
+	if tokens := l.states["app"].entries.tokens; tokens != 9 {
+		t.Errorf("Expected rejected entries not to consume tokens, got %v tokens", tokens)
+	}
+}
+
+func TestLimiter_EvictsIdleApplications(t *testing.T) {
+	l, clock := newTestLimiter(Policy{EntriesPerSecond: 1, EntriesBurst: 5, DailyQuota: 100})
+
+	for i := 0; i < 3; i++ {
+		l.Allow("busy", 1)
+	}
+	l.Allow("quota", 1)
+
+	// After a minute the buckets have refilled, but today's quota is still counted
+	clock.Advance(evictInterval)
+	l.Allow("other", 1)
+	if _, ok := l.states["quota"]; !ok {
+		t.Error("Expected an application with quota used today to be kept")
+	}
+
+	clock.Advance(24 * time.Hour)
+	l.Allow("other", 1)
+	for _, applicationID := range []string{"busy", "quota"} {
+		if _, ok := l.states[applicationID]; ok {
+			t.Errorf("Expected idle application %s to be evicted", applicationID)
+		}
+	}
+	if _, ok := l.states["other"]; !ok {
+		t.Error("Expected the active application to be kept")
+	}
+}
+
+func TestLimiter_PerApplicationOverrides(t *testing.T) {
+	l, _ := newTestLimiter(Policy{DailyQuota: 1})
+
+	if err := l.SetPolicy("vip", Policy{}); err != nil {
+		t.Fatalf("Unexpected error setting policy: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := l.Allow("vip", 1); err != nil {
+			t.Fatalf("Expected override to lift the quota, got %v", err)
+		}
+	}
+
+	l.Allow("other", 1)
+	if err := l.Allow("other", 1); err == nil {
+		t.Error("Expected default quota to apply to other applications")
+	}
+
+	if _, override := l.Policy("vip"); !override {
+		t.Error("Expected vip to report an override")
+	}
+
+	l.ResetPolicy("vip")
+	if _, override := l.Policy("vip"); override {
+		t.Error("Expected override to be removed after reset")
+	}
+}
+
+func TestLimiter_InvalidPolicy(t *testing.T) {
+	l, _ := newTestLimiter(Policy{})
+
+	if err := l.SetPolicy("app", Policy{EntriesPerSecond: -1}); !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("Expected ErrInvalidPolicy, got %v", err)
+	}
+	if err := l.SetDefault(Policy{DailyQuota: -5}); !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("Expected ErrInvalidPolicy, got %v", err)
+	}
+}
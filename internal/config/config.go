@@ -0,0 +1,257 @@
+// Package config loads the service configuration from defaults, an optional
+// YAML file, environment variables and command-line flags, in increasing
+// order of precedence, and validates it at startup.
+//
+// Every setting has a YAML key, given by its path in the file (mongo.uri), a
+// flag of the same name (-mongo.uri) and usually an environment variable
+// (MONGO_URI). Empty environment variables count as unset.
+//
+// Settings tagged reload:"true" can change at runtime; see Reloader.
+package config
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Config struct {
+	// File is the configuration file that was loaded, if any
+	File string `yaml:"-"`
+
+	Server        ServerConfig        `yaml:"server"`
+	Log           LogConfig           `yaml:"log"`
+	TLS           TLSConfig           `yaml:"tls"`
+	Mongo         MongoConfig         `yaml:"mongo"`
+	CORS          CORSConfig          `yaml:"cors"`
+	SSE           SSEConfig           `yaml:"sse"`
+	Ingest        IngestConfig        `yaml:"ingest"`
+	RateLimit     RateLimitConfig     `yaml:"rate_limit"`
+	Spool         SpoolConfig         `yaml:"spool"`
+	Idempotency   IdempotencyConfig   `yaml:"idempotency"`
+	Timestamps    TimestampConfig     `yaml:"timestamps"`
+	Metrics       MetricsConfig       `yaml:"metrics"`
+	Text          TextConfig          `yaml:"text"`
+	GRPC          GRPCConfig          `yaml:"grpc"`
+	Syslog        SyslogConfig        `yaml:"syslog"`
+	GELF          GELFConfig          `yaml:"gelf"`
+	Fluentd       FluentdConfig       `yaml:"fluentd"`
+	Loki          LokiConfig          `yaml:"loki"`
+	Elasticsearch ElasticsearchConfig `yaml:"elasticsearch"`
+	Reload        ReloadConfig        `yaml:"reload"`
+}
+
+// ServerConfig covers the HTTP server. There is no write timeout, since SSE
+// responses stay open for as long as the client listens.
+type ServerConfig struct {
+	Port              int           `yaml:"port" env:"PORT"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	WebDir            string        `yaml:"web_dir" env:"WEB_DIR"`
+	// AdminToken is the bearer token of the administrative routes, which are
+	// disabled when it is empty
+	AdminToken string `yaml:"admin_token" env:"ADMIN_TOKEN" secret:"true"`
+}
+
+// LogConfig covers the service's own logs, written to stderr
+type LogConfig struct {
+	// Level is debug, info, warn or error
+	Level string `yaml:"level" env:"LOG_LEVEL" reload:"true"`
+	// Format is "text" for logfmt lines or "json"
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+type MongoConfig struct {
+	URI                    string        `yaml:"uri" env:"MONGO_URI" secret:"url"`
+	Database               string        `yaml:"database" env:"MONGO_DB_NAME"`
+	ConnectTimeout         time.Duration `yaml:"connect_timeout" env:"MONGO_CONNECT_TIMEOUT"`
+	ServerSelectionTimeout time.Duration `yaml:"server_selection_timeout" env:"MONGO_SERVER_SELECTION_TIMEOUT"`
+	MaxPoolSize            int           `yaml:"max_pool_size" env:"MONGO_MAX_POOL_SIZE"`
+	MinPoolSize            int           `yaml:"min_pool_size" env:"MONGO_MIN_POOL_SIZE"`
+}
+
+type CORSConfig struct {
+	// AllowedOrigins lists origins such as https://app.example.com, or "*" for any
+	AllowedOrigins []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" reload:"true"`
+	// Applications replaces AllowedOrigins for the routes of the listed application IDs
+	Applications map[string][]string `yaml:"applications" env:"CORS_APPLICATION_ORIGINS" reload:"true"`
+}
+
+// TLSConfig enables HTTPS on the HTTP server. Certificates are read again when
+// their files change, and client certificates can be verified against a CA.
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"TLS_KEY_FILE"`
+	// ReloadInterval polls the certificate files for changes; 0 reloads on SIGHUP only
+	ReloadInterval time.Duration `yaml:"reload_interval" env:"TLS_RELOAD_INTERVAL"`
+	// ClientCAFile enables mutual TLS with the CA certificates client certificates must chain to
+	ClientCAFile string `yaml:"client_ca_file" env:"TLS_CLIENT_CA_FILE"`
+	// ClientAuth is "optional" to verify certificates that clients present, or "require"
+	ClientAuth string `yaml:"client_auth" env:"TLS_CLIENT_AUTH"`
+	// ClientApplications binds client certificate identities (a URI, DNS or email
+	// SAN, or the subject common name) to the application they may ingest for
+	ClientApplications map[string]uuid.UUID `yaml:"client_applications" env:"TLS_CLIENT_APPLICATIONS" reload:"true"`
+}
+
+type SSEConfig struct {
+	// MaxClients caps the event streams open at once; 0 is unlimited
+	MaxClients int `yaml:"max_clients" env:"SSE_MAX_CLIENTS" reload:"true"`
+	// MaxClientsPerApplication caps the event streams of a single application; 0 is unlimited
+	MaxClientsPerApplication int `yaml:"max_clients_per_application" env:"SSE_MAX_CLIENTS_PER_APPLICATION" reload:"true"`
+	// BufferSize is the number of events an application stream queues before new ones
+	// are dropped; a reload applies to streams opened afterwards
+	BufferSize int `yaml:"buffer_size" env:"SSE_BUFFER_SIZE" reload:"true"`
+	// OrderBy is the time field each flushed batch is sorted by before publishing.
+	// It only applies in async ingest mode and does not order logs across batches.
+	OrderBy string `yaml:"order_by" env:"SSE_ORDER_BY"`
+}
+
+type IngestConfig struct {
+	// Mode is "sync" or "async" (write-behind queue)
+	Mode          string        `yaml:"mode" env:"INGEST_MODE"`
+	QueueSize     int           `yaml:"queue_size" env:"INGEST_QUEUE_SIZE"`
+	BatchSize     int           `yaml:"batch_size" env:"INGEST_BATCH_SIZE"`
+	FlushInterval time.Duration `yaml:"flush_interval" env:"INGEST_FLUSH_INTERVAL"`
+	Workers       int           `yaml:"workers" env:"INGEST_WORKERS"`
+}
+
+type RateLimitConfig struct {
+	EntriesPerSecond float64 `yaml:"entries_per_sec" env:"RATE_LIMIT_ENTRIES_PER_SEC" reload:"true"`
+	EntriesBurst     int     `yaml:"entries_burst" env:"RATE_LIMIT_ENTRIES_BURST" reload:"true"`
+	BytesPerSecond   float64 `yaml:"bytes_per_sec" env:"RATE_LIMIT_BYTES_PER_SEC" reload:"true"`
+	BytesBurst       int     `yaml:"bytes_burst" env:"RATE_LIMIT_BYTES_BURST" reload:"true"`
+	DailyQuota       int64   `yaml:"daily_quota" env:"RATE_LIMIT_DAILY_QUOTA" reload:"true"`
+}
+
+type SpoolConfig struct {
+	// Dir enables the disk spool for inserts that fail
+	Dir            string        `yaml:"dir" env:"SPOOL_DIR"`
+	MaxBytes       int64         `yaml:"max_bytes" env:"SPOOL_MAX_BYTES"`
+	SegmentBytes   int64         `yaml:"segment_bytes" env:"SPOOL_SEGMENT_BYTES"`
+	ReplayInterval time.Duration `yaml:"replay_interval" env:"SPOOL_REPLAY_INTERVAL"`
+}
+
+type IdempotencyConfig struct {
+	Window time.Duration `yaml:"window" env:"IDEMPOTENCY_WINDOW"`
+}
+
+type TimestampConfig struct {
+	MaxPast   time.Duration `yaml:"max_past" env:"TIMESTAMP_MAX_PAST" reload:"true"`
+	MaxFuture time.Duration `yaml:"max_future" env:"TIMESTAMP_MAX_FUTURE" reload:"true"`
+}
+
+type MetricsConfig struct {
+	MaxApplications int `yaml:"max_applications" env:"METRICS_MAX_APPLICATIONS"`
+}
+
+type TextConfig struct {
+	Format       string `yaml:"format" env:"TEXT_PARSER_FORMAT"`
+	Pattern      string `yaml:"pattern" env:"TEXT_PARSER_PATTERN"`
+	DefaultLevel string `yaml:"default_level" env:"TEXT_DEFAULT_LEVEL"`
+}
+
+type GRPCConfig struct {
+	Port int `yaml:"port" env:"GRPC_PORT"`
+}
+
+type SyslogConfig struct {
+	UDPAddr string `yaml:"udp_addr" env:"SYSLOG_UDP_ADDR"`
+	TCPAddr string `yaml:"tcp_addr" env:"SYSLOG_TCP_ADDR"`
+	TLSAddr string `yaml:"tls_addr" env:"SYSLOG_TLS_ADDR"`
+	// ApplicationID is used by listeners without their own application ID
+	ApplicationID    uuid.UUID `yaml:"application_id" env:"SYSLOG_APPLICATION_ID"`
+	UDPApplicationID uuid.UUID `yaml:"udp_application_id" env:"SYSLOG_UDP_APPLICATION_ID"`
+	TCPApplicationID uuid.UUID `yaml:"tcp_application_id" env:"SYSLOG_TCP_APPLICATION_ID"`
+	TLSApplicationID uuid.UUID `yaml:"tls_application_id" env:"SYSLOG_TLS_APPLICATION_ID"`
+	UserID           uuid.UUID `yaml:"user_id" env:"SYSLOG_USER_ID"`
+	// Senders routes hostnames or addresses to applications, e.g. router-1=<uuid>
+	Senders     map[string]uuid.UUID `yaml:"senders" env:"SYSLOG_SENDERS"`
+	TLSCertFile string               `yaml:"tls_cert_file" env:"SYSLOG_TLS_CERT_FILE"`
+	TLSKeyFile  string               `yaml:"tls_key_file" env:"SYSLOG_TLS_KEY_FILE"`
+}
+
+type GELFConfig struct {
+	UDPAddr       string    `yaml:"udp_addr" env:"GELF_UDP_ADDR"`
+	TCPAddr       string    `yaml:"tcp_addr" env:"GELF_TCP_ADDR"`
+	ApplicationID uuid.UUID `yaml:"application_id" env:"GELF_APPLICATION_ID"`
+	UserID        uuid.UUID `yaml:"user_id" env:"GELF_USER_ID"`
+}
+
+type FluentdConfig struct {
+	ForwardAddr       string    `yaml:"forward_addr" env:"FLUENTD_FORWARD_ADDR"`
+	MessageFields     []string  `yaml:"message_fields" env:"FLUENTD_MESSAGE_FIELDS"`
+	LevelFields       []string  `yaml:"level_fields" env:"FLUENTD_LEVEL_FIELDS"`
+	SourceFields      []string  `yaml:"source_fields" env:"FLUENTD_SOURCE_FIELDS"`
+	ApplicationFields []string  `yaml:"application_fields" env:"FLUENTD_APPLICATION_FIELDS"`
+	UserFields        []string  `yaml:"user_fields" env:"FLUENTD_USER_FIELDS"`
+	ApplicationID     uuid.UUID `yaml:"application_id" env:"FLUENTD_APPLICATION_ID"`
+	UserID            uuid.UUID `yaml:"user_id" env:"FLUENTD_USER_ID"`
+	TLSCertFile       string    `yaml:"tls_cert_file" env:"FLUENTD_TLS_CERT_FILE"`
+	TLSKeyFile        string    `yaml:"tls_key_file" env:"FLUENTD_TLS_KEY_FILE"`
+}
+
+type LokiConfig struct {
+	ApplicationLabel string    `yaml:"application_label" env:"LOKI_APPLICATION_LABEL"`
+	ApplicationID    uuid.UUID `yaml:"application_id" env:"LOKI_APPLICATION_ID"`
+	UserID           uuid.UUID `yaml:"user_id" env:"LOKI_USER_ID"`
+}
+
+type ElasticsearchConfig struct {
+	MessageFields     []string  `yaml:"message_fields" env:"ES_MESSAGE_FIELDS"`
+	LevelFields       []string  `yaml:"level_fields" env:"ES_LEVEL_FIELDS"`
+	TimestampFields   []string  `yaml:"timestamp_fields" env:"ES_TIMESTAMP_FIELDS"`
+	SourceFields      []string  `yaml:"source_fields" env:"ES_SOURCE_FIELDS"`
+	ApplicationFields []string  `yaml:"application_fields" env:"ES_APPLICATION_FIELDS"`
+	UserFields        []string  `yaml:"user_fields" env:"ES_USER_FIELDS"`
+	ApplicationID     uuid.UUID `yaml:"application_id" env:"ES_APPLICATION_ID"`
+	UserID            uuid.UUID `yaml:"user_id" env:"ES_USER_ID"`
+}
+
+type ReloadConfig struct {
+	// WatchInterval polls the configuration file for changes; 0 reloads on SIGHUP only
+	WatchInterval time.Duration `yaml:"watch_interval" env:"CONFIG_WATCH_INTERVAL"`
+}
+
+// Default returns the settings used when nothing else is configured. Zero
+// values of other settings select each component's own default.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:              8080,
+			ReadHeaderTimeout: 10 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Mongo: MongoConfig{
+			ConnectTimeout: 10 * time.Second,
+		},
+		TLS: TLSConfig{
+			ReloadInterval: time.Minute,
+			ClientAuth:     "optional",
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+		},
+		SSE: SSEConfig{
+			BufferSize: 1024,
+		},
+		Ingest: IngestConfig{
+			Mode: "sync",
+		},
+		Idempotency: IdempotencyConfig{
+			Window: 24 * time.Hour,
+		},
+		Metrics: MetricsConfig{
+			MaxApplications: 100,
+		},
+		GRPC: GRPCConfig{
+			Port: 9090,
+		},
+	}
+}
@@ -0,0 +1,263 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func getenvFrom(env map[string]string) func(string) string {
+	return func(key string) string {
+		return env[key]
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+var requiredEnv = map[string]string{
+	"MONGO_URI":     "mongodb://localhost:27017",
+	"MONGO_DB_NAME": "logs",
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load("test", nil, io.Discard, getenvFrom(requiredEnv))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cfg.Server.Port != 8080 || cfg.GRPC.Port != 9090 {
+		t.Errorf("Expected default ports 8080 and 9090, got %d and %d", cfg.Server.Port, cfg.GRPC.Port)
+	}
+	if cfg.Idempotency.Window != 24*time.Hour {
+		t.Errorf("Expected a 24h idempotency window, got %v", cfg.Idempotency.Window)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 1 || cfg.CORS.AllowedOrigins[0] != "*" {
+		t.Errorf("Expected any origin to be allowed by default, got %v", cfg.CORS.AllowedOrigins)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, `
+server:
+  port: 7000
+  shutdown_timeout: 10s
+mongo:
+  uri: mongodb://file:27017
+  database: from-file
+rate_limit:
+  entries_per_sec: 50
+`)
+	env := map[string]string{
+		"CONFIG_FILE":   path,
+		"PORT":          "7100",
+		"MONGO_DB_NAME": "from-env",
+		"GRPC_PORT":     "",
+	}
+
+	cfg, err := Load("test", []string{"-server.port=7200", "-sse.max_clients", "5"}, io.Discard, getenvFrom(env))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cfg.Server.Port != 7200 {
+		t.Errorf("Expected the flag to override the environment, got port %d", cfg.Server.Port)
+	}
+	if cfg.Mongo.Database != "from-env" {
+		t.Errorf("Expected the environment to override the file, got %q", cfg.Mongo.Database)
+	}
+	if cfg.Mongo.URI != "mongodb://file:27017" || cfg.Server.ShutdownTimeout != 10*time.Second || cfg.RateLimit.EntriesPerSecond != 50 {
+		t.Errorf("Expected file settings to override defaults, got %+v", cfg)
+	}
+	if cfg.GRPC.Port != 9090 {
+		t.Errorf("Expected an empty environment variable to be ignored, got port %d", cfg.GRPC.Port)
+	}
+	if cfg.SSE.MaxClients != 5 {
+		t.Errorf("Expected 5 SSE clients, got %d", cfg.SSE.MaxClients)
+	}
+}
+
+func TestLoad_ParsesTypes(t *testing.T) {
+	appID := uuid.New()
+	env := map[string]string{
+		"MONGO_URI":             "mongodb://localhost:27017",
+		"MONGO_DB_NAME":         "logs",
+		"SPOOL_MAX_BYTES":       "1e9",
+		"INGEST_FLUSH_INTERVAL": "250ms",
+		"CORS_ALLOWED_ORIGINS":  "https://a.example.com, https://b.example.com",
+		"SYSLOG_SENDERS":        "router-1=" + appID.String() + ", 10.0.0.5=" + appID.String(),
+		"LOKI_APPLICATION_ID":   appID.String(),
+		"CORS_APPLICATION_ORIGINS": appID.String() + "=https://a.example.com https://c.example.com; " +
+			uuid.NewString() + "=*",
+	}
+
+	cfg, err := Load("test", nil, io.Discard, getenvFrom(env))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cfg.Spool.MaxBytes != 1e9 {
+		t.Errorf("Expected 1e9 spool bytes, got %d", cfg.Spool.MaxBytes)
+	}
+	if cfg.Ingest.FlushInterval != 250*time.Millisecond {
+		t.Errorf("Expected a 250ms flush interval, got %v", cfg.Ingest.FlushInterval)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 2 || cfg.CORS.AllowedOrigins[1] != "https://b.example.com" {
+		t.Errorf("Expected two trimmed origins, got %v", cfg.CORS.AllowedOrigins)
+	}
+	if len(cfg.Syslog.Senders) != 2 || cfg.Syslog.Senders["router-1"] != appID {
+		t.Errorf("Expected two senders, got %v", cfg.Syslog.Senders)
+	}
+	if origins := cfg.CORS.Applications[appID.String()]; len(cfg.CORS.Applications) != 2 || len(origins) != 2 || origins[1] != "https://c.example.com" {
+		t.Errorf("Expected per-application origins, got %v", cfg.CORS.Applications)
+	}
+	if cfg.Loki.ApplicationID != appID {
+		t.Errorf("Expected application ID %s, got %s", appID, cfg.Loki.ApplicationID)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		file    string
+		wantErr []string
+	}{
+		{
+			name:    "Missing MongoDB settings",
+			env:     map[string]string{"MONGO_URI": "", "MONGO_DB_NAME": ""},
+			wantErr: []string{"mongo.uri", "mongo.database"},
+		},
+		{
+			name:    "Unparseable values",
+			env:     map[string]string{"PORT": "http", "IDEMPOTENCY_WINDOW": "1 day", "SYSLOG_SENDERS": "router-1"},
+			wantErr: []string{"PORT", "IDEMPOTENCY_WINDOW", "SYSLOG_SENDERS"},
+		},
+		{
+			name:    "Invalid flag value",
+			args:    []string{"-sse.buffer_size=many"},
+			wantErr: []string{"-sse.buffer_size"},
+		},
+		{
+			name: "Invalid values are all reported",
+			env: map[string]string{
+				"PORT":                     "70000",
+				"INGEST_MODE":              "batch",
+				"SSE_ORDER_BY":             "id",
+				"RATE_LIMIT_BYTES_PER_SEC": "-1",
+				"CORS_ALLOWED_ORIGINS":     "example.com",
+				"SYSLOG_UDP_ADDR":          ":5514",
+				"LOG_LEVEL":                "verbose",
+				"LOG_FORMAT":               "xml",
+			},
+			wantErr: []string{"server.port", "ingest.mode", "sse.order_by", "rate_limit.bytes_per_sec", "cors.allowed_origins", "syslog.user_id", "log.level", "log.format"},
+		},
+		{
+			name: "Invalid TLS settings",
+			env: map[string]string{
+				"TLS_CERT_FILE":            "server.crt",
+				"TLS_CLIENT_AUTH":          "always",
+				"TLS_CLIENT_APPLICATIONS":  "checkout=" + uuid.NewString(),
+				"CORS_APPLICATION_ORIGINS": "checkout=https://a.example.com",
+			},
+			wantErr: []string{"tls.key_file", "tls.client_auth", "tls.client_applications", `"checkout" is not an application ID`},
+		},
+		{
+			name:    "Publish order in sync mode",
+			env:     map[string]string{"INGEST_MODE": "sync", "SSE_ORDER_BY": "timestamp"},
+			wantErr: []string{"sse.order_by requires ingest.mode async"},
+		},
+		{
+			name:    "Unknown file key",
+			file:    "mongo:\n  url: mongodb://localhost\n",
+			wantErr: []string{"field url not found"},
+		},
+		{
+			name:    "Unexpected argument",
+			args:    []string{"serve"},
+			wantErr: []string{"unexpected arguments"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := make(map[string]string)
+			for k, v := range requiredEnv {
+				env[k] = v
+			}
+			for k, v := range tt.env {
+				env[k] = v
+			}
+			if tt.file != "" {
+				env["CONFIG_FILE"] = writeFile(t, tt.file)
+			}
+
+			_, err := Load("test", tt.args, io.Discard, getenvFrom(env))
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected the error to mention %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_Help(t *testing.T) {
+	var output strings.Builder
+	_, err := Load("test", []string{"-h"}, &output, getenvFrom(requiredEnv))
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Fatalf("Expected flag.ErrHelp, got %v", err)
+	}
+	if !strings.Contains(output.String(), "-mongo.uri") || !strings.Contains(output.String(), "env MONGO_URI") {
+		t.Errorf("Expected the usage to list settings with their environment variables, got:\n%s", output.String())
+	}
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	cfg := Default()
+	cfg.Mongo.URI = "mongodb://admin:s3cret@db:27017/?authSource=admin&tlsCertificateKeyFilePassword=hunter2"
+	cfg.Mongo.Database = "logs"
+
+	out := cfg.Redacted()
+	if strings.Contains(out, "s3cret") || strings.Contains(out, "hunter2") {
+		t.Errorf("Expected credentials to be masked, got:\n%s", out)
+	}
+	for _, want := range []string{"admin:REDACTED@db:27017", "authSource=admin", "database: logs", "shutdown_timeout: 30s", "allowed_origins: ['*']"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestConfig_Redacted_RoundTrip(t *testing.T) {
+	cfg := Default()
+	cfg.Mongo.URI = "mongodb://db:27017"
+	cfg.Mongo.Database = "logs"
+	cfg.Syslog.Senders = map[string]uuid.UUID{"router-1": uuid.New()}
+	cfg.Fluentd.MessageFields = []string{"log", "message"}
+
+	loaded, err := Load("test", nil, io.Discard, getenvFrom(map[string]string{"CONFIG_FILE": writeFile(t, cfg.Redacted())}))
+	if err != nil {
+		t.Fatalf("Expected the rendered configuration to load, got %v", err)
+	}
+	if loaded.Redacted() != cfg.Redacted() {
+		t.Errorf("Expected the same configuration after loading, got:\n%s\nwant:\n%s", loaded.Redacted(), cfg.Redacted())
+	}
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// field is one leaf setting, addressed by its dotted YAML path
+type field struct {
+	key        string
+	env        string
+	secret     string
+	reloadable bool
+	value      reflect.Value
+}
+
+// Load builds the configuration from defaults, the YAML file named by
+// -config or CONFIG_FILE, the environment read through getenv, and the flags
+// in args, then validates it. flag.ErrHelp is returned when -h is passed.
+func Load(name string, args []string, output io.Writer, getenv func(string) string) (*Config, error) {
+	cfg := Default()
+	fields := collect(reflect.ValueOf(&cfg).Elem(), "")
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	configFile := fs.String("config", getenv("CONFIG_FILE"), "YAML configuration file (env CONFIG_FILE)")
+	overrides := make(map[string]string)
+	var order []string
+	for _, f := range fields {
+		usage := "see the configuration reference"
+		if f.env != "" {
+			usage = "env " + f.env
+		}
+		if f.reloadable {
+			usage += ", reloadable"
+		}
+		fs.Func(f.key, usage, func(value string) error {
+			if _, ok := overrides[f.key]; !ok {
+				order = append(order, f.key)
+			}
+			overrides[f.key] = value
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	if *configFile != "" {
+		if err := loadFile(&cfg, *configFile); err != nil {
+			return nil, err
+		}
+		cfg.File = *configFile
+	}
+
+	var errs []error
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+		if value := getenv(f.env); value != "" {
+			if err := set(f.value, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+			}
+		}
+	}
+
+	byKey := make(map[string]field, len(fields))
+	for _, f := range fields {
+		byKey[f.key] = f
+	}
+	for _, key := range order {
+		if err := set(byKey[key].value, overrides[key]); err != nil {
+			errs = append(errs, fmt.Errorf("-%s: %w", key, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// loadFile overlays the settings present in a YAML file; unknown keys are
+// rejected so that typos do not go unnoticed
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// collect lists the leaf settings of a struct, depth first in declaration order
+func collect(v reflect.Value, prefix string) []field {
+	var fields []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key, _, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		fv := v.Field(i)
+		if sf.Type.Kind() == reflect.Struct && sf.Type != uuidType {
+			fields = append(fields, collect(fv, key)...)
+			continue
+		}
+		fields = append(fields, field{
+			key:        key,
+			env:        sf.Tag.Get("env"),
+			secret:     sf.Tag.Get("secret"),
+			reloadable: sf.Tag.Get("reload") == "true",
+			value:      fv,
+		})
+	}
+	return fields
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	uuidType     = reflect.TypeOf(uuid.UUID{})
+	sendersType  = reflect.TypeOf(map[string]uuid.UUID{})
+	originsType  = reflect.TypeOf(map[string][]string{})
+)
+
+// set parses an environment variable or flag value into a setting. Lists
+// are comma-separated, and maps are written as key=value pairs; maps of
+// lists separate their entries with semicolons and list items with spaces.
+func set(v reflect.Value, value string) error {
+	switch v.Type() {
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("must be a duration such as 500ms or 1h, got %q", value)
+		}
+		v.SetInt(int64(d))
+		return nil
+	case uuidType:
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return fmt.Errorf("must be a UUID, got %q", value)
+		}
+		v.Set(reflect.ValueOf(id))
+		return nil
+	case sendersType:
+		senders := make(map[string]uuid.UUID)
+		for _, entry := range splitList(value) {
+			key, rawID, ok := strings.Cut(entry, "=")
+			id, err := uuid.Parse(strings.TrimSpace(rawID))
+			if !ok || err != nil || strings.TrimSpace(key) == "" {
+				return fmt.Errorf("entries must be formatted as name=<application uuid>, got %q", entry)
+			}
+			senders[strings.TrimSpace(key)] = id
+		}
+		v.Set(reflect.ValueOf(senders))
+		return nil
+	case originsType:
+		lists := make(map[string][]string)
+		for _, entry := range strings.Split(value, ";") {
+			if entry = strings.TrimSpace(entry); entry == "" {
+				continue
+			}
+			key, items, ok := strings.Cut(entry, "=")
+			if !ok || strings.TrimSpace(key) == "" {
+				return fmt.Errorf("entries must be formatted as name=<item> <item>, got %q", entry)
+			}
+			lists[strings.TrimSpace(key)] = strings.Fields(items)
+		}
+		v.Set(reflect.ValueOf(lists))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			// Sizes were historically read as floats, e.g. 1e9 bytes
+			f, ferr := strconv.ParseFloat(value, 64)
+			if ferr != nil || f != float64(int64(f)) {
+				return fmt.Errorf("must be an integer, got %q", value)
+			}
+			n = int64(f)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("must be a number, got %q", value)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		v.Set(reflect.ValueOf(splitList(value)))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"bytes"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "REDACTED"
+
+// Redacted renders the effective configuration as YAML, in the layout of the
+// config file, with credentials masked so that it is safe to log
+func (c Config) Redacted() string {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node(reflect.ValueOf(c), "")); err != nil {
+		return "# failed to render configuration: " + err.Error() + "\n"
+	}
+	encoder.Close()
+	return buf.String()
+}
+
+func node(v reflect.Value, secret string) *yaml.Node {
+	switch v.Type() {
+	case durationType:
+		return scalar(time.Duration(v.Int()).String(), "")
+	case uuidType:
+		id := v.Interface().(uuid.UUID)
+		if id == uuid.Nil {
+			// null rather than "", which is not a valid UUID when the output is loaded back
+			return scalar("null", "!!null")
+		}
+		return scalar(id.String(), "")
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		mapping := &yaml.Node{Kind: yaml.MappingNode}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			key := sf.Tag.Get("yaml")
+			if key == "" || key == "-" {
+				continue
+			}
+			mapping.Content = append(mapping.Content, scalar(key, ""), node(v.Field(i), sf.Tag.Get("secret")))
+		}
+		return mapping
+	case reflect.Map:
+		mapping := &yaml.Node{Kind: yaml.MappingNode}
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			mapping.Content = append(mapping.Content, scalar(k, "!!str"), node(v.MapIndex(reflect.ValueOf(k)), ""))
+		}
+		return mapping
+	case reflect.Slice:
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for i := 0; i < v.Len(); i++ {
+			seq.Content = append(seq.Content, node(v.Index(i), ""))
+		}
+		return seq
+	case reflect.String:
+		return scalar(redact(v.String(), secret), "!!str")
+	case reflect.Int, reflect.Int64:
+		return scalar(strconv.FormatInt(v.Int(), 10), "")
+	case reflect.Float64:
+		return scalar(strconv.FormatFloat(v.Float(), 'g', -1, 64), "")
+	}
+	return scalar("", "!!str")
+}
+
+func scalar(value, tag string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value, Tag: tag}
+}
+
+// redact masks secret values; for URLs only the password and password-like
+// query parameters are masked, so that the host stays visible
+func redact(value, secret string) string {
+	if value == "" || secret == "" {
+		return value
+	}
+	if secret == "url" {
+		u, err := url.Parse(value)
+		if err != nil {
+			return redacted
+		}
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+		}
+		query := u.Query()
+		for key := range query {
+			if lower := strings.ToLower(key); strings.Contains(lower, "password") || strings.Contains(lower, "secret") {
+				query.Set(key, redacted)
+			}
+		}
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+	return redacted
+}
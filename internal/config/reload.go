@@ -0,0 +1,231 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reload triggers reported in ReloadStatus
+const (
+	TriggerStartup = "startup"
+	TriggerSignal  = "signal"
+	TriggerFile    = "file"
+	TriggerAPI     = "api"
+)
+
+// ReloadStatus reports the outcome of the latest reload
+type ReloadStatus struct {
+	Time    time.Time `json:"time"`
+	Trigger string    `json:"trigger"`
+	Success bool      `json:"success"`
+	Error   string    `json:"error,omitempty"`
+	// Applied lists the settings the reload changed
+	Applied []string `json:"applied"`
+	// RestartRequired lists changed settings that only take effect after a restart
+	RestartRequired []string `json:"restart_required,omitempty"`
+	// EnvOverrides lists reloadable settings pinned by environment variables,
+	// which take precedence over the file
+	EnvOverrides []string `json:"env_overrides,omitempty"`
+}
+
+// ReloadMetrics records reload outcomes, such as Prometheus counters
+type ReloadMetrics interface {
+	ConfigReloaded(success bool)
+}
+
+// ReloaderOption configures optional Reloader behaviour
+type ReloaderOption func(*Reloader)
+
+// WithReloadMetrics reports every reload attempt
+func WithReloadMetrics(m ReloadMetrics) ReloaderOption {
+	return func(r *Reloader) {
+		r.metrics = m
+	}
+}
+
+// WithEnv reports the reloadable settings that environment variables read
+// through getenv override, since changing them in the file has no effect
+func WithEnv(getenv func(string) string) ReloaderOption {
+	return func(r *Reloader) {
+		r.getenv = getenv
+	}
+}
+
+// Reloader loads the configuration again on demand and applies the settings
+// tagged reload:"true" to the running components. A reload is all or
+// nothing: the new configuration is validated as a whole before anything is
+// applied, and components are restored to the previous settings when one of
+// them refuses the change.
+type Reloader struct {
+	load    func() (*Config, error)
+	metrics ReloadMetrics
+	getenv  func(string) string
+
+	mu       sync.Mutex
+	current  Config
+	appliers []func(*Config) error
+	status   atomic.Pointer[ReloadStatus]
+}
+
+// NewReloader starts from the configuration the service was started with;
+// load is called on every reload, typically with the same sources as Load.
+func NewReloader(current *Config, load func() (*Config, error), opts ...ReloaderOption) *Reloader {
+	r := &Reloader{load: load, current: *current}
+	for _, opt := range opts {
+		opt(r)
+	}
+	status := &ReloadStatus{Time: time.Now(), Trigger: TriggerStartup, Success: true, EnvOverrides: r.envOverrides()}
+	if len(status.EnvOverrides) > 0 && current.File != "" {
+		slog.Warn("Environment variables override reloadable settings of the configuration file", "env_overrides", status.EnvOverrides)
+	}
+	r.status.Store(status)
+	return r
+}
+
+// OnReload registers a component that applies reloadable settings. apply is
+// called with the complete configuration whenever a reloadable setting
+// changes, and must replace its settings atomically.
+func (r *Reloader) OnReload(apply func(*Config) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.appliers = append(r.appliers, apply)
+}
+
+// Status returns the outcome of the latest reload
+func (r *Reloader) Status() ReloadStatus {
+	return *r.status.Load()
+}
+
+// Current returns the configuration in effect
+func (r *Reloader) Current() Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Reload loads the configuration and applies the reloadable settings that changed
+func (r *Reloader) Reload(trigger string) ReloadStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := ReloadStatus{Time: time.Now(), Trigger: trigger, Applied: []string{}, EnvOverrides: r.envOverrides()}
+	if err := r.reload(&status); err != nil {
+		status.Error = err.Error()
+		slog.Error("Configuration reload failed, keeping the previous settings", "trigger", trigger, "error", err)
+	} else {
+		status.Success = true
+		slog.Info("Configuration reloaded", "trigger", trigger, "applied", status.Applied, "restart_required", status.RestartRequired, "env_overrides", status.EnvOverrides)
+	}
+	if r.metrics != nil {
+		r.metrics.ConfigReloaded(status.Success)
+	}
+	r.status.Store(&status)
+	return status
+}
+
+func (r *Reloader) reload(status *ReloadStatus) error {
+	next, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	updated := r.current
+	status.Applied, status.RestartRequired = merge(&updated, next)
+	if len(status.Applied) == 0 {
+		return nil
+	}
+
+	for i, apply := range r.appliers {
+		if err := apply(&updated); err != nil {
+			for _, undo := range r.appliers[:i] {
+				undo(&r.current)
+			}
+			status.Applied = []string{}
+			return err
+		}
+	}
+	r.current = updated
+	return nil
+}
+
+// Watch reloads whenever the configuration file changes, polling it every
+// interval until ctx is done. It returns immediately when no file was loaded.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	path := r.Current().File
+	if path == "" || interval <= 0 {
+		return
+	}
+
+	last, _ := os.Stat(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			// Editors may replace the file; wait for it to reappear
+			continue
+		}
+		if last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+			continue
+		}
+		last = info
+		r.Reload(TriggerFile)
+	}
+}
+
+// envOverrides lists the reloadable settings whose environment variable is
+// set, as "key (ENV)"
+func (r *Reloader) envOverrides() []string {
+	if r.getenv == nil {
+		return nil
+	}
+	var overrides []string
+	for _, f := range collect(reflect.ValueOf(&r.current).Elem(), "") {
+		if f.reloadable && f.env != "" && r.getenv(f.env) != "" {
+			overrides = append(overrides, f.key+" ("+f.env+")")
+		}
+	}
+	return overrides
+}
+
+// merge copies the reloadable settings of next into dst, returning the keys
+// it changed and the changed keys that need a restart
+func merge(dst, next *Config) (applied, restartRequired []string) {
+	applied = []string{}
+	current := collect(reflect.ValueOf(dst).Elem(), "")
+	incoming := collect(reflect.ValueOf(next).Elem(), "")
+	for i, f := range current {
+		if equal(f.value, incoming[i].value) {
+			continue
+		}
+		if !f.reloadable {
+			restartRequired = append(restartRequired, f.key)
+			continue
+		}
+		f.value.Set(incoming[i].value)
+		applied = append(applied, f.key)
+	}
+	return applied, restartRequired
+}
+
+// equal compares two settings, treating nil and empty lists or maps alike
+func equal(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice, reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
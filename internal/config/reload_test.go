@@ -0,0 +1,173 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// reloadFrom returns a reloader whose load step returns the given configurations in turn
+func reloadFrom(t *testing.T, start Config, next ...func(*Config) error) *Reloader {
+	t.Helper()
+	i := 0
+	return NewReloader(&start, func() (*Config, error) {
+		cfg := start
+		err := next[i](&cfg)
+		i++
+		if err != nil {
+			return nil, err
+		}
+		return &cfg, nil
+	})
+}
+
+func validConfig() Config {
+	cfg := Default()
+	cfg.Mongo.URI = "mongodb://localhost:27017"
+	cfg.Mongo.Database = "logs"
+	return cfg
+}
+
+func TestReloader_AppliesReloadableSettings(t *testing.T) {
+	r := reloadFrom(t, validConfig(), func(c *Config) error {
+		c.RateLimit.EntriesPerSecond = 10
+		c.CORS.AllowedOrigins = []string{"https://app.example.com"}
+		c.Server.Port = 9000
+		return nil
+	})
+
+	var applied []*Config
+	r.OnReload(func(c *Config) error {
+		applied = append(applied, c)
+		return nil
+	})
+
+	status := r.Reload(TriggerSignal)
+	if !status.Success || status.Trigger != TriggerSignal {
+		t.Fatalf("Expected a successful reload, got %+v", status)
+	}
+	if want := []string{"cors.allowed_origins", "rate_limit.entries_per_sec"}; !reflect.DeepEqual(status.Applied, want) {
+		t.Errorf("Expected applied settings %v, got %v", want, status.Applied)
+	}
+	if want := []string{"server.port"}; !reflect.DeepEqual(status.RestartRequired, want) {
+		t.Errorf("Expected %v to require a restart, got %v", want, status.RestartRequired)
+	}
+	if len(applied) != 1 || applied[0].RateLimit.EntriesPerSecond != 10 {
+		t.Fatalf("Expected the new settings to be applied once, got %v", applied)
+	}
+	if applied[0].Server.Port != 8080 || r.Current().Server.Port != 8080 {
+		t.Errorf("Expected settings that need a restart to keep their value, got %d", r.Current().Server.Port)
+	}
+	if !reflect.DeepEqual(r.Status(), status) {
+		t.Errorf("Expected the status to be kept, got %+v", r.Status())
+	}
+}
+
+func TestReloader_NoChanges(t *testing.T) {
+	r := reloadFrom(t, validConfig(), func(c *Config) error { return nil })
+	r.OnReload(func(c *Config) error {
+		t.Error("Expected components not to be called without changes")
+		return nil
+	})
+
+	if status := r.Reload(TriggerAPI); !status.Success || len(status.Applied) != 0 {
+		t.Errorf("Expected a successful reload without changes, got %+v", status)
+	}
+}
+
+func TestReloader_InvalidConfigurationKeepsSettings(t *testing.T) {
+	r := reloadFrom(t, validConfig(), func(c *Config) error { return errors.New("sse.buffer_size must be at least 1") })
+	r.OnReload(func(c *Config) error {
+		t.Error("Expected nothing to be applied")
+		return nil
+	})
+
+	status := r.Reload(TriggerFile)
+	if status.Success || status.Error == "" {
+		t.Errorf("Expected a failed reload with an error, got %+v", status)
+	}
+	if r.Current().RateLimit != validConfig().RateLimit {
+		t.Error("Expected the previous settings to be kept")
+	}
+}
+
+func TestReloader_RollsBackWhenAComponentRefuses(t *testing.T) {
+	r := reloadFrom(t, validConfig(), func(c *Config) error {
+		c.SSE.MaxClients = 10
+		return nil
+	})
+
+	var first []int
+	r.OnReload(func(c *Config) error {
+		first = append(first, c.SSE.MaxClients)
+		return nil
+	})
+	r.OnReload(func(c *Config) error { return errors.New("refused") })
+
+	status := r.Reload(TriggerAPI)
+	if status.Success || len(status.Applied) != 0 {
+		t.Errorf("Expected a failed reload without applied settings, got %+v", status)
+	}
+	if want := []int{10, 0}; !reflect.DeepEqual(first, want) {
+		t.Errorf("Expected the first component to be restored, got %v", first)
+	}
+	if r.Current().SSE.MaxClients != 0 {
+		t.Errorf("Expected the previous settings to be kept, got %d", r.Current().SSE.MaxClients)
+	}
+}
+
+func TestReloader_WatchesFile(t *testing.T) {
+	path := writeFile(t, "mongo:\n  uri: mongodb://localhost:27017\n  database: logs\n")
+	load := func() (*Config, error) {
+		return Load("test", []string{"-config", path}, nil, getenvFrom(nil))
+	}
+	cfg, err := load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	reloaded := make(chan *Config, 1)
+	r := NewReloader(cfg, load)
+	r.OnReload(func(c *Config) error {
+		reloaded <- c
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx, 10*time.Millisecond)
+
+	time.Sleep(30 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("mongo:\n  uri: mongodb://localhost:27017\n  database: logs\nrate_limit:\n  daily_quota: 1000\n"), 0o600); err != nil {
+		t.Fatalf("Failed to update config file: %v", err)
+	}
+
+	select {
+	case c := <-reloaded:
+		if c.RateLimit.DailyQuota != 1000 {
+			t.Errorf("Expected the new daily quota, got %d", c.RateLimit.DailyQuota)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the file change to be reloaded")
+	}
+}
+
+func TestReloader_ReportsEnvOverrides(t *testing.T) {
+	env := map[string]string{"RATE_LIMIT_ENTRIES_PER_SEC": "5", "MONGO_URI": "mongodb://localhost"}
+	start := validConfig()
+	r := NewReloader(&start, func() (*Config, error) {
+		cfg := start
+		return &cfg, nil
+	}, WithEnv(func(key string) string { return env[key] }))
+
+	want := []string{"rate_limit.entries_per_sec (RATE_LIMIT_ENTRIES_PER_SEC)"}
+	if got := r.Status().EnvOverrides; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected startup status to list %v, got %v", want, got)
+	}
+	if got := r.Reload(TriggerFile).EnvOverrides; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected reload status to list %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+	domainLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+)
+
+// Validate reports every invalid setting at once
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	for _, f := range collect(reflect.ValueOf(c).Elem(), "") {
+		switch f.value.Kind() {
+		case reflect.Int, reflect.Int64:
+			if f.value.Int() < 0 {
+				fail("%s must not be negative", f.key)
+			}
+		case reflect.Float64:
+			if f.value.Float() < 0 {
+				fail("%s must not be negative", f.key)
+			}
+		}
+	}
+
+	if c.Mongo.URI == "" {
+		fail("mongo.uri (MONGO_URI) is required")
+	} else if !strings.HasPrefix(c.Mongo.URI, "mongodb://") && !strings.HasPrefix(c.Mongo.URI, "mongodb+srv://") {
+		fail("mongo.uri must start with mongodb:// or mongodb+srv://")
+	}
+	if c.Mongo.Database == "" {
+		fail("mongo.database (MONGO_DB_NAME) is required")
+	}
+	if c.Mongo.MaxPoolSize > 0 && c.Mongo.MinPoolSize > c.Mongo.MaxPoolSize {
+		fail("mongo.min_pool_size must not exceed mongo.max_pool_size")
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		fail("server.port must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	if c.GRPC.Port < 1 || c.GRPC.Port > 65535 {
+		fail("grpc.port must be between 1 and 65535, got %d", c.GRPC.Port)
+	}
+	if c.Server.ShutdownTimeout == 0 {
+		fail("server.shutdown_timeout must be positive")
+	}
+	if c.Idempotency.Window == 0 {
+		fail("idempotency.window must be positive")
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		fail("log.level must be 'debug', 'info', 'warn' or 'error', got %q", c.Log.Level)
+	}
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		fail("log.format must be 'text' or 'json', got %q", c.Log.Format)
+	}
+
+	if len(c.CORS.AllowedOrigins) == 0 {
+		fail("cors.allowed_origins must list at least one origin, or *")
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			fail("cors.allowed_origins: %v", err)
+		}
+	}
+
+	for applicationID, origins := range c.CORS.Applications {
+		if _, err := uuid.Parse(applicationID); err != nil {
+			fail("cors.applications: %q is not an application ID", applicationID)
+		}
+		for _, origin := range origins {
+			if err := validateOrigin(origin); err != nil {
+				fail("cors.applications.%s: %v", applicationID, err)
+			}
+		}
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		fail("tls.cert_file and tls.key_file must be set together")
+	}
+	if c.TLS.ClientCAFile != "" && c.TLS.CertFile == "" {
+		fail("tls.client_ca_file requires tls.cert_file and tls.key_file")
+	}
+	if c.TLS.ClientAuth != "optional" && c.TLS.ClientAuth != "require" {
+		fail("tls.client_auth must be 'optional' or 'require', got %q", c.TLS.ClientAuth)
+	}
+	if len(c.TLS.ClientApplications) > 0 && c.TLS.ClientCAFile == "" {
+		fail("tls.client_applications requires tls.client_ca_file")
+	}
+
+	if c.SSE.BufferSize < 1 {
+		fail("sse.buffer_size must be at least 1")
+	}
+	if c.SSE.OrderBy != "" && !domainLog.TimeField(c.SSE.OrderBy).IsValid() {
+		fail("sse.order_by must be 'timestamp' or 'ingested_at', got %q", c.SSE.OrderBy)
+	}
+	if c.SSE.OrderBy != "" && c.Ingest.Mode == "sync" {
+		fail("sse.order_by requires ingest.mode async, since sync ingestion publishes each log on its own")
+	}
+	if c.Ingest.Mode != "sync" && c.Ingest.Mode != "async" {
+		fail("ingest.mode must be 'sync' or 'async', got %q", c.Ingest.Mode)
+	}
+
+	if c.Syslog.UDPAddr != "" || c.Syslog.TCPAddr != "" || c.Syslog.TLSAddr != "" {
+		if c.Syslog.UserID == uuid.Nil {
+			fail("syslog.user_id (SYSLOG_USER_ID) is required when a syslog listener is enabled")
+		}
+	}
+	if c.Syslog.TLSAddr != "" && (c.Syslog.TLSCertFile == "" || c.Syslog.TLSKeyFile == "") {
+		fail("syslog.tls_cert_file and syslog.tls_key_file are required with syslog.tls_addr")
+	}
+	if (c.Fluentd.TLSCertFile == "") != (c.Fluentd.TLSKeyFile == "") {
+		fail("fluentd.tls_cert_file and fluentd.tls_key_file must be set together")
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateOrigin accepts "*" or a scheme://host[:port] origin without a path
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		return fmt.Errorf("%q must be * or an origin such as https://app.example.com", origin)
+	}
+	return nil
+}
@@ -13,7 +13,8 @@ type Log struct {
 	ID            uuid.UUID              `bson:"_id" json:"id"`
 	Message       string                 `bson:"message" json:"message"`
 	Level         valueobjects.LogLevel  `bson:"level" json:"level"`
-	Timestamp     time.Time              `bson:"timestamp" json:"timestamp"`
+	Timestamp     time.Time              `bson:"timestamp" json:"timestamp"`     // Event time, reported by the client or defaulted to IngestedAt
+	IngestedAt    time.Time              `bson:"ingested_at" json:"ingested_at"` // Time the service received the log
 	ApplicationID uuid.UUID              `bson:"application_id" json:"application_id"`
 	UserID        uuid.UUID              `bson:"user_id" json:"user_id"`
 	Source        string                 `bson:"source,omitempty" json:"source,omitempty"`     // Optional: source component/service
@@ -38,11 +39,13 @@ func New(message string, level valueobjects.LogLevel, applicationID, userID uuid
 		return nil, ErrUserIDInvalid
 	}
 
+	now := time.Now()
 	return &Log{
 		ID:            uuid.New(),
 		Message:       message,
 		Level:         level,
-		Timestamp:     time.Now(),
+		Timestamp:     now,
+		IngestedAt:    now,
 		ApplicationID: applicationID,
 		UserID:        userID,
 		Tags:          make(map[string]string),
@@ -8,6 +8,13 @@ var (
 	ErrApplicationIDInvalid = errors.New("application ID is required and must be a valid UUID")
 	ErrUserIDInvalid        = errors.New("user ID is required and must be a valid UUID")
 	ErrLogNotFound          = errors.New("log not found")
+	ErrDuplicateLog         = errors.New("log with this ID already exists")
+	ErrIdempotencyKeyLength = errors.New("idempotency key must be at most 255 characters")
 	ErrInvalidDateRange     = errors.New("invalid date range")
 	ErrInvalidPagination    = errors.New("invalid pagination parameters")
+	ErrInvalidTimeField     = errors.New("time field must be 'timestamp' or 'ingested_at'")
+	ErrInvalidSortOrder     = errors.New("sort order must be 'asc' or 'desc'")
+	ErrInvalidTagFilter     = errors.New("tag filter keys cannot be empty or contain '.' or '$'")
+	ErrTimestampOutOfRange  = errors.New("log timestamp is outside the accepted clock skew")
+	ErrApplicationForbidden = errors.New("client is not authorized to ingest logs for this application")
 )
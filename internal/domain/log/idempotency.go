@@ -0,0 +1,25 @@
+package log
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyRecord remembers the log created for a client-supplied key so that
+// retried requests within the idempotency window return the original entry.
+type IdempotencyRecord struct {
+	ApplicationID uuid.UUID `bson:"application_id" json:"application_id"`
+	Key           string    `bson:"key" json:"key"`
+	Log           *Log      `bson:"log" json:"log"`
+	CreatedAt     time.Time `bson:"created_at" json:"created_at"`
+}
+
+type IdempotencyStore interface {
+	// Reserve stores the record unless the key is already taken within the
+	// window, in which case the existing record is returned instead.
+	Reserve(ctx context.Context, record *IdempotencyRecord) (*IdempotencyRecord, error)
+	// Release frees a key whose log could not be persisted so that it can be retried.
+	Release(ctx context.Context, applicationID uuid.UUID, key string) error
+}
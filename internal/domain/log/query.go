@@ -0,0 +1,91 @@
+package log
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+// TimeField selects which timestamp a query filters and sorts on
+type TimeField string
+
+const (
+	// TimeFieldTimestamp is the event time reported by the client
+	TimeFieldTimestamp TimeField = "timestamp"
+	// TimeFieldIngestedAt is the time the service received the log
+	TimeFieldIngestedAt TimeField = "ingested_at"
+)
+
+const (
+	DefaultQueryLimit = 100
+	MaxQueryLimit     = 1000
+)
+
+// IsValid checks if the time field is supported
+func (f TimeField) IsValid() bool {
+	return f == TimeFieldTimestamp || f == TimeFieldIngestedAt
+}
+
+// Of returns the value of the time field for a log
+func (f TimeField) Of(l *Log) time.Time {
+	if f == TimeFieldIngestedAt {
+		return l.IngestedAt
+	}
+	return l.Timestamp
+}
+
+// Query describes a search over an application's historical logs
+type Query struct {
+	ApplicationID uuid.UUID
+	From          time.Time // Optional: inclusive lower bound on TimeField
+	To            time.Time // Optional: exclusive upper bound on TimeField
+	Levels        []valueobjects.LogLevel
+	Source        string
+	Tags          map[string]string
+	Search        string // Optional: case-insensitive substring of the message
+	TimeField     TimeField
+	Descending    bool
+	Limit         int
+	Offset        int
+}
+
+// Validate checks the query and fills in defaults
+func (q *Query) Validate() error {
+	if q.ApplicationID == (uuid.UUID{}) {
+		return ErrApplicationIDInvalid
+	}
+
+	if q.TimeField == "" {
+		q.TimeField = TimeFieldTimestamp
+	}
+	if !q.TimeField.IsValid() {
+		return ErrInvalidTimeField
+	}
+
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return ErrInvalidDateRange
+	}
+
+	for key := range q.Tags {
+		if key == "" || strings.ContainsAny(key, ".$") {
+			return ErrInvalidTagFilter
+		}
+	}
+
+	for _, level := range q.Levels {
+		if !level.IsValid() {
+			return ErrLevelRequired
+		}
+	}
+
+	if q.Limit == 0 {
+		q.Limit = DefaultQueryLimit
+	}
+	if q.Limit < 0 || q.Limit > MaxQueryLimit || q.Offset < 0 {
+		return ErrInvalidPagination
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+func TestQuery_Validate(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		query         Query
+		expectedError error
+	}{
+		{name: "Valid query", query: Query{ApplicationID: uuid.New(), From: now.Add(-time.Hour), To: now}},
+		{name: "Missing application ID", query: Query{}, expectedError: ErrApplicationIDInvalid},
+		{name: "Invalid time field", query: Query{ApplicationID: uuid.New(), TimeField: "created_at"}, expectedError: ErrInvalidTimeField},
+		{name: "Inverted date range", query: Query{ApplicationID: uuid.New(), From: now, To: now.Add(-time.Hour)}, expectedError: ErrInvalidDateRange},
+		{name: "Nested tag key", query: Query{ApplicationID: uuid.New(), Tags: map[string]string{"a.b": "c"}}, expectedError: ErrInvalidTagFilter},
+		{name: "Operator tag key", query: Query{ApplicationID: uuid.New(), Tags: map[string]string{"$ne": "c"}}, expectedError: ErrInvalidTagFilter},
+		{name: "Invalid level", query: Query{ApplicationID: uuid.New(), Levels: []valueobjects.LogLevel{"LOUD"}}, expectedError: ErrLevelRequired},
+		{name: "Limit too large", query: Query{ApplicationID: uuid.New(), Limit: MaxQueryLimit + 1}, expectedError: ErrInvalidPagination},
+		{name: "Negative offset", query: Query{ApplicationID: uuid.New(), Offset: -1}, expectedError: ErrInvalidPagination},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.query.Validate()
+			if err != tt.expectedError {
+				t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestQuery_ValidateDefaults(t *testing.T) {
+	query := Query{ApplicationID: uuid.New()}
+	if err := query.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if query.TimeField != TimeFieldTimestamp {
+		t.Errorf("Expected default time field %s, got %s", TimeFieldTimestamp, query.TimeField)
+	}
+	if query.Limit != DefaultQueryLimit {
+		t.Errorf("Expected default limit %d, got %d", DefaultQueryLimit, query.Limit)
+	}
+}
+
+func TestTimestampPolicy_Check(t *testing.T) {
+	policy := TimestampPolicy{MaxPast: time.Hour, MaxFuture: time.Minute}
+
+	tests := []struct {
+		name        string
+		policy      TimestampPolicy
+		offset      time.Duration
+		expectError bool
+	}{
+		{name: "Same as ingestion", policy: policy, offset: 0},
+		{name: "Within past bound", policy: policy, offset: -59 * time.Minute},
+		{name: "Beyond past bound", policy: policy, offset: -2 * time.Hour, expectError: true},
+		{name: "Within future bound", policy: policy, offset: 30 * time.Second},
+		{name: "Beyond future bound", policy: policy, offset: 2 * time.Minute, expectError: true},
+		{name: "Unbounded policy", policy: TimestampPolicy{}, offset: -24 * 365 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := New("Test log message", valueobjects.LogLevelInfo, uuid.New(), uuid.New())
+			l.Timestamp = l.IngestedAt.Add(tt.offset)
+
+			err := tt.policy.Check(l)
+			if tt.expectError && err != ErrTimestampOutOfRange {
+				t.Errorf("Expected ErrTimestampOutOfRange, got %v", err)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
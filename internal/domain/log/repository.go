@@ -6,4 +6,6 @@ import (
 
 type LogRepository interface {
 	Create(ctx context.Context, log *Log) error
+	CreateMany(ctx context.Context, logs []*Log) error
+	Find(ctx context.Context, query Query) ([]*Log, error)
 }
@@ -0,0 +1,21 @@
+package log
+
+import "time"
+
+// TimestampPolicy bounds how far a client-supplied event timestamp may be from
+// the ingestion time. A zero bound means that direction is not checked.
+type TimestampPolicy struct {
+	MaxPast   time.Duration
+	MaxFuture time.Duration
+}
+
+// Check validates the log's event timestamp against its ingestion time
+func (p TimestampPolicy) Check(l *Log) error {
+	if p.MaxPast > 0 && l.Timestamp.Before(l.IngestedAt.Add(-p.MaxPast)) {
+		return ErrTimestampOutOfRange
+	}
+	if p.MaxFuture > 0 && l.Timestamp.After(l.IngestedAt.Add(p.MaxFuture)) {
+		return ErrTimestampOutOfRange
+	}
+	return nil
+}
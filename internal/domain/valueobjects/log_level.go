@@ -1,10 +1,14 @@
 package valueobjects
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
 
+// ErrInvalidLogLevel is wrapped by NewLogLevel when the level is not recognised
+var ErrInvalidLogLevel = errors.New("invalid log level")
+
 // LogLevel represents the severity level of a log entry
 type LogLevel string
 
@@ -34,12 +38,54 @@ func NewLogLevel(level string) (LogLevel, error) {
 	normalized := LogLevel(strings.ToUpper(strings.TrimSpace(level)))
 
 	if !normalized.IsValid() {
-		return "", fmt.Errorf("invalid log level '%s', valid levels are: %v", level, ValidLogLevels())
+		return "", fmt.Errorf("%w '%s', valid levels are: %v", ErrInvalidLogLevel, level, ValidLogLevels())
 	}
 
 	return normalized, nil
 }
 
+// ParseLogLevelAlias recognises the level names used by common logging
+// libraries and shippers (e.g. "warning", "err", "critical", "notice") in
+// addition to the canonical levels
+func ParseLogLevelAlias(level string) (LogLevel, bool) {
+	if normalized, err := NewLogLevel(level); err == nil {
+		return normalized, true
+	}
+
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "trc":
+		return LogLevelTrace, true
+	case "dbg":
+		return LogLevelDebug, true
+	case "information", "informational", "notice", "inf":
+		return LogLevelInfo, true
+	case "warning", "wrn":
+		return LogLevelWarn, true
+	case "err", "eror":
+		return LogLevelError, true
+	case "crit", "critical", "alert", "emerg", "emergency", "panic", "ftl":
+		return LogLevelFatal, true
+	}
+	return "", false
+}
+
+// LogLevelFromSyslogSeverity maps a syslog severity (0 emergency to 7 debug):
+// emergency, alert and critical become FATAL, notice and informational become INFO
+func LogLevelFromSyslogSeverity(severity int) LogLevel {
+	switch {
+	case severity <= 2:
+		return LogLevelFatal
+	case severity == 3:
+		return LogLevelError
+	case severity == 4:
+		return LogLevelWarn
+	case severity <= 6:
+		return LogLevelInfo
+	default:
+		return LogLevelDebug
+	}
+}
+
 // IsValid checks if the log level is valid
 func (l LogLevel) IsValid() bool {
 	for _, valid := range ValidLogLevels() {
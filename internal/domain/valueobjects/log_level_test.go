@@ -234,3 +234,46 @@ func TestValidLogLevels(t *testing.T) {
 		}
 	}
 }
+
+func TestParseLogLevelAlias(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected LogLevel
+		ok       bool
+	}{
+		{input: "info", expected: LogLevelInfo, ok: true},
+		{input: "Warning", expected: LogLevelWarn, ok: true},
+		{input: "err", expected: LogLevelError, ok: true},
+		{input: "CRITICAL", expected: LogLevelFatal, ok: true},
+		{input: "notice", expected: LogLevelInfo, ok: true},
+		{input: "dbg", expected: LogLevelDebug, ok: true},
+		{input: "verbose", ok: false},
+		{input: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		level, ok := ParseLogLevelAlias(tt.input)
+		if ok != tt.ok || level != tt.expected {
+			t.Errorf("ParseLogLevelAlias(%q) = %q, %v; expected %q, %v", tt.input, level, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestLogLevelFromSyslogSeverity(t *testing.T) {
+	expected := []LogLevel{
+		LogLevelFatal, // emergency
+		LogLevelFatal, // alert
+		LogLevelFatal, // critical
+		LogLevelError,
+		LogLevelWarn,
+		LogLevelInfo, // notice
+		LogLevelInfo,
+		LogLevelDebug,
+	}
+
+	for severity, level := range expected {
+		if result := LogLevelFromSyslogSeverity(severity); result != level {
+			t.Errorf("Expected severity %d to map to %s, got %s", severity, level, result)
+		}
+	}
+}
@@ -3,7 +3,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -11,11 +11,33 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
-func Connect(uri string) (*mongo.Client, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+const defaultConnectTimeout = 10 * time.Second
+
+// Options tunes the client; zero values keep the driver defaults
+type Options struct {
+	ConnectTimeout         time.Duration
+	ServerSelectionTimeout time.Duration
+	MaxPoolSize            uint64
+	MinPoolSize            uint64
+}
+
+func Connect(uri string, opts Options) (*mongo.Client, error) {
+	if opts.ConnectTimeout <= 0 {
+		opts.ConnectTimeout = defaultConnectTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opts.ConnectTimeout)
 	defer cancel()
 
-	clientOptions := options.Client().ApplyURI(uri)
+	clientOptions := options.Client().ApplyURI(uri).SetConnectTimeout(opts.ConnectTimeout)
+	if opts.ServerSelectionTimeout > 0 {
+		clientOptions.SetServerSelectionTimeout(opts.ServerSelectionTimeout)
+	}
+	if opts.MaxPoolSize > 0 {
+		clientOptions.SetMaxPoolSize(opts.MaxPoolSize)
+	}
+	if opts.MinPoolSize > 0 {
+		clientOptions.SetMinPoolSize(opts.MinPoolSize)
+	}
 
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
@@ -24,12 +46,12 @@ func Connect(uri string) (*mongo.Client, error) {
 
 	if err = client.Ping(ctx, readpref.Primary()); err != nil {
 		if discErr := client.Disconnect(ctx); discErr != nil {
-			log.Printf("Error during client cleanup after ping failure: %v", discErr)
+			slog.Warn("MongoDB client cleanup after ping failure failed", "error", discErr)
 		}
 		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
-	fmt.Println("Successfully connected and pinged MongoDB!")
+	slog.Info("Connected to MongoDB")
 	return client, nil
 }
 
@@ -38,8 +60,16 @@ func Disconnect(client *mongo.Client) {
 	defer cancel()
 
 	if err := client.Disconnect(ctx); err != nil {
-		fmt.Printf("Error during MongoDB disconnection: %v\n", err)
+		slog.Error("MongoDB disconnection failed", "error", err)
 		return
 	}
-	fmt.Println("MongoDB connection closed.")
+	slog.Info("MongoDB connection closed")
+}
+
+// Ping checks that the primary is reachable, for readiness probes
+func Ping(ctx context.Context, client *mongo.Client) error {
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		return fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,155 @@
+package fluentd
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/netutil"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	readBufferSize = 64 * 1024
+	createTimeout  = 10 * time.Second
+	tcpIdleTimeout = 5 * time.Minute
+)
+
+// Config describes one forward protocol listener. Records are stored under
+// ApplicationID and UserID unless the mapped fields name another owner.
+type Config struct {
+	Address       string // e.g. ":24224"
+	TLS           *tls.Config
+	Fields        FieldMapping
+	ApplicationID uuid.UUID
+	UserID        uuid.UUID
+}
+
+// Listener accepts Fluentd and Fluent Bit forward output connections and
+// creates a log for each record
+type Listener struct {
+	cfg     Config
+	usecase usecase.LogUsecaseInterface
+	server  *netutil.Server
+	stream  net.Listener
+}
+
+func NewListener(cfg Config, uc usecase.LogUsecaseInterface) *Listener {
+	cfg.Fields = cfg.Fields.withDefaults()
+	return &Listener{
+		cfg:     cfg,
+		usecase: uc,
+		server:  netutil.NewServer("Fluentd forward"),
+	}
+}
+
+// Listen binds the configured address and serves it in the background
+func (l *Listener) Listen() error {
+	listener, err := net.Listen("tcp", l.cfg.Address)
+	if err != nil {
+		return fmt.Errorf("fluentd: failed to listen on tcp %s: %w", l.cfg.Address, err)
+	}
+	if l.cfg.TLS != nil {
+		listener = tls.NewListener(listener, l.cfg.TLS)
+	}
+	l.stream = listener
+	l.server.Serve(listener, tcpIdleTimeout, l.serveConn)
+	return nil
+}
+
+// Addr returns the bound address, or nil before Listen
+func (l *Listener) Addr() net.Addr {
+	if l.stream != nil {
+		return l.stream.Addr()
+	}
+	return nil
+}
+
+// Close stops accepting connections and waits for open connections to finish
+func (l *Listener) Close() error {
+	return l.server.Close()
+}
+
+// serveConn decodes consecutive messages and acknowledges those carrying a
+// chunk option once all of their records have been handled
+func (l *Listener) serveConn(conn net.Conn) {
+	sender := conn.RemoteAddr().String()
+	dec := NewDecoder(bufio.NewReaderSize(conn, readBufferSize))
+	for {
+		msg, err := Decode(dec)
+		if err != nil {
+			if err != io.EOF && !errors.Is(err, io.ErrUnexpectedEOF) && !l.server.Closed() {
+				slog.Warn("Fluentd forward connection closed", "sender", sender, "error", err)
+			}
+			return
+		}
+
+		if !l.handle(msg, sender) || msg.Chunk == "" {
+			continue
+		}
+		ack, err := msgpack.Marshal(map[string]string{"ack": msg.Chunk})
+		if err != nil {
+			return
+		}
+		conn.SetWriteDeadline(time.Now().Add(createTimeout))
+		if _, err := conn.Write(ack); err != nil {
+			return
+		}
+	}
+}
+
+// handle stores every record of a message. It returns false when a record
+// failed for a reason the client should retry (rate limits, a full queue or
+// a storage error), in which case the chunk is not acknowledged and the
+// client resends it. Records of acknowledged chunks carry idempotency keys so
+// that a resent chunk does not duplicate the records stored the first time.
+func (l *Listener) handle(msg *Message, sender string) bool {
+	for i, entry := range msg.Entries {
+		input, err := toInput(l.cfg.Fields, msg.Tag, entry, l.cfg.ApplicationID, l.cfg.UserID)
+		if err != nil {
+			slog.Warn("Fluentd record dropped", "sender", sender, "error", err)
+			continue
+		}
+		if msg.Chunk != "" {
+			input.IdempotencyKey = fmt.Sprintf("fluentd:%s:%d", msg.Chunk, i)
+		}
+
+		if !l.create(input, sender) {
+			return false
+		}
+	}
+	return true
+}
+
+// create stores one record under its own deadline, so a large chunk cannot
+// exhaust a shared timeout and never be acknowledged. It returns false when
+// the record should be retried.
+func (l *Listener) create(input dto.CreateLogInput, sender string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), createTimeout)
+	defer cancel()
+
+	_, err := l.usecase.CreateLog(ctx, input)
+	if err == nil {
+		return true
+	}
+	switch usecase.ClassifyCreateError(err).Kind {
+	case usecase.CreateErrorDuplicate:
+	case usecase.CreateErrorInvalid, usecase.CreateErrorForbidden:
+		slog.Warn("Fluentd record dropped", "sender", sender, "error", err)
+	case usecase.CreateErrorRateLimited, usecase.CreateErrorUnavailable:
+		return false
+	default:
+		slog.Error("Fluentd record failed", "sender", sender, "error", err)
+		return false
+	}
+	return true
+}
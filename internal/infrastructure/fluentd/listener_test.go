@@ -0,0 +1,166 @@
+package fluentd
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/pipeline"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type mockLogUsecase struct {
+	mu     sync.Mutex
+	inputs []dto.CreateLogInput
+	err    error
+}
+
+func (m *mockLogUsecase) CreateLog(ctx context.Context, input dto.CreateLogInput) (*dto.CreateLogOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inputs = append(m.inputs, input)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &dto.CreateLogOutput{}, nil
+}
+
+func (m *mockLogUsecase) ListLogs(ctx context.Context, input dto.ListLogsInput) (*dto.ListLogsOutput, error) {
+	return nil, nil
+}
+
+func (m *mockLogUsecase) waitFor(t *testing.T, n int) []dto.CreateLogInput {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		m.mu.Lock()
+		if len(m.inputs) >= n {
+			inputs := append([]dto.CreateLogInput(nil), m.inputs...)
+			m.mu.Unlock()
+			return inputs
+		}
+		m.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Expected %d logs to be created", n)
+	return nil
+}
+
+func startListener(t *testing.T, cfg Config, uc *mockLogUsecase) net.Conn {
+	listener := NewListener(cfg, uc)
+	if err := listener.Listen(); err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readAck(t *testing.T, conn net.Conn) (string, bool) {
+	conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	var response map[string]string
+	if err := msgpack.NewDecoder(conn).Decode(&response); err != nil {
+		return "", false
+	}
+	return response["ack"], true
+}
+
+func TestListener_ForwardWithAck(t *testing.T) {
+	uc := &mockLogUsecase{}
+	applicationID := uuid.New()
+	userID := uuid.New()
+	podApplicationID := uuid.New()
+	conn := startListener(t, Config{Address: "127.0.0.1:0", ApplicationID: applicationID, UserID: userID}, uc)
+
+	conn.Write(encode(t, []interface{}{"kube.var.log", []interface{}{
+		[]interface{}{eventTime(sampleTime), map[string]interface{}{
+			"log":    "connection refused\n",
+			"stream": "stderr",
+			"level":  "error",
+			"kubernetes": map[string]interface{}{
+				"pod_name":  "api-7d9f",
+				"namespace": "prod",
+				"labels":    map[string]interface{}{"application_id": podApplicationID.String()},
+			},
+		}},
+		[]interface{}{eventTime(sampleTime), map[string]interface{}{"message": "plain"}},
+		[]interface{}{eventTime(sampleTime), map[string]interface{}{"no_message": true}},
+	}, map[string]interface{}{"chunk": "Y2h1bmsx"}}))
+
+	if ack, ok := readAck(t, conn); !ok || ack != "Y2h1bmsx" {
+		t.Fatalf("Expected ack for the chunk, got %q", ack)
+	}
+
+	inputs := uc.waitFor(t, 2)
+	first := inputs[0]
+	if first.ApplicationID != podApplicationID || first.UserID != userID {
+		t.Errorf("Expected application from the pod label, got %s", first.ApplicationID)
+	}
+	if first.Message != "connection refused" || first.Level != "ERROR" || first.Source != "api-7d9f" {
+		t.Errorf("Unexpected mapped input: %+v", first)
+	}
+	if first.Timestamp == nil || !first.Timestamp.Equal(sampleTime) {
+		t.Errorf("Expected event time, got %v", first.Timestamp)
+	}
+	if first.Metadata["stream"] != "stderr" || first.Metadata[TagField] != "kube.var.log" {
+		t.Errorf("Expected remaining fields and tag in metadata, got %v", first.Metadata)
+	}
+	if first.Metadata["kubernetes"].(map[string]interface{})["namespace"] != "prod" {
+		t.Errorf("Expected unmapped nested fields to be kept, got %v", first.Metadata["kubernetes"])
+	}
+	if first.IdempotencyKey != "fluentd:Y2h1bmsx:0" {
+		t.Errorf("Expected idempotency key from the chunk, got %q", first.IdempotencyKey)
+	}
+
+	second := inputs[1]
+	if second.ApplicationID != applicationID || second.Source != "kube.var.log" || second.Level != "INFO" {
+		t.Errorf("Expected defaults and the tag as source, got %+v", second)
+	}
+}
+
+func TestListener_CustomFieldMapping(t *testing.T) {
+	uc := &mockLogUsecase{}
+	conn := startListener(t, Config{
+		Address:       "127.0.0.1:0",
+		Fields:        FieldMapping{Message: []string{"event.text"}, Level: []string{"sev"}},
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+	}, uc)
+
+	conn.Write(encode(t, []interface{}{"app", 1730109600, map[string]interface{}{
+		"event": map[string]interface{}{"text": "custom"},
+		"sev":   "warn",
+		"log":   "kept",
+	}}))
+
+	input := uc.waitFor(t, 1)[0]
+	if input.Message != "custom" || input.Level != "WARN" {
+		t.Errorf("Unexpected mapped input: %+v", input)
+	}
+	if input.Metadata["log"] != "kept" {
+		t.Errorf("Expected fields outside the mapping in metadata, got %v", input.Metadata)
+	}
+	if _, ok := input.Metadata["event"]; ok {
+		t.Error("Expected emptied nested maps to be removed")
+	}
+}
+
+func TestListener_NoAckOnRetryableError(t *testing.T) {
+	uc := &mockLogUsecase{err: pipeline.ErrQueueFull}
+	conn := startListener(t, Config{Address: "127.0.0.1:0", ApplicationID: uuid.New(), UserID: uuid.New()}, uc)
+
+	conn.Write(encode(t, []interface{}{"app", 1730109600, map[string]interface{}{"log": "retry me"}, map[string]interface{}{"chunk": "c1"}}))
+
+	uc.waitFor(t, 1)
+	if ack, ok := readAck(t, conn); ok {
+		t.Errorf("Expected no ack while the queue is full, got %q", ack)
+	}
+}
@@ -0,0 +1,184 @@
+package fluentd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+var (
+	ErrMissingMessage = errors.New("fluentd: record has no message field")
+	ErrNoApplication  = errors.New("fluentd: no application configured for record")
+)
+
+// TagField is the metadata key holding the Fluentd tag of each record
+const TagField = "fluentd_tag"
+
+// FieldMapping lists, for each log attribute, the record fields tried in
+// order. Dotted names are looked up both as nested maps and literal keys.
+type FieldMapping struct {
+	Message     []string
+	Level       []string
+	Source      []string
+	Application []string
+	User        []string
+}
+
+// DefaultFieldMapping covers records produced by Fluent Bit's tail and
+// kubernetes filters and by Fluentd's in_tail parsers
+func DefaultFieldMapping() FieldMapping {
+	return FieldMapping{
+		Message:     []string{"log", "message", "msg"},
+		Level:       []string{"level", "severity", "log.level"},
+		Source:      []string{"kubernetes.pod_name", "host", "hostname"},
+		Application: []string{"application_id", "kubernetes.labels.application_id"},
+		User:        []string{"user_id", "kubernetes.labels.user_id"},
+	}
+}
+
+// withDefaults fills empty lists from DefaultFieldMapping
+func (m FieldMapping) withDefaults() FieldMapping {
+	defaults := DefaultFieldMapping()
+	if len(m.Message) == 0 {
+		m.Message = defaults.Message
+	}
+	if len(m.Level) == 0 {
+		m.Level = defaults.Level
+	}
+	if len(m.Source) == 0 {
+		m.Source = defaults.Source
+	}
+	if len(m.Application) == 0 {
+		m.Application = defaults.Application
+	}
+	if len(m.User) == 0 {
+		m.User = defaults.User
+	}
+	return m
+}
+
+// toInput maps a record using the field mapping. Mapped fields are removed,
+// the rest of the record is kept as metadata along with the tag, and the tag
+// is used as the source when no source field is present.
+func toInput(mapping FieldMapping, tag string, entry Entry, applicationID, userID uuid.UUID) (dto.CreateLogInput, error) {
+	record := entry.Record
+
+	message, ok := takeString(record, mapping.Message)
+	if !ok {
+		return dto.CreateLogInput{}, ErrMissingMessage
+	}
+
+	input := dto.CreateLogInput{
+		ApplicationID: applicationID,
+		UserID:        userID,
+		Message:       strings.TrimRight(message, "\r\n"),
+		Level:         valueobjects.LogLevelInfo.String(),
+		Source:        tag,
+	}
+
+	if raw, ok := takeString(record, mapping.Application); ok {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return dto.CreateLogInput{}, fmt.Errorf("fluentd: invalid application ID %q", raw)
+		}
+		input.ApplicationID = id
+	}
+	if input.ApplicationID == uuid.Nil {
+		return dto.CreateLogInput{}, ErrNoApplication
+	}
+	if raw, ok := takeString(record, mapping.User); ok {
+		if id, err := uuid.Parse(raw); err == nil {
+			input.UserID = id
+		}
+	}
+
+	if raw, ok := takeString(record, mapping.Level); ok {
+		if level, ok := valueobjects.ParseLogLevelAlias(raw); ok {
+			input.Level = level.String()
+		}
+	}
+	if source, ok := takeString(record, mapping.Source); ok {
+		input.Source = source
+	}
+	if !entry.Time.IsZero() {
+		timestamp := entry.Time
+		input.Timestamp = &timestamp
+	}
+
+	input.Metadata = normalize(record).(map[string]interface{})
+	input.Metadata[TagField] = tag
+	return input, nil
+}
+
+// takeString finds the first candidate field holding a non-empty scalar and removes it
+func takeString(record map[string]interface{}, fields []string) (string, bool) {
+	for _, field := range fields {
+		value, ok := take(record, field)
+		if !ok {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			if v != "" {
+				return v, true
+			}
+		case int64, uint64, float64, bool:
+			return fmt.Sprint(v), true
+		}
+	}
+	return "", false
+}
+
+// take looks up a literal key first, then a dotted path through nested
+// maps, removing the value and any maps left empty
+func take(record map[string]interface{}, field string) (interface{}, bool) {
+	if value, ok := record[field]; ok {
+		delete(record, field)
+		return value, true
+	}
+
+	head, rest, nested := strings.Cut(field, ".")
+	if !nested {
+		return nil, false
+	}
+	child, ok := record[head].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	value, ok := take(child, rest)
+	if ok && len(child) == 0 {
+		delete(record, head)
+	}
+	return value, ok
+}
+
+// normalize converts uint64 values, which MongoDB cannot store, to int64 or float64
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v)
+		}
+		return float64(v)
+	case map[string]interface{}:
+		if v == nil {
+			return map[string]interface{}{}
+		}
+		for key, item := range v {
+			v[key] = normalize(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
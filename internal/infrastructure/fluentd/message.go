@@ -0,0 +1,245 @@
+package fluentd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+)
+
+var (
+	ErrInvalidMessage = errors.New("fluentd: malformed forward message")
+	ErrInvalidTime    = errors.New("fluentd: malformed event time")
+)
+
+const (
+	// eventTimeExt is the MessagePack extension type of Fluentd's EventTime:
+	// 4 bytes of seconds and 4 bytes of nanoseconds, both big-endian
+	eventTimeExt = 0
+
+	// maxDecompressedSize bounds a CompressedPackedForward chunk after gunzip
+	maxDecompressedSize = 64 << 20
+)
+
+// Entry is one event of a forward message
+type Entry struct {
+	Time   time.Time
+	Record map[string]interface{}
+}
+
+// Message is a decoded forward protocol message. Every mode (Message,
+// Forward, PackedForward and CompressedPackedForward) is reduced to a tag and
+// its entries. Chunk is set when the client expects an acknowledgement.
+type Message struct {
+	Tag     string
+	Entries []Entry
+	Chunk   string
+}
+
+// Decode reads the next message from a stream created with NewDecoder
+func Decode(dec *msgpack.Decoder) (*Message, error) {
+	n, err := dec.DecodeArrayLen()
+	if err != nil {
+		return nil, err
+	}
+	if n < 2 || n > 4 {
+		return nil, fmt.Errorf("%w: array of %d elements", ErrInvalidMessage, n)
+	}
+
+	tag, err := dec.DecodeString()
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid tag: %v", ErrInvalidMessage, err)
+	}
+	msg := &Message{Tag: tag}
+
+	code, err := dec.PeekCode()
+	if err != nil {
+		return nil, err
+	}
+
+	var packed []byte
+	remaining := n - 2
+	switch {
+	case msgpcode.IsFixedArray(code) || code == msgpcode.Array16 || code == msgpcode.Array32:
+		// Forward mode: [tag, [[time, record], ...], option?]
+		if msg.Entries, err = decodeEntryArray(dec); err != nil {
+			return nil, err
+		}
+	case msgpcode.IsString(code) || msgpcode.IsBin(code):
+		// PackedForward mode: [tag, <concatenated [time, record] entries>, option?]
+		if packed, err = dec.DecodeBytes(); err != nil {
+			return nil, fmt.Errorf("%w: invalid entries: %v", ErrInvalidMessage, err)
+		}
+	default:
+		// Message mode: [tag, time, record, option?]
+		if n < 3 {
+			return nil, fmt.Errorf("%w: message mode without a record", ErrInvalidMessage)
+		}
+		entry, err := decodeEvent(dec)
+		if err != nil {
+			return nil, err
+		}
+		msg.Entries = []Entry{entry}
+		remaining--
+	}
+
+	var compressed string
+	if remaining > 0 {
+		option, err := dec.DecodeMap()
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid option: %v", ErrInvalidMessage, err)
+		}
+		msg.Chunk, _ = option["chunk"].(string)
+		compressed, _ = option["compressed"].(string)
+	}
+
+	if packed != nil {
+		if compressed == "gzip" {
+			if packed, err = gunzip(packed); err != nil {
+				return nil, fmt.Errorf("%w: invalid compressed entries: %v", ErrInvalidMessage, err)
+			}
+		} else if compressed != "" && compressed != "text" {
+			return nil, fmt.Errorf("%w: unsupported compression %q", ErrInvalidMessage, compressed)
+		}
+		if msg.Entries, err = decodePacked(packed); err != nil {
+			return nil, err
+		}
+	}
+
+	return msg, nil
+}
+
+func decodeEntryArray(dec *msgpack.Decoder) ([]Entry, error) {
+	n, err := dec.DecodeArrayLen()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, min(max(n, 0), 1024))
+	for i := 0; i < n; i++ {
+		entry, err := decodeEntry(dec)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// decodePacked reads the back-to-back [time, record] arrays of a PackedForward message
+func decodePacked(data []byte) ([]Entry, error) {
+	dec := NewDecoder(bytes.NewReader(data))
+
+	var entries []Entry
+	for {
+		entry, err := decodeEntry(dec)
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// decodeEntry reads a [time, record] array
+func decodeEntry(dec *msgpack.Decoder) (Entry, error) {
+	n, err := dec.DecodeArrayLen()
+	if err != nil {
+		return Entry{}, err
+	}
+	if n != 2 {
+		return Entry{}, fmt.Errorf("%w: entry of %d elements", ErrInvalidMessage, n)
+	}
+	return decodeEvent(dec)
+}
+
+func decodeEvent(dec *msgpack.Decoder) (Entry, error) {
+	timestamp, err := decodeTime(dec)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	record, err := dec.DecodeMap()
+	if err != nil {
+		return Entry{}, fmt.Errorf("%w: invalid record: %v", ErrInvalidMessage, err)
+	}
+	return Entry{Time: timestamp, Record: record}, nil
+}
+
+// decodeTime accepts an EventTime extension, integer seconds or float seconds
+func decodeTime(dec *msgpack.Decoder) (time.Time, error) {
+	code, err := dec.PeekCode()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if code == msgpcode.FixExt8 || code == msgpcode.Ext8 {
+		extID, extLen, err := dec.DecodeExtHeader()
+		if err != nil {
+			return time.Time{}, err
+		}
+		if extID != eventTimeExt || extLen != 8 {
+			return time.Time{}, fmt.Errorf("%w: extension %d of %d bytes", ErrInvalidTime, extID, extLen)
+		}
+		buf := make([]byte, 8)
+		if err := dec.ReadFull(buf); err != nil {
+			return time.Time{}, err
+		}
+		seconds := binary.BigEndian.Uint32(buf[:4])
+		nanos := binary.BigEndian.Uint32(buf[4:])
+		return time.Unix(int64(seconds), int64(nanos)).UTC(), nil
+	}
+
+	value, err := dec.DecodeInterfaceLoose()
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch v := value.(type) {
+	case int64:
+		return time.Unix(v, 0).UTC(), nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return time.Time{}, ErrInvalidTime
+		}
+		return time.Unix(int64(v), 0).UTC(), nil
+	case float64:
+		seconds, fraction := math.Modf(v)
+		return time.Unix(int64(seconds), int64(fraction*1e9)).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: %T", ErrInvalidTime, value)
+}
+
+// gunzip decompresses every gzip member, as clients may append one per flush
+func gunzip(data []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	out, err := io.ReadAll(io.LimitReader(gz, maxDecompressedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(out) > maxDecompressedSize {
+		return nil, errors.New("decompressed entries too large")
+	}
+	return out, nil
+}
+
+// NewDecoder returns a decoder producing int64, uint64, float64 and string
+// values instead of the exact MessagePack widths
+func NewDecoder(r io.Reader) *msgpack.Decoder {
+	dec := msgpack.NewDecoder(r)
+	dec.UseLooseInterfaceDecoding(true)
+	return dec
+}
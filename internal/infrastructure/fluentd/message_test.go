@@ -0,0 +1,148 @@
+package fluentd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var sampleTime = time.Date(2025, 10, 28, 10, 0, 0, 123456789, time.UTC)
+
+// eventTime encodes t as Fluentd's EventTime extension
+func eventTime(t time.Time) msgpack.RawMessage {
+	raw := []byte{0xd7, 0x00, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(raw[2:6], uint32(t.Unix()))
+	binary.BigEndian.PutUint32(raw[6:], uint32(t.Nanosecond()))
+	return raw
+}
+
+func encode(t *testing.T, values ...interface{}) []byte {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	for _, value := range values {
+		if err := enc.Encode(value); err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func decode(t *testing.T, data []byte) *Message {
+	msg, err := Decode(NewDecoder(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return msg
+}
+
+func TestDecode_MessageMode(t *testing.T) {
+	data := encode(t, []interface{}{"app.web", eventTime(sampleTime), map[string]interface{}{"log": "hello", "count": uint8(3)}, map[string]interface{}{"chunk": "abc"}})
+
+	msg := decode(t, data)
+
+	if msg.Tag != "app.web" || msg.Chunk != "abc" || len(msg.Entries) != 1 {
+		t.Fatalf("Unexpected message: %+v", msg)
+	}
+	entry := msg.Entries[0]
+	if !entry.Time.Equal(sampleTime) {
+		t.Errorf("Expected time %v, got %v", sampleTime, entry.Time)
+	}
+	if entry.Record["log"] != "hello" || entry.Record["count"] != uint64(3) {
+		t.Errorf("Unexpected record: %#v", entry.Record)
+	}
+}
+
+func TestDecode_ForwardMode(t *testing.T) {
+	data := encode(t, []interface{}{"app.web", []interface{}{
+		[]interface{}{1730109600, map[string]interface{}{"log": "first"}},
+		[]interface{}{1730109600.5, map[string]interface{}{"log": "second"}},
+	}})
+
+	msg := decode(t, data)
+
+	if len(msg.Entries) != 2 || msg.Chunk != "" {
+		t.Fatalf("Unexpected message: %+v", msg)
+	}
+	if !msg.Entries[0].Time.Equal(time.Unix(1730109600, 0)) {
+		t.Errorf("Expected integer seconds, got %v", msg.Entries[0].Time)
+	}
+	if !msg.Entries[1].Time.Equal(time.Unix(1730109600, 500000000)) {
+		t.Errorf("Expected float seconds, got %v", msg.Entries[1].Time)
+	}
+}
+
+func TestDecode_PackedForwardModes(t *testing.T) {
+	entries := encode(t,
+		[]interface{}{eventTime(sampleTime), map[string]interface{}{"log": "first"}},
+		[]interface{}{eventTime(sampleTime), map[string]interface{}{"log": "second"}},
+	)
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write(entries)
+	gz.Close()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "PackedForward bin", data: encode(t, []interface{}{"app", entries})},
+		{name: "PackedForward str", data: encode(t, []interface{}{"app", string(entries), map[string]interface{}{"size": 2}})},
+		{name: "CompressedPackedForward", data: encode(t, []interface{}{"app", compressed.Bytes(), map[string]interface{}{"compressed": "gzip", "chunk": "c1"}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := decode(t, tt.data)
+
+			if len(msg.Entries) != 2 {
+				t.Fatalf("Expected 2 entries, got %d", len(msg.Entries))
+			}
+			if msg.Entries[1].Record["log"] != "second" || !msg.Entries[1].Time.Equal(sampleTime) {
+				t.Errorf("Unexpected entry: %+v", msg.Entries[1])
+			}
+		})
+	}
+}
+
+func TestDecode_Stream(t *testing.T) {
+	data := encode(t,
+		[]interface{}{"a", 1730109600, map[string]interface{}{"log": "first"}},
+		[]interface{}{"b", 1730109601, map[string]interface{}{"log": "second"}},
+	)
+	dec := NewDecoder(bytes.NewReader(data))
+
+	for _, tag := range []string{"a", "b"} {
+		msg, err := Decode(dec)
+		if err != nil || msg.Tag != tag {
+			t.Fatalf("Expected message %q, got %+v (%v)", tag, msg, err)
+		}
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Too short", data: encode(t, []interface{}{"app"})},
+		{name: "Message without record", data: encode(t, []interface{}{"app", 1730109600})},
+		{name: "Invalid time", data: encode(t, []interface{}{"app", true, map[string]interface{}{}})},
+		{name: "Invalid entry", data: encode(t, []interface{}{"app", []interface{}{[]interface{}{1730109600}}})},
+		{name: "Unsupported compression", data: encode(t, []interface{}{"app", []byte{1}, map[string]interface{}{"compressed": "zstd"}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(NewDecoder(bytes.NewReader(tt.data)))
+			if !errors.Is(err, ErrInvalidMessage) && !errors.Is(err, ErrInvalidTime) {
+				t.Errorf("Expected a malformed message error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package gelf
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrInvalidChunk = errors.New("gelf: malformed chunk")
+
+const (
+	chunkHeaderSize = 12
+	maxChunks       = 128
+
+	// chunkTimeout is how long the specification allows for all chunks of a message to arrive
+	chunkTimeout = 5 * time.Second
+	// maxPendingMessages bounds the memory held by incomplete messages
+	maxPendingMessages = 1024
+)
+
+// isChunk reports whether a datagram starts with the chunked GELF magic bytes 0x1e 0x0f
+func isChunk(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1e && data[1] == 0x0f
+}
+
+type pendingMessage struct {
+	chunks   [][]byte
+	received int
+	first    time.Time
+}
+
+// assembler reassembles chunked UDP messages. Each chunk carries a 12 byte
+// header: magic (2), message ID (8), sequence number (1) and sequence count (1).
+type assembler struct {
+	mu      sync.Mutex
+	pending map[[8]byte]*pendingMessage
+	now     func() time.Time
+}
+
+func newAssembler() *assembler {
+	return &assembler{
+		pending: make(map[[8]byte]*pendingMessage),
+		now:     time.Now,
+	}
+}
+
+// add stores a chunk and returns the complete message once every chunk has arrived
+func (a *assembler) add(chunk []byte) ([]byte, error) {
+	if len(chunk) <= chunkHeaderSize {
+		return nil, ErrInvalidChunk
+	}
+	var id [8]byte
+	copy(id[:], chunk[2:10])
+	seq, count := int(chunk[10]), int(chunk[11])
+	if count == 0 || count > maxChunks || seq >= count {
+		return nil, ErrInvalidChunk
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	a.expire(now)
+
+	msg, ok := a.pending[id]
+	if !ok {
+		if len(a.pending) >= maxPendingMessages {
+			return nil, errors.New("gelf: too many incomplete chunked messages")
+		}
+		msg = &pendingMessage{chunks: make([][]byte, count), first: now}
+		a.pending[id] = msg
+	}
+	if len(msg.chunks) != count {
+		delete(a.pending, id)
+		return nil, ErrInvalidChunk
+	}
+	if msg.chunks[seq] == nil {
+		msg.chunks[seq] = append([]byte(nil), chunk[chunkHeaderSize:]...)
+		msg.received++
+	}
+	if msg.received < count {
+		return nil, nil
+	}
+
+	delete(a.pending, id)
+	size := 0
+	for _, c := range msg.chunks {
+		size += len(c)
+	}
+	complete := make([]byte, 0, size)
+	for _, c := range msg.chunks {
+		complete = append(complete, c...)
+	}
+	return complete, nil
+}
+
+// expire drops messages whose chunks did not all arrive in time
+func (a *assembler) expire(now time.Time) {
+	for id, msg := range a.pending {
+		if now.Sub(msg.first) > chunkTimeout {
+			delete(a.pending, id)
+		}
+	}
+}
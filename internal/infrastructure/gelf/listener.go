@@ -0,0 +1,218 @@
+package gelf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/netutil"
+)
+
+const (
+	// maxDatagramSize fits any UDP payload; larger messages arrive chunked
+	maxDatagramSize = 65536
+	// maxFrameSize bounds a single null-delimited TCP frame
+	maxFrameSize   = 1 << 20
+	createTimeout  = 10 * time.Second
+	tcpIdleTimeout = 5 * time.Minute
+
+	// ApplicationIDField and UserIDField are additional fields (e.g. set
+	// through Docker's --log-opt labels) that override the listener's owner
+	ApplicationIDField = "application_id"
+	UserIDField        = "user_id"
+)
+
+var ErrNoApplication = errors.New("gelf: no application configured for message")
+
+// Config describes one listener. Messages are stored under ApplicationID and
+// UserID unless they carry _application_id / _user_id additional fields.
+type Config struct {
+	Network       string // "udp" or "tcp"
+	Address       string // e.g. ":12201"
+	ApplicationID uuid.UUID
+	UserID        uuid.UUID
+}
+
+// Listener receives GELF messages and creates a log for each one
+type Listener struct {
+	cfg       Config
+	usecase   usecase.LogUsecaseInterface
+	assembler *assembler
+	server    *netutil.Server
+
+	packet net.PacketConn
+	stream net.Listener
+}
+
+func NewListener(cfg Config, uc usecase.LogUsecaseInterface) *Listener {
+	return &Listener{
+		cfg:       cfg,
+		usecase:   uc,
+		assembler: newAssembler(),
+		server:    netutil.NewServer("GELF " + strings.ToUpper(cfg.Network)),
+	}
+}
+
+// Listen binds the configured address and serves it in the background
+func (l *Listener) Listen() error {
+	switch l.cfg.Network {
+	case "udp":
+		conn, err := net.ListenPacket("udp", l.cfg.Address)
+		if err != nil {
+			return fmt.Errorf("gelf: failed to listen on udp %s: %w", l.cfg.Address, err)
+		}
+		l.packet = conn
+		l.server.Track(conn)
+		l.server.Go(l.serveUDP)
+	case "tcp":
+		listener, err := net.Listen("tcp", l.cfg.Address)
+		if err != nil {
+			return fmt.Errorf("gelf: failed to listen on tcp %s: %w", l.cfg.Address, err)
+		}
+		l.stream = listener
+		l.server.Serve(listener, tcpIdleTimeout, l.serveConn)
+	default:
+		return fmt.Errorf("gelf: unsupported network %q", l.cfg.Network)
+	}
+	return nil
+}
+
+// Addr returns the bound address, or nil before Listen
+func (l *Listener) Addr() net.Addr {
+	if l.packet != nil {
+		return l.packet.LocalAddr()
+	}
+	if l.stream != nil {
+		return l.stream.Addr()
+	}
+	return nil
+}
+
+// Close stops accepting messages and waits for open connections to finish
+func (l *Listener) Close() error {
+	return l.server.Close()
+}
+
+func (l *Listener) serveUDP() {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, addr, err := l.packet.ReadFrom(buf)
+		if err != nil {
+			if !l.server.Closed() {
+				slog.Error("GELF UDP read failed", "error", err)
+			}
+			return
+		}
+
+		data := buf[:n]
+		if isChunk(data) {
+			data, err = l.assembler.add(data)
+			if err != nil {
+				slog.Warn("GELF chunk dropped", "sender", addr.String(), "error", err)
+				continue
+			}
+			if data == nil {
+				continue
+			}
+		}
+		l.handle(data, addr.String())
+	}
+}
+
+// serveConn reads null-byte delimited frames; TCP messages are never compressed or chunked
+func (l *Listener) serveConn(conn net.Conn) {
+	sender := conn.RemoteAddr().String()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), maxFrameSize)
+	scanner.Split(splitNull)
+	for scanner.Scan() {
+		if frame := bytes.TrimSpace(scanner.Bytes()); len(frame) > 0 {
+			l.handle(frame, sender)
+		}
+	}
+	if err := scanner.Err(); err != nil && err != io.EOF && !l.server.Closed() {
+		slog.Warn("GELF connection closed", "sender", sender, "error", err)
+	}
+}
+
+func splitNull(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func (l *Listener) handle(data []byte, sender string) {
+	msg, err := Parse(data)
+	if err != nil {
+		slog.Warn("GELF message dropped", "sender", sender, "error", err)
+		return
+	}
+
+	input, err := l.toInput(msg)
+	if err != nil {
+		slog.Warn("GELF message dropped", "sender", sender, "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), createTimeout)
+	defer cancel()
+	if _, err := l.usecase.CreateLog(ctx, input); err != nil {
+		slog.Warn("GELF message dropped", "sender", sender, "error", err)
+	}
+}
+
+// toInput maps short_message to Message, the syslog level to Level, host to
+// Source, and full_message and the additional fields to Metadata
+func (l *Listener) toInput(msg *Message) (dto.CreateLogInput, error) {
+	input := dto.CreateLogInput{
+		ApplicationID: l.cfg.ApplicationID,
+		UserID:        l.cfg.UserID,
+		Message:       msg.ShortMessage,
+		Level:         valueobjects.LogLevelFromSyslogSeverity(msg.Level).String(),
+		Source:        msg.Host,
+		Metadata:      make(map[string]interface{}, len(msg.Fields)+1),
+	}
+
+	for name, value := range msg.Fields {
+		switch name {
+		case ApplicationIDField:
+			if id, err := uuid.Parse(fmt.Sprint(value)); err == nil {
+				input.ApplicationID = id
+				continue
+			}
+		case UserIDField:
+			if id, err := uuid.Parse(fmt.Sprint(value)); err == nil {
+				input.UserID = id
+				continue
+			}
+		}
+		input.Metadata[name] = value
+	}
+	if msg.FullMessage != "" {
+		input.Metadata["full_message"] = msg.FullMessage
+	}
+	if !msg.Timestamp.IsZero() {
+		timestamp := msg.Timestamp
+		input.Timestamp = &timestamp
+	}
+
+	if input.ApplicationID == uuid.Nil {
+		return dto.CreateLogInput{}, ErrNoApplication
+	}
+	return input, nil
+}
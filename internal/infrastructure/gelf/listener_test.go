@@ -0,0 +1,173 @@
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+)
+
+type mockLogUsecase struct {
+	mu     sync.Mutex
+	inputs []dto.CreateLogInput
+}
+
+func (m *mockLogUsecase) CreateLog(ctx context.Context, input dto.CreateLogInput) (*dto.CreateLogOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inputs = append(m.inputs, input)
+	return &dto.CreateLogOutput{}, nil
+}
+
+func (m *mockLogUsecase) ListLogs(ctx context.Context, input dto.ListLogsInput) (*dto.ListLogsOutput, error) {
+	return nil, nil
+}
+
+func (m *mockLogUsecase) waitFor(t *testing.T, n int) []dto.CreateLogInput {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		m.mu.Lock()
+		if len(m.inputs) >= n {
+			inputs := append([]dto.CreateLogInput(nil), m.inputs...)
+			m.mu.Unlock()
+			return inputs
+		}
+		m.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Expected %d logs to be created", n)
+	return nil
+}
+
+func startListener(t *testing.T, network string, uc *mockLogUsecase) (*Listener, uuid.UUID) {
+	applicationID := uuid.New()
+	listener := NewListener(Config{Network: network, Address: "127.0.0.1:0", ApplicationID: applicationID, UserID: uuid.New()}, uc)
+	if err := listener.Listen(); err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	return listener, applicationID
+}
+
+// chunk splits a payload into GELF chunks of at most size bytes
+func chunk(id [8]byte, payload []byte, size int) [][]byte {
+	var parts [][]byte
+	for start := 0; start < len(payload); start += size {
+		parts = append(parts, payload[start:min(start+size, len(payload))])
+	}
+
+	chunks := make([][]byte, len(parts))
+	for i, part := range parts {
+		header := append([]byte{0x1e, 0x0f}, id[:]...)
+		header = append(header, byte(i), byte(len(parts)))
+		chunks[i] = append(header, part...)
+	}
+	return chunks
+}
+
+func TestListener_UDPChunkedGzip(t *testing.T) {
+	uc := &mockLogUsecase{}
+	listener, applicationID := startListener(t, "udp", uc)
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write([]byte(samplePayload))
+	gz.Close()
+
+	conn, err := net.Dial("udp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	chunks := chunk([8]byte{1, 2, 3, 4, 5, 6, 7, 8}, gzipped.Bytes(), 40)
+	if len(chunks) < 2 {
+		t.Fatalf("Expected the payload to be split into several chunks")
+	}
+	// Chunks may arrive out of order
+	for i := len(chunks) - 1; i >= 0; i-- {
+		conn.Write(chunks[i])
+	}
+
+	input := uc.waitFor(t, 1)[0]
+	if input.ApplicationID != applicationID {
+		t.Errorf("Expected listener application %s, got %s", applicationID, input.ApplicationID)
+	}
+	if input.Message != "container started" || input.Level != "ERROR" || input.Source != "docker-01" {
+		t.Errorf("Unexpected mapped input: %+v", input)
+	}
+	if input.Metadata["container_name"] != "web" || input.Metadata["full_message"] == nil {
+		t.Errorf("Expected additional fields and full message in metadata, got %v", input.Metadata)
+	}
+	if input.Timestamp == nil {
+		t.Error("Expected event timestamp to be set")
+	}
+}
+
+func TestListener_TCPNullDelimited(t *testing.T) {
+	uc := &mockLogUsecase{}
+	listener, _ := startListener(t, "tcp", uc)
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	override := uuid.New()
+	conn.Write([]byte(`{"short_message":"first","level":6}` + "\x00"))
+	conn.Write([]byte(`{"short_message":"second","level":7,"_application_id":"` + override.String() + `"}` + "\x00"))
+
+	inputs := uc.waitFor(t, 2)
+	if inputs[0].Message != "first" || inputs[0].Level != "INFO" {
+		t.Errorf("Unexpected first input: %+v", inputs[0])
+	}
+	if inputs[1].ApplicationID != override || inputs[1].Level != "DEBUG" {
+		t.Errorf("Expected _application_id override and DEBUG, got %+v", inputs[1])
+	}
+	if _, ok := inputs[1].Metadata[ApplicationIDField]; ok {
+		t.Error("Expected the application field not to be copied into metadata")
+	}
+}
+
+func TestAssembler(t *testing.T) {
+	a := newAssembler()
+	now := time.Now()
+	a.now = func() time.Time { return now }
+
+	chunks := chunk([8]byte{9}, []byte(samplePayload), 100)
+
+	for _, c := range chunks[:len(chunks)-1] {
+		if complete, err := a.add(c); err != nil || complete != nil {
+			t.Fatalf("Expected partial message, got %q, %v", complete, err)
+		}
+	}
+	// Duplicate chunks are ignored
+	a.add(chunks[0])
+
+	complete, err := a.add(chunks[len(chunks)-1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(complete) != samplePayload {
+		t.Errorf("Expected reassembled payload, got %q", complete)
+	}
+
+	// Incomplete messages expire after the chunk timeout
+	a.add(chunks[0])
+	now = now.Add(chunkTimeout + time.Second)
+	a.add(chunk([8]byte{10}, []byte("x"), 100)[0])
+	if len(a.pending) != 0 {
+		t.Errorf("Expected expired messages to be dropped, got %d pending", len(a.pending))
+	}
+
+	if _, err := a.add([]byte{0x1e, 0x0f, 1, 2, 3, 4, 5, 6, 7, 8, 5, 2, 'x'}); err != ErrInvalidChunk {
+		t.Errorf("Expected ErrInvalidChunk for sequence beyond count, got %v", err)
+	}
+}
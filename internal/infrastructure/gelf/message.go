@@ -0,0 +1,143 @@
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidMessage  = errors.New("gelf: malformed message")
+	ErrMissingShortMsg = errors.New("gelf: short_message is required")
+	ErrMessageTooLarge = errors.New("gelf: decompressed message too large")
+)
+
+// maxDecompressedSize bounds a message after decompression
+const maxDecompressedSize = 8 << 20
+
+// defaultLevel is syslog "alert", which GELF specifies when level is omitted
+const defaultLevel = 1
+
+// Message is a decoded GELF 1.1 payload
+type Message struct {
+	Host         string
+	ShortMessage string
+	FullMessage  string
+	Timestamp    time.Time
+	Level        int
+	// Fields holds the additional fields without their leading underscore
+	Fields map[string]interface{}
+}
+
+// Parse decompresses (gzip or zlib, detected from the magic bytes) and decodes a GELF message
+func Parse(data []byte) (*Message, error) {
+	payload, err := decompress(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	msg := &Message{Level: defaultLevel, Fields: make(map[string]interface{})}
+	for key, value := range raw {
+		switch key {
+		case "host":
+			msg.Host, _ = value.(string)
+		case "short_message":
+			msg.ShortMessage, _ = value.(string)
+		case "full_message":
+			msg.FullMessage, _ = value.(string)
+		case "timestamp":
+			if seconds, ok := number(value); ok && seconds > 0 {
+				whole, frac := math.Modf(seconds)
+				msg.Timestamp = time.Unix(int64(whole), int64(math.Round(frac*1e6))*1e3).UTC()
+			}
+		case "level":
+			if level, ok := number(value); ok && level >= 0 && level <= 7 {
+				msg.Level = int(level)
+			}
+		default:
+			// "_id" is reserved by the specification and ignored
+			if name, ok := strings.CutPrefix(key, "_"); ok && name != "" && name != "id" {
+				msg.Fields[name] = plain(value)
+			}
+		}
+	}
+
+	if strings.TrimSpace(msg.ShortMessage) == "" {
+		return nil, ErrMissingShortMsg
+	}
+	return msg, nil
+}
+
+func decompress(data []byte) ([]byte, error) {
+	var reader io.ReadCloser
+	var err error
+	switch {
+	case len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b:
+		reader, err = gzip.NewReader(bytes.NewReader(data))
+	case len(data) >= 2 && data[0] == 0x78 && (uint16(data[0])<<8|uint16(data[1]))%31 == 0:
+		reader, err = zlib.NewReader(bytes.NewReader(data))
+	default:
+		return data, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	defer reader.Close()
+
+	payload, err := io.ReadAll(io.LimitReader(reader, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	if len(payload) > maxDecompressedSize {
+		return nil, ErrMessageTooLarge
+	}
+	return payload, nil
+}
+
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := json.Number(v).Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// plain converts json.Number values back to int64 or float64, including
+// those nested in objects and arrays
+func plain(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = plain(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = plain(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,103 @@
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"testing"
+	"time"
+)
+
+const samplePayload = `{"version":"1.1","host":"docker-01","short_message":"container started","full_message":"container started\nwith details","timestamp":1730109600.123,"level":3,"_container_name":"web","_retries":2,"_ratio":0.5,"_id":"ignored"}`
+
+func TestParse(t *testing.T) {
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write([]byte(samplePayload))
+	gz.Close()
+
+	var zlibbed bytes.Buffer
+	zw := zlib.NewWriter(&zlibbed)
+	zw.Write([]byte(samplePayload))
+	zw.Close()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Uncompressed", data: []byte(samplePayload)},
+		{name: "Gzip", data: gzipped.Bytes()},
+		{name: "Zlib", data: zlibbed.Bytes()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := Parse(tt.data)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if msg.Host != "docker-01" || msg.ShortMessage != "container started" || msg.Level != 3 {
+				t.Errorf("Unexpected message: %+v", msg)
+			}
+			expectedTime := time.Date(2024, 10, 28, 10, 0, 0, 123000000, time.UTC)
+			if !msg.Timestamp.Equal(expectedTime) {
+				t.Errorf("Expected timestamp %v, got %v", expectedTime, msg.Timestamp)
+			}
+			if msg.Fields["container_name"] != "web" || msg.Fields["retries"] != int64(2) || msg.Fields["ratio"] != 0.5 {
+				t.Errorf("Unexpected additional fields: %v", msg.Fields)
+			}
+			if _, ok := msg.Fields["id"]; ok {
+				t.Error("Expected reserved _id field to be ignored")
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		expectedError error
+	}{
+		{name: "Invalid JSON", data: `{"short_message":`, expectedError: ErrInvalidMessage},
+		{name: "Missing short message", data: `{"host":"a","level":3}`, expectedError: ErrMissingShortMsg},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestParse_DefaultLevel(t *testing.T) {
+	msg, err := Parse([]byte(`{"short_message":"no level"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if msg.Level != defaultLevel {
+		t.Errorf("Expected default level %d, got %d", defaultLevel, msg.Level)
+	}
+}
+
+func TestParse_NestedNumbers(t *testing.T) {
+	msg, err := Parse([]byte(`{"short_message":"nested","_request":{"status":200,"timing":{"ms":1.5}},"_ports":[80,443]}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	request, ok := msg.Fields["request"].(map[string]interface{})
+	if !ok || request["status"] != int64(200) {
+		t.Fatalf("Expected nested numbers to be converted, got %#v", msg.Fields["request"])
+	}
+	if timing, ok := request["timing"].(map[string]interface{}); !ok || timing["ms"] != 1.5 {
+		t.Errorf("Expected deeply nested numbers to be converted, got %#v", request["timing"])
+	}
+	if ports, ok := msg.Fields["ports"].([]interface{}); !ok || len(ports) != 2 || ports[0] != int64(80) || ports[1] != int64(443) {
+		t.Errorf("Expected numbers in arrays to be converted, got %#v", msg.Fields["ports"])
+	}
+}
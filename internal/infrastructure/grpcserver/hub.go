@@ -0,0 +1,111 @@
+package grpcserver
+
+import (
+	"sync"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/metrics"
+)
+
+// subscriberBuffer is the number of logs a slow TailLogs stream may fall behind before logs are dropped for it
+const subscriberBuffer = 256
+
+// Hub fans published logs out to TailLogs streams. It implements the
+// usecase's publisher interface, with one channel per application ID.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan []byte]struct{}
+	closed      bool
+	metrics     *metrics.Transport
+}
+
+// HubOption configures optional Hub behaviour
+type HubOption func(*Hub)
+
+// WithHubMetrics reports subscribers, streams and published or dropped logs
+func WithHubMetrics(t *metrics.Transport) HubOption {
+	return func(h *Hub) {
+		h.metrics = t
+	}
+}
+
+func NewHub(opts ...HubOption) *Hub {
+	h := &Hub{subscribers: make(map[string]map[chan []byte]struct{})}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Subscribe returns the logs published to a channel until cancel is called.
+// The channel is closed when the hub is closed.
+func (h *Hub) Subscribe(channel string) (<-chan []byte, func()) {
+	ch := make(chan []byte, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if h.subscribers[channel] == nil {
+		h.subscribers[channel] = make(map[chan []byte]struct{})
+		h.metrics.StreamOpened()
+	}
+	h.subscribers[channel][ch] = struct{}{}
+	h.metrics.Subscribed()
+	h.mu.Unlock()
+
+	cancel := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		// Already removed by Close
+		if _, ok := h.subscribers[channel][ch]; !ok {
+			return
+		}
+		delete(h.subscribers[channel], ch)
+		h.metrics.Unsubscribed()
+		if len(h.subscribers[channel]) == 0 {
+			delete(h.subscribers, channel)
+			h.metrics.StreamClosed()
+		}
+	}
+	return ch, cancel
+}
+
+// StreamExists reports whether a channel has subscribers
+func (h *Hub) StreamExists(channel string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers[channel]) > 0
+}
+
+// Publish delivers data to every subscriber of a channel without blocking;
+// subscribers whose buffer is full miss the log
+func (h *Hub) Publish(channel string, data []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.subscribers[channel] {
+		select {
+		case ch <- data:
+			h.metrics.Published()
+		default:
+			h.metrics.Dropped()
+		}
+	}
+}
+
+// Close ends every subscription by closing its channel, so that tail streams
+// return and a graceful stop of the gRPC server does not wait on them
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for channel, subscribers := range h.subscribers {
+		for ch := range subscribers {
+			close(ch)
+			h.metrics.Unsubscribed()
+		}
+		delete(h.subscribers, channel)
+		h.metrics.StreamClosed()
+	}
+}
@@ -0,0 +1,88 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/tls"
+
+	loggingv1 "github.com/rubensantoniorosa2704/LoggingSSE/api/logging/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ClientIdentities binds verified TLS client certificates to applications, as
+// the HTTP API does under mutual TLS
+type ClientIdentities interface {
+	// Bind restricts ctx to the application of the client certificate, if any
+	Bind(ctx context.Context, state *tls.ConnectionState) context.Context
+	// Refusal reports why an ingestion call must be refused, or ""
+	Refusal(ctx context.Context, state *tls.ConnectionState) string
+}
+
+// ingestionMethods are guarded by ClientIdentities.Refusal, like the HTTP
+// ingestion routes. TailLogs is only bound.
+var ingestionMethods = map[string]bool{
+	loggingv1.LogService_CreateLog_FullMethodName:  true,
+	loggingv1.LogService_IngestLogs_FullMethodName: true,
+}
+
+// WithClientIdentities returns the server options that apply client
+// certificate bindings to every call
+func WithClientIdentities(identities ClientIdentities) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			ctx, err := authorize(ctx, identities, info.FullMethod)
+			if err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			ctx, err := authorize(stream.Context(), identities, info.FullMethod)
+			if err != nil {
+				return err
+			}
+			return handler(srv, &boundStream{ServerStream: stream, ctx: ctx})
+		}),
+	}
+}
+
+// authorize binds ctx to the caller's application and refuses ingestion calls
+// that the bindings do not allow
+func authorize(ctx context.Context, identities ClientIdentities, method string) (context.Context, error) {
+	state := tlsState(ctx)
+	ctx = identities.Bind(ctx, state)
+	if !ingestionMethods[method] {
+		return ctx, nil
+	}
+	if message := identities.Refusal(ctx, state); message != "" {
+		return nil, status.Error(codes.PermissionDenied, message)
+	}
+	return ctx, nil
+}
+
+// tlsState returns the TLS connection state of the caller, or nil over
+// plaintext connections
+func tlsState(ctx context.Context) *tls.ConnectionState {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil
+	}
+	return &info.State
+}
+
+// boundStream carries the bound context to stream handlers
+type boundStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *boundStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,75 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"github.com/google/uuid"
+	loggingv1 "github.com/rubensantoniorosa2704/LoggingSSE/api/logging/v1"
+	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Mock identities binding every verified certificate to one application
+type mockClientIdentities struct {
+	applicationID uuid.UUID
+}
+
+func (m *mockClientIdentities) Bind(ctx context.Context, state *tls.ConnectionState) context.Context {
+	if state == nil || len(state.VerifiedChains) == 0 {
+		return ctx
+	}
+	return usecase.BindApplication(ctx, m.applicationID)
+}
+
+func (m *mockClientIdentities) Refusal(ctx context.Context, state *tls.ConnectionState) string {
+	if _, ok := usecase.BoundApplication(ctx); !ok {
+		return "A verified client certificate is required."
+	}
+	return ""
+}
+
+func TestAuthorize(t *testing.T) {
+	identities := &mockClientIdentities{applicationID: uuid.New()}
+	verified := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}},
+	})
+	plaintext := peer.NewContext(context.Background(), &peer.Peer{})
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		bound  bool
+		code   codes.Code
+	}{
+		{name: "Verified ingestion", ctx: verified, method: loggingv1.LogService_CreateLog_FullMethodName, bound: true, code: codes.OK},
+		{name: "Verified stream", ctx: verified, method: loggingv1.LogService_IngestLogs_FullMethodName, bound: true, code: codes.OK},
+		{name: "Plaintext ingestion", ctx: plaintext, method: loggingv1.LogService_CreateLog_FullMethodName, code: codes.PermissionDenied},
+		{name: "Plaintext tail", ctx: plaintext, method: loggingv1.LogService_TailLogs_FullMethodName, code: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := authorize(tt.ctx, identities, tt.method)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("Expected code %v, got %v", tt.code, code)
+			}
+			if err != nil {
+				return
+			}
+			applicationID, ok := usecase.BoundApplication(ctx)
+			if ok != tt.bound {
+				t.Errorf("Expected bound %v, got %v", tt.bound, ok)
+			}
+			if ok && applicationID != identities.applicationID {
+				t.Errorf("Expected application %s, got %s", identities.applicationID, applicationID)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package grpcserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	loggingv1 "github.com/rubensantoniorosa2704/LoggingSSE/api/logging/v1"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toCreateLogInput converts a request to the usecase input. Only the UUIDs are
+// checked here; the rest is validated by the domain like any other log.
+func toCreateLogInput(req *loggingv1.CreateLogRequest) (dto.CreateLogInput, error) {
+	applicationID, err := uuid.Parse(req.GetApplicationId())
+	if err != nil {
+		return dto.CreateLogInput{}, fmt.Errorf("invalid application_id %q", req.GetApplicationId())
+	}
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return dto.CreateLogInput{}, fmt.Errorf("invalid user_id %q", req.GetUserId())
+	}
+
+	input := dto.CreateLogInput{
+		ApplicationID:  applicationID,
+		UserID:         userID,
+		Message:        req.GetMessage(),
+		Level:          req.GetLevel(),
+		Source:         req.GetSource(),
+		Tags:           req.GetTags(),
+		IdempotencyKey: req.GetIdempotencyKey(),
+	}
+	if req.GetId() != "" {
+		id, err := uuid.Parse(req.GetId())
+		if err != nil {
+			return dto.CreateLogInput{}, fmt.Errorf("invalid id %q", req.GetId())
+		}
+		input.ID = &id
+	}
+	if req.GetMetadata() != nil {
+		input.Metadata = req.GetMetadata().AsMap()
+	}
+	if req.GetTimestamp() != nil {
+		timestamp := req.GetTimestamp().AsTime()
+		input.Timestamp = &timestamp
+	}
+	return input, nil
+}
+
+// toProtoLog converts a log as published to subscribers or returned by the usecase
+func toProtoLog(output dto.LogOutput) (*loggingv1.Log, error) {
+	l := &loggingv1.Log{
+		Id:            output.ID.String(),
+		ApplicationId: output.ApplicationID.String(),
+		UserId:        output.UserID.String(),
+		Message:       output.Message,
+		Level:         output.Level,
+		Source:        output.Source,
+		Tags:          output.Tags,
+	}
+	if len(output.Metadata) > 0 {
+		metadata, err := toStruct(output.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert metadata: %w", err)
+		}
+		l.Metadata = metadata
+	}
+	if timestamp, err := time.Parse(time.RFC3339Nano, output.Timestamp); err == nil {
+		l.Timestamp = timestamppb.New(timestamp)
+	}
+	if ingestedAt, err := time.Parse(time.RFC3339Nano, output.IngestedAt); err == nil {
+		l.IngestedAt = timestamppb.New(ingestedAt)
+	}
+	return l, nil
+}
+
+// toStruct converts metadata, normalising values that are not JSON types
+// (e.g. int64 or values read back from storage) through a JSON round trip
+func toStruct(metadata map[string]interface{}) (*structpb.Struct, error) {
+	if s, err := structpb.NewStruct(metadata); err == nil {
+		return s, nil
+	}
+
+	payload, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	var normalised map[string]interface{}
+	if err := json.Unmarshal(payload, &normalised); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(normalised)
+}
+
+func createOutputToLogOutput(output dto.CreateLogOutput) dto.LogOutput {
+	return dto.LogOutput{
+		ID:            output.ID,
+		ApplicationID: output.ApplicationID,
+		UserID:        output.UserID,
+		Message:       output.Message,
+		Level:         output.Level,
+		Source:        output.Source,
+		Tags:          output.Tags,
+		Metadata:      output.Metadata,
+		Timestamp:     output.Timestamp,
+		IngestedAt:    output.IngestedAt,
+	}
+}
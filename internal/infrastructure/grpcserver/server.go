@@ -0,0 +1,166 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	loggingv1 "github.com/rubensantoniorosa2704/LoggingSSE/api/logging/v1"
+	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// maxReportedErrors bounds the per-entry errors returned by IngestLogs; the
+// rejected count still covers every entry
+const maxReportedErrors = 1000
+
+// LogServer implements loggingv1.LogServiceServer on top of the log usecase
+type LogServer struct {
+	loggingv1.UnimplementedLogServiceServer
+
+	Usecase usecase.LogUsecaseInterface
+	Hub     *Hub
+}
+
+func NewLogServer(uc usecase.LogUsecaseInterface, hub *Hub) *LogServer {
+	return &LogServer{
+		Usecase: uc,
+		Hub:     hub,
+	}
+}
+
+// NewServer creates a gRPC server exposing the log service and server reflection
+func NewServer(logServer *LogServer, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	loggingv1.RegisterLogServiceServer(server, logServer)
+	reflection.Register(server)
+	return server
+}
+
+func (s *LogServer) CreateLog(ctx context.Context, req *loggingv1.CreateLogRequest) (*loggingv1.CreateLogResponse, error) {
+	input, err := toCreateLogInput(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	output, err := s.Usecase.CreateLog(ctx, input)
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+
+	l, err := toProtoLog(createOutputToLogOutput(*output))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &loggingv1.CreateLogResponse{
+		Log:      l,
+		Queued:   output.Queued,
+		Replayed: output.Replayed,
+	}, nil
+}
+
+func (s *LogServer) IngestLogs(stream loggingv1.LogService_IngestLogsServer) error {
+	response := &loggingv1.IngestLogsResponse{}
+	reject := func(index uint64, st *status.Status) {
+		response.Rejected++
+		if len(response.Errors) < maxReportedErrors {
+			response.Errors = append(response.Errors, &loggingv1.IngestError{
+				Index:   index,
+				Code:    st.Code().String(),
+				Message: st.Message(),
+			})
+		}
+	}
+
+	for index := uint64(0); ; index++ {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(response)
+		}
+		if err != nil {
+			return err
+		}
+
+		input, err := toCreateLogInput(req)
+		if err != nil {
+			reject(index, status.New(codes.InvalidArgument, err.Error()))
+			continue
+		}
+		if _, err := s.Usecase.CreateLog(stream.Context(), input); err != nil {
+			reject(index, status.Convert(statusFromError(err)))
+			continue
+		}
+		response.Accepted++
+	}
+}
+
+func (s *LogServer) TailLogs(req *loggingv1.TailLogsRequest, stream loggingv1.LogService_TailLogsServer) error {
+	applicationID, err := uuid.Parse(req.GetApplicationId())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid application_id %q", req.GetApplicationId())
+	}
+
+	logs, cancel := s.Hub.Subscribe(applicationID.String())
+	defer cancel()
+	logger := slog.With("application_id", applicationID.String())
+	connected := time.Now()
+	logger.Info("gRPC tail client connected")
+	defer func() {
+		logger.Info("gRPC tail client disconnected", "duration", time.Since(connected))
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case payload, ok := <-logs:
+			if !ok {
+				return status.Error(codes.Unavailable, "The server is shutting down, please reconnect.")
+			}
+			var output dto.LogOutput
+			if err := json.Unmarshal(payload, &output); err != nil {
+				continue
+			}
+			l, err := toProtoLog(output)
+			if err != nil {
+				continue
+			}
+			if err := stream.Send(l); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// statusFromError maps usecase errors to the codes the HTTP API expresses with status codes
+func statusFromError(err error) error {
+	failure := usecase.ClassifyCreateError(err)
+	switch failure.Kind {
+	case usecase.CreateErrorRateLimited:
+		st := status.New(codes.ResourceExhausted, failure.Message)
+		if detailed, detailErr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(failure.RetryAfter)}); detailErr == nil {
+			st = detailed
+		}
+		return st.Err()
+	case usecase.CreateErrorUnavailable:
+		return status.Error(codes.Unavailable, failure.Message)
+	case usecase.CreateErrorDuplicate:
+		return status.Error(codes.AlreadyExists, failure.Message)
+	case usecase.CreateErrorForbidden:
+		return status.Error(codes.PermissionDenied, failure.Message)
+	case usecase.CreateErrorInvalid:
+		return status.Error(codes.InvalidArgument, failure.Message)
+	default:
+		slog.Error("gRPC CreateLog failed", "error", err)
+		return status.Error(codes.Internal, failure.Message)
+	}
+}
@@ -0,0 +1,293 @@
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	loggingv1 "github.com/rubensantoniorosa2704/LoggingSSE/api/logging/v1"
+	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Mock usecase for testing
+type mockLogUsecase struct {
+	mu     sync.Mutex
+	inputs []dto.CreateLogInput
+	err    func(input dto.CreateLogInput) error
+}
+
+func (m *mockLogUsecase) CreateLog(ctx context.Context, input dto.CreateLogInput) (*dto.CreateLogOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inputs = append(m.inputs, input)
+	if m.err != nil {
+		if err := m.err(input); err != nil {
+			return nil, err
+		}
+	}
+	timestamp := time.Now()
+	if input.Timestamp != nil {
+		timestamp = *input.Timestamp
+	}
+	return &dto.CreateLogOutput{
+		ID:            uuid.New(),
+		ApplicationID: input.ApplicationID,
+		UserID:        input.UserID,
+		Message:       input.Message,
+		Level:         input.Level,
+		Metadata:      input.Metadata,
+		Timestamp:     timestamp.Format(time.RFC3339Nano),
+		IngestedAt:    time.Now().Format(time.RFC3339Nano),
+	}, nil
+}
+
+func (m *mockLogUsecase) ListLogs(ctx context.Context, input dto.ListLogsInput) (*dto.ListLogsOutput, error) {
+	return nil, nil
+}
+
+func newClient(t *testing.T, uc *mockLogUsecase, hub *Hub) loggingv1.LogServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(NewLogServer(uc, hub))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return loggingv1.NewLogServiceClient(conn)
+}
+
+func validRequest(message string) *loggingv1.CreateLogRequest {
+	return &loggingv1.CreateLogRequest{
+		ApplicationId: uuid.NewString(),
+		UserId:        uuid.NewString(),
+		Message:       message,
+		Level:         "INFO",
+	}
+}
+
+func TestLogServer_CreateLog(t *testing.T) {
+	uc := &mockLogUsecase{}
+	client := newClient(t, uc, NewHub())
+
+	req := validRequest("Test gRPC log")
+	req.Id = uuid.NewString()
+	req.Tags = map[string]string{"env": "test"}
+	req.Metadata, _ = structpb.NewStruct(map[string]interface{}{"attempt": 2})
+	eventTime := time.Date(2025, 10, 28, 10, 0, 0, 123456789, time.UTC)
+	req.Timestamp = timestamppb.New(eventTime)
+	req.IdempotencyKey = "retry-1"
+
+	response, err := client.CreateLog(context.Background(), req)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.GetLog().GetMessage() != "Test gRPC log" || response.GetLog().GetMetadata().AsMap()["attempt"] != float64(2) {
+		t.Errorf("Unexpected response: %v", response)
+	}
+	if !response.GetLog().GetTimestamp().AsTime().Equal(eventTime) {
+		t.Errorf("Expected event time %v, got %v", eventTime, response.GetLog().GetTimestamp().AsTime())
+	}
+
+	input := uc.inputs[0]
+	if input.ID == nil || input.ID.String() != req.Id {
+		t.Errorf("Expected client ID to be passed, got %v", input.ID)
+	}
+	if input.Tags["env"] != "test" || input.IdempotencyKey != "retry-1" || input.Timestamp == nil {
+		t.Errorf("Unexpected usecase input: %+v", input)
+	}
+}
+
+func TestLogServer_CreateLog_Errors(t *testing.T) {
+	uc := &mockLogUsecase{err: func(input dto.CreateLogInput) error {
+		switch input.Message {
+		case "invalid":
+			return usecase.ErrInvalidLog
+		case "limited":
+			return &ratelimit.ExceededError{Kind: ratelimit.LimitEntries, RetryAfter: 2 * time.Second}
+		}
+		return nil
+	}}
+	client := newClient(t, uc, NewHub())
+
+	badID := validRequest("bad application")
+	badID.ApplicationId = "not-a-uuid"
+
+	tests := []struct {
+		name string
+		req  *loggingv1.CreateLogRequest
+		code codes.Code
+	}{
+		{name: "Invalid UUID", req: badID, code: codes.InvalidArgument},
+		{name: "Domain validation", req: validRequest("invalid"), code: codes.InvalidArgument},
+		{name: "Rate limited", req: validRequest("limited"), code: codes.ResourceExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreateLog(context.Background(), tt.req)
+			if status.Code(err) != tt.code {
+				t.Errorf("Expected code %s, got %v", tt.code, err)
+			}
+		})
+	}
+
+	_, err := client.CreateLog(context.Background(), validRequest("limited"))
+	details := status.Convert(err).Details()
+	if len(details) != 1 || details[0].(*errdetails.RetryInfo).GetRetryDelay().AsDuration() != 2*time.Second {
+		t.Errorf("Expected RetryInfo detail, got %v", details)
+	}
+}
+
+func TestLogServer_IngestLogs(t *testing.T) {
+	uc := &mockLogUsecase{err: func(input dto.CreateLogInput) error {
+		if input.Message == "invalid" {
+			return usecase.ErrInvalidLog
+		}
+		return nil
+	}}
+	client := newClient(t, uc, NewHub())
+
+	stream, err := client.IngestLogs(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	bad := validRequest("bad user")
+	bad.UserId = ""
+	for _, req := range []*loggingv1.CreateLogRequest{validRequest("first"), bad, validRequest("invalid"), validRequest("last")} {
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("Failed to send: %v", err)
+		}
+	}
+	response, err := stream.CloseAndRecv()
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.GetAccepted() != 2 || response.GetRejected() != 2 || len(response.GetErrors()) != 2 {
+		t.Fatalf("Unexpected response: %v", response)
+	}
+	if response.GetErrors()[0].GetIndex() != 1 || response.GetErrors()[1].GetIndex() != 2 {
+		t.Errorf("Expected errors for entries 1 and 2, got %v", response.GetErrors())
+	}
+	if response.GetErrors()[0].GetCode() != codes.InvalidArgument.String() {
+		t.Errorf("Expected InvalidArgument, got %s", response.GetErrors()[0].GetCode())
+	}
+}
+
+func TestLogServer_TailLogs(t *testing.T) {
+	hub := NewHub()
+	client := newClient(t, &mockLogUsecase{}, hub)
+	applicationID := uuid.New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.TailLogs(ctx, &loggingv1.TailLogsRequest{ApplicationId: applicationID.String()})
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !hub.StreamExists(applicationID.String()) {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the tail stream to subscribe")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if hub.StreamExists(uuid.NewString()) {
+		t.Error("Expected no subscribers for other applications")
+	}
+
+	payload, _ := json.Marshal(dto.LogOutput{
+		ID:            uuid.New(),
+		ApplicationID: applicationID,
+		Message:       "Tailed log",
+		Level:         "ERROR",
+		Metadata:      map[string]interface{}{"nested": map[string]interface{}{"ok": true}},
+		Timestamp:     time.Now().Format(time.RFC3339Nano),
+		IngestedAt:    time.Now().Format(time.RFC3339Nano),
+	})
+	hub.Publish(applicationID.String(), payload)
+
+	l, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if l.GetMessage() != "Tailed log" || l.GetLevel() != "ERROR" || l.GetApplicationId() != applicationID.String() {
+		t.Errorf("Unexpected log: %v", l)
+	}
+
+	cancel()
+	deadline = time.Now().Add(2 * time.Second)
+	for hub.StreamExists(applicationID.String()) {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the subscription to be removed when the client disconnects")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLogServer_TailLogs_HubClosed(t *testing.T) {
+	hub := NewHub()
+	client := newClient(t, &mockLogUsecase{}, hub)
+	applicationID := uuid.NewString()
+
+	stream, err := client.TailLogs(context.Background(), &loggingv1.TailLogsRequest{ApplicationId: applicationID})
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !hub.StreamExists(applicationID) {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the tail stream to subscribe")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	hub.Close()
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable once the hub is closed, got %v", err)
+	}
+	if hub.StreamExists(applicationID) {
+		t.Error("Expected no subscribers after close")
+	}
+	hub.Publish(applicationID, []byte("{}"))
+
+	logs, cancel := hub.Subscribe(applicationID)
+	defer cancel()
+	if _, ok := <-logs; ok {
+		t.Error("Expected subscriptions after close to be closed")
+	}
+}
+
+func TestLogServer_TailLogs_InvalidApplication(t *testing.T) {
+	client := newClient(t, &mockLogUsecase{}, NewHub())
+
+	stream, err := client.TailLogs(context.Background(), &loggingv1.TailLogsRequest{ApplicationId: "nope"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package http
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/httputil"
+)
+
+// RequireAdmin guards the routes that change behaviour for every client, such
+// as rate limit overrides. Requests must send "Authorization: Bearer <token>";
+// without a configured token the routes are disabled.
+func RequireAdmin(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				httputil.WriteError(w, http.StatusForbidden, "Administrative routes are disabled; set ADMIN_TOKEN to enable them.")
+				return
+			}
+			presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+				httputil.WriteError(w, http.StatusUnauthorized, "A valid admin token is required.")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
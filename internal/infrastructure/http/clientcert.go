@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	applicationLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/httputil"
+)
+
+// ClientIdentities maps the identities of verified TLS client certificates to
+// application IDs. The mapping can be replaced while the server is running.
+type ClientIdentities struct {
+	applications atomic.Pointer[map[string]uuid.UUID]
+	// required makes ingestion routes refuse clients without a verified
+	// certificate, or with an unmapped one while bindings are configured
+	required bool
+}
+
+// NewClientIdentities binds certificates to applications. required is set
+// when mutual TLS is configured, so that ingestion cannot bypass the bindings.
+func NewClientIdentities(applications map[string]uuid.UUID, required bool) *ClientIdentities {
+	c := &ClientIdentities{required: required}
+	c.Set(applications)
+	return c
+}
+
+// Set replaces the mapping of identities to application IDs
+func (c *ClientIdentities) Set(applications map[string]uuid.UUID) {
+	c.applications.Store(&applications)
+}
+
+// Lookup returns the application of a certificate, trying its URI, DNS and
+// email SANs before the subject common name
+func (c *ClientIdentities) Lookup(cert *x509.Certificate) (uuid.UUID, bool) {
+	applications := *c.applications.Load()
+
+	identities := make([]string, 0, len(cert.URIs)+len(cert.DNSNames)+len(cert.EmailAddresses)+1)
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	identities = append(identities, cert.DNSNames...)
+	identities = append(identities, cert.EmailAddresses...)
+	identities = append(identities, cert.Subject.CommonName)
+
+	for _, identity := range identities {
+		if applicationID, ok := applications[identity]; ok && identity != "" {
+			return applicationID, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+// Bind restricts ctx to the application of the verified client certificate in
+// state, if the certificate is mapped to one
+func (c *ClientIdentities) Bind(ctx context.Context, state *tls.ConnectionState) context.Context {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ctx
+	}
+	if applicationID, ok := c.Lookup(state.VerifiedChains[0][0]); ok {
+		return applicationLog.BindApplication(ctx, applicationID)
+	}
+	return ctx
+}
+
+// Refusal reports why an ingestion request must be refused when mutual TLS is
+// configured: a verified client certificate is required, and it must be bound
+// to an application unless no bindings are configured. ctx must come from
+// Bind. It returns "" when the request may proceed.
+func (c *ClientIdentities) Refusal(ctx context.Context, state *tls.ConnectionState) string {
+	if !c.required {
+		return ""
+	}
+	if state == nil || len(state.VerifiedChains) == 0 {
+		return "A verified client certificate is required."
+	}
+	if len(*c.applications.Load()) > 0 {
+		if _, ok := applicationLog.BoundApplication(ctx); !ok {
+			return "The client certificate is not bound to an application."
+		}
+	}
+	return ""
+}
+
+// Middleware binds requests with a mapped client certificate to its
+// application: logs for other applications are refused, and the
+// X-Application-ID header read by header-based ingestion APIs is set.
+// Requests without a certificate, or with an unmapped one, are left to
+// RequireIdentity on ingestion routes.
+func (c *ClientIdentities) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := c.Bind(r.Context(), r.TLS)
+		applicationID, ok := applicationLog.BoundApplication(ctx)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r.Header.Set("X-Application-ID", applicationID.String())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireIdentity guards ingestion routes with Refusal. It must run after
+// Middleware.
+func (c *ClientIdentities) RequireIdentity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if message := c.Refusal(r.Context(), r.TLS); message != "" {
+			httputil.WriteError(w, http.StatusForbidden, message)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
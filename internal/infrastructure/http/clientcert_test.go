@@ -0,0 +1,130 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	applicationLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+)
+
+func TestClientIdentities_Lookup(t *testing.T) {
+	byURI, byDNS, byName := uuid.New(), uuid.New(), uuid.New()
+	identities := NewClientIdentities(map[string]uuid.UUID{
+		"spiffe://example.com/billing": byURI,
+		"shipper.example.com":          byDNS,
+		"checkout":                     byName,
+	}, false)
+
+	spiffe, _ := url.Parse("spiffe://example.com/billing")
+	tests := []struct {
+		name string
+		cert *x509.Certificate
+		want uuid.UUID
+		ok   bool
+	}{
+		{name: "URI SAN first", cert: &x509.Certificate{URIs: []*url.URL{spiffe}, DNSNames: []string{"shipper.example.com"}}, want: byURI, ok: true},
+		{name: "DNS SAN", cert: &x509.Certificate{DNSNames: []string{"shipper.example.com"}, Subject: pkix.Name{CommonName: "checkout"}}, want: byDNS, ok: true},
+		{name: "Common name", cert: &x509.Certificate{Subject: pkix.Name{CommonName: "checkout"}}, want: byName, ok: true},
+		{name: "Unmapped", cert: &x509.Certificate{Subject: pkix.Name{CommonName: "unknown"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := identities.Lookup(tt.cert)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Expected %s, %v, got %s, %v", tt.want, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestClientIdentities_Middleware(t *testing.T) {
+	applicationID := uuid.New()
+	identities := NewClientIdentities(map[string]uuid.UUID{"checkout": applicationID}, false)
+
+	var bound uuid.UUID
+	var isBound bool
+	var header string
+	handler := identities.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bound, isBound = applicationLog.BoundApplication(r.Context())
+		header = r.Header.Get("X-Application-ID")
+	}))
+
+	serve := func(cert *x509.Certificate) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/logs/text", nil)
+		req.Header.Set("X-Application-ID", uuid.NewString())
+		if cert != nil {
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	serve(&x509.Certificate{Subject: pkix.Name{CommonName: "checkout"}})
+	if !isBound || bound != applicationID {
+		t.Errorf("Expected the request to be bound to %s, got %s, %v", applicationID, bound, isBound)
+	}
+	if header != applicationID.String() {
+		t.Errorf("Expected X-Application-ID to be replaced with %s, got %s", applicationID, header)
+	}
+
+	serve(&x509.Certificate{Subject: pkix.Name{CommonName: "unknown"}})
+	if isBound {
+		t.Error("Expected an unmapped certificate not to bind the request")
+	}
+
+	serve(nil)
+	if isBound {
+		t.Error("Expected a request without certificate not to be bound")
+	}
+
+	// The mapping can be reloaded
+	identities.Set(map[string]uuid.UUID{})
+	serve(&x509.Certificate{Subject: pkix.Name{CommonName: "checkout"}})
+	if isBound {
+		t.Error("Expected a removed mapping not to bind the request")
+	}
+}
+
+func TestClientIdentities_RequireIdentity(t *testing.T) {
+	applicationID := uuid.New()
+	checkout := &x509.Certificate{Subject: pkix.Name{CommonName: "checkout"}}
+	unknown := &x509.Certificate{Subject: pkix.Name{CommonName: "unknown"}}
+
+	tests := []struct {
+		name     string
+		required bool
+		bindings map[string]uuid.UUID
+		cert     *x509.Certificate
+		want     int
+	}{
+		{name: "Not required", bindings: map[string]uuid.UUID{"checkout": applicationID}, want: http.StatusOK},
+		{name: "Bound certificate", required: true, bindings: map[string]uuid.UUID{"checkout": applicationID}, cert: checkout, want: http.StatusOK},
+		{name: "Missing certificate", required: true, bindings: map[string]uuid.UUID{"checkout": applicationID}, want: http.StatusForbidden},
+		{name: "Unmapped certificate", required: true, bindings: map[string]uuid.UUID{"checkout": applicationID}, cert: unknown, want: http.StatusForbidden},
+		{name: "Verified certificate without bindings", required: true, cert: unknown, want: http.StatusOK},
+		{name: "Missing certificate without bindings", required: true, want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identities := NewClientIdentities(tt.bindings, tt.required)
+			handler := identities.Middleware(identities.RequireIdentity(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/logs", nil)
+			if tt.cert != nil {
+				req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{tt.cert}}}
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package elasticsearch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+var (
+	ErrInvalidAction  = errors.New("elasticsearch: malformed bulk action")
+	ErrMissingMessage = errors.New("elasticsearch: document has no message field")
+	ErrNoApplication  = errors.New("elasticsearch: document has no application ID")
+)
+
+// maxLineSize bounds a single action or document line
+const maxLineSize = 4 << 20
+
+// FieldMapping lists, for each log attribute, the document fields tried in
+// order. Dotted names are looked up both as nested objects and literal keys.
+type FieldMapping struct {
+	Message     []string
+	Level       []string
+	Timestamp   []string
+	Source      []string
+	Application []string
+	User        []string
+}
+
+// DefaultFieldMapping covers the fields written by Filebeat, Fluent Bit and ECS loggers
+func DefaultFieldMapping() FieldMapping {
+	return FieldMapping{
+		Message:     []string{"message", "log", "msg"},
+		Level:       []string{"log.level", "level", "severity"},
+		Timestamp:   []string{"@timestamp", "timestamp", "time"},
+		Source:      []string{"service.name", "host.name", "host", "kubernetes.pod.name"},
+		Application: []string{"application_id", "labels.application_id"},
+		User:        []string{"user_id", "labels.user_id"},
+	}
+}
+
+// bulkItem is one action and its document from a _bulk body
+type bulkItem struct {
+	op       string
+	index    string
+	id       string
+	document map[string]interface{}
+	err      error
+}
+
+// parseBulk reads newline-delimited action/document pairs. Malformed pairs
+// are kept as items carrying an error so that they get a per-item status.
+func parseBulk(body []byte, defaultIndex string) ([]bulkItem, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var items []bulkItem
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var action map[string]struct {
+			Index string `json:"_index"`
+			ID    string `json:"_id"`
+		}
+		if err := json.Unmarshal(line, &action); err != nil || len(action) != 1 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAction, truncate(line))
+		}
+
+		var item bulkItem
+		for op, meta := range action {
+			item = bulkItem{op: op, index: meta.Index, id: meta.ID}
+		}
+		if item.index == "" {
+			item.index = defaultIndex
+		}
+
+		switch item.op {
+		case "index", "create":
+		case "update":
+			item.err = errors.New("update is not supported, logs are immutable")
+		case "delete":
+			item.err = errors.New("delete is not supported, logs are immutable")
+			items = append(items, item)
+			continue
+		default:
+			return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidAction, item.op)
+		}
+
+		if !scanner.Scan() {
+			return nil, fmt.Errorf("%w: %s action without a document", ErrInvalidAction, item.op)
+		}
+		if item.err == nil {
+			decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+			decoder.UseNumber()
+			if err := decoder.Decode(&item.document); err != nil {
+				item.err = fmt.Errorf("failed to parse document: %v", err)
+			}
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAction, err)
+	}
+	return items, nil
+}
+
+// toCreateLogInput maps a document using the field mapping. Mapped fields are
+// removed and the rest of the document is kept as metadata.
+func toCreateLogInput(mapping FieldMapping, item bulkItem, defaults owner) (dto.CreateLogInput, error) {
+	doc := item.document
+
+	message, ok := takeString(doc, mapping.Message)
+	if !ok {
+		return dto.CreateLogInput{}, ErrMissingMessage
+	}
+
+	input := dto.CreateLogInput{
+		ApplicationID: defaults.applicationID,
+		UserID:        defaults.userID,
+		Message:       message,
+		Level:         valueobjects.LogLevelInfo.String(),
+	}
+
+	// The index name is used as the application when it is a UUID
+	if id, err := uuid.Parse(item.index); err == nil {
+		input.ApplicationID = id
+	}
+	if raw, ok := takeString(doc, mapping.Application); ok {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return dto.CreateLogInput{}, fmt.Errorf("invalid application ID %q", raw)
+		}
+		input.ApplicationID = id
+	}
+	if input.ApplicationID == uuid.Nil {
+		return dto.CreateLogInput{}, ErrNoApplication
+	}
+	if raw, ok := takeString(doc, mapping.User); ok {
+		if id, err := uuid.Parse(raw); err == nil {
+			input.UserID = id
+		}
+	}
+
+	if raw, ok := takeString(doc, mapping.Level); ok {
+		if level, ok := valueobjects.ParseLogLevelAlias(raw); ok {
+			input.Level = level.String()
+		}
+	}
+	if source, ok := takeString(doc, mapping.Source); ok {
+		input.Source = source
+	}
+	if timestamp, ok := takeTime(doc, mapping.Timestamp); ok {
+		input.Timestamp = &timestamp
+	}
+
+	// A UUID _id is used as the log ID so that retried bulk requests conflict instead of duplicating
+	if id, err := uuid.Parse(item.id); err == nil {
+		input.ID = &id
+	} else if item.id != "" {
+		input.IdempotencyKey = "es:" + item.index + ":" + item.id
+	}
+
+	if len(doc) > 0 {
+		input.Metadata = plain(doc).(map[string]interface{})
+	}
+	return input, nil
+}
+
+// owner is the application and user used when a document does not name one
+type owner struct {
+	applicationID uuid.UUID
+	userID        uuid.UUID
+}
+
+// takeString finds the first candidate field holding a non-empty scalar and removes it
+func takeString(doc map[string]interface{}, fields []string) (string, bool) {
+	for _, field := range fields {
+		value, ok := take(doc, field)
+		if !ok {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			if v != "" {
+				return v, true
+			}
+		case json.Number:
+			return v.String(), true
+		case bool:
+			return fmt.Sprint(v), true
+		}
+	}
+	return "", false
+}
+
+// takeTime accepts RFC 3339 strings and epoch milliseconds
+func takeTime(doc map[string]interface{}, fields []string) (time.Time, bool) {
+	for _, field := range fields {
+		value, ok := take(doc, field)
+		if !ok {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t, true
+			}
+		case json.Number:
+			if millis, err := v.Int64(); err == nil {
+				return time.UnixMilli(millis).UTC(), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// take looks up a literal key first, then a dotted path through nested
+// objects, removing the value and any objects left empty
+func take(doc map[string]interface{}, field string) (interface{}, bool) {
+	if value, ok := doc[field]; ok {
+		delete(doc, field)
+		return value, true
+	}
+
+	head, rest, nested := strings.Cut(field, ".")
+	if !nested {
+		return nil, false
+	}
+	child, ok := doc[head].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	value, ok := take(child, rest)
+	if ok && len(child) == 0 {
+		delete(doc, head)
+	}
+	return value, ok
+}
+
+// plain converts json.Number values to int64 or float64 so they are stored as numbers
+func plain(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = plain(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = plain(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func truncate(line []byte) string {
+	if len(line) > 100 {
+		return string(line[:100]) + "..."
+	}
+	return string(line)
+}
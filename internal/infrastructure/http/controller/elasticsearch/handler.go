@@ -0,0 +1,239 @@
+package elasticsearch
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/httputil"
+)
+
+const (
+	// compatibleVersion is reported to clients, which refuse to talk to clusters they do not recognise
+	compatibleVersion = "8.11.0"
+
+	maxBodyBytes = 64 << 20
+)
+
+// Config controls how bulk documents are mapped to logs
+type Config struct {
+	Fields FieldMapping
+	// ApplicationID and UserID are used for documents that do not name their
+	// owner and are not sent to an index named after an application UUID
+	ApplicationID uuid.UUID
+	UserID        uuid.UUID
+}
+
+// ElasticsearchController implements the subset of the Elasticsearch API used
+// by Filebeat and Fluent Bit: the cluster handshake, template setup and _bulk
+type ElasticsearchController struct {
+	Usecase usecase.LogUsecaseInterface
+	Config  Config
+}
+
+func NewElasticsearchController(uc usecase.LogUsecaseInterface, cfg Config) *ElasticsearchController {
+	defaults := DefaultFieldMapping()
+	if len(cfg.Fields.Message) == 0 {
+		cfg.Fields.Message = defaults.Message
+	}
+	if len(cfg.Fields.Level) == 0 {
+		cfg.Fields.Level = defaults.Level
+	}
+	if len(cfg.Fields.Timestamp) == 0 {
+		cfg.Fields.Timestamp = defaults.Timestamp
+	}
+	if len(cfg.Fields.Source) == 0 {
+		cfg.Fields.Source = defaults.Source
+	}
+	if len(cfg.Fields.Application) == 0 {
+		cfg.Fields.Application = defaults.Application
+	}
+	if len(cfg.Fields.User) == 0 {
+		cfg.Fields.User = defaults.User
+	}
+
+	return &ElasticsearchController{
+		Usecase: uc,
+		Config:  cfg,
+	}
+}
+
+// Routes mounts the compatibility API; clients are pointed at its prefix (e.g. /es)
+func (c *ElasticsearchController) Routes(r chi.Router) {
+	r.Use(productHeader)
+
+	r.Get("/", c.InfoHandler)
+	r.Head("/", c.InfoHandler)
+	r.Get("/_license", c.LicenseHandler)
+
+	r.Post("/_bulk", c.BulkHandler)
+	r.Put("/_bulk", c.BulkHandler)
+	r.Post("/{index}/_bulk", c.BulkHandler)
+	r.Put("/{index}/_bulk", c.BulkHandler)
+
+	// Setup calls made by shippers before sending data are acknowledged without effect
+	for _, pattern := range []string{
+		"/_index_template/{name}",
+		"/_template/{name}",
+		"/_component_template/{name}",
+		"/_ilm/policy/{name}",
+		"/_ingest/pipeline/{name}",
+		"/_data_stream/{name}",
+	} {
+		r.Head(pattern, acknowledgeHandler)
+		r.Get(pattern, acknowledgeHandler)
+		r.Put(pattern, acknowledgeHandler)
+		r.Post(pattern, acknowledgeHandler)
+	}
+}
+
+// @Summary      Elasticsearch cluster information
+// @Description  Handshake endpoint probed by Elasticsearch clients before sending data.
+// @Tags         Elasticsearch
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Router       /es/ [get]
+func (c *ElasticsearchController) InfoHandler(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"name":         "log-service",
+		"cluster_name": "log-service",
+		"cluster_uuid": "log-service",
+		"version": map[string]interface{}{
+			"number":                              compatibleVersion,
+			"build_flavor":                        "default",
+			"build_type":                          "docker",
+			"lucene_version":                      "9.8.0",
+			"minimum_wire_compatibility_version":  "7.17.0",
+			"minimum_index_compatibility_version": "7.0.0",
+		},
+		"tagline": "You Know, for Search",
+	})
+}
+
+func (c *ElasticsearchController) LicenseHandler(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"license": map[string]interface{}{"status": "active", "type": "basic", "mode": "basic"},
+	})
+}
+
+func acknowledgeHandler(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteJSON(w, http.StatusOK, map[string]interface{}{"acknowledged": true})
+}
+
+// @Summary      Bulk ingest documents in the Elasticsearch format
+// @Description  Parses newline-delimited action/document pairs and stores each index or create document as a log. Responds in the Elasticsearch bulk response shape with a status per item.
+// @Tags         Elasticsearch
+// @Accept       application/x-ndjson
+// @Produce      json
+// @Param        index  path  string  false  "Default index; an application UUID routes documents to that application."
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{} "Malformed bulk body."
+// @Router       /es/_bulk [post]
+func (c *ElasticsearchController) BulkHandler(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	body, err := httputil.ReadBody(w, r, maxBodyBytes)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "parse_exception", "Invalid request body.")
+		return
+	}
+
+	items, err := parseBulk(body, chi.URLParam(r, "index"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "illegal_argument_exception", err.Error())
+		return
+	}
+
+	defaults := owner{applicationID: c.Config.ApplicationID, userID: c.Config.UserID}
+	results := make([]map[string]interface{}, 0, len(items))
+	hasErrors := false
+	for _, item := range items {
+		result := c.indexItem(r, item, defaults)
+		if result["status"].(int) >= 300 {
+			hasErrors = true
+		}
+		results = append(results, map[string]interface{}{item.op: result})
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"took":   time.Since(start).Milliseconds(),
+		"errors": hasErrors,
+		"items":  results,
+	})
+}
+
+// indexItem stores one document and describes the outcome as an Elasticsearch bulk item
+func (c *ElasticsearchController) indexItem(r *http.Request, item bulkItem, defaults owner) map[string]interface{} {
+	result := map[string]interface{}{"_index": item.index}
+	if item.id != "" {
+		result["_id"] = item.id
+	}
+
+	fail := func(status int, errorType, reason string) map[string]interface{} {
+		result["status"] = status
+		result["error"] = map[string]interface{}{"type": errorType, "reason": reason}
+		return result
+	}
+
+	if item.err != nil {
+		return fail(http.StatusBadRequest, "action_request_validation_exception", item.err.Error())
+	}
+
+	input, err := toCreateLogInput(c.Config.Fields, item, defaults)
+	if err != nil {
+		return fail(http.StatusBadRequest, "document_parsing_exception", err.Error())
+	}
+
+	output, err := c.Usecase.CreateLog(r.Context(), input)
+	if err != nil {
+		failure := usecase.ClassifyCreateError(err)
+		switch failure.Kind {
+		case usecase.CreateErrorRateLimited, usecase.CreateErrorUnavailable:
+			return fail(http.StatusTooManyRequests, "es_rejected_execution_exception", failure.Message)
+		case usecase.CreateErrorDuplicate:
+			return fail(http.StatusConflict, "version_conflict_engine_exception", failure.Message)
+		case usecase.CreateErrorForbidden:
+			return fail(http.StatusForbidden, "security_exception", failure.Message)
+		case usecase.CreateErrorInvalid:
+			return fail(http.StatusBadRequest, "document_parsing_exception", failure.Message)
+		default:
+			return fail(http.StatusInternalServerError, "exception", "An internal error occurred while storing the log.")
+		}
+	}
+
+	status := http.StatusCreated
+	if output.Replayed {
+		status = http.StatusOK
+	}
+	result["_id"] = output.ID.String()
+	result["_version"] = 1
+	result["result"] = "created"
+	result["status"] = status
+	result["_shards"] = map[string]int{"total": 1, "successful": 1, "failed": 0}
+	result["_seq_no"] = 0
+	result["_primary_term"] = 1
+	return result
+}
+
+// productHeader identifies the server as Elasticsearch, which official clients require since 7.14
+func productHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeError uses the Elasticsearch error body so that clients can report the
+// reason, with the request ID alongside as on the other ingestion APIs
+func writeError(w http.ResponseWriter, status int, errorType, reason string) {
+	body := map[string]interface{}{
+		"error":  map[string]interface{}{"type": errorType, "reason": reason},
+		"status": status,
+	}
+	if requestID := httputil.RequestID(w); requestID != "" {
+		body["request_id"] = requestID
+	}
+	httputil.WriteJSON(w, status, body)
+}
@@ -0,0 +1,234 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+	domainLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+)
+
+// Mock usecase for testing
+type mockLogUsecase struct {
+	inputs []dto.CreateLogInput
+	errs   map[int]error // Optional: error returned for the n-th call
+}
+
+func (m *mockLogUsecase) CreateLog(ctx context.Context, input dto.CreateLogInput) (*dto.CreateLogOutput, error) {
+	m.inputs = append(m.inputs, input)
+	if err := m.errs[len(m.inputs)]; err != nil {
+		return nil, err
+	}
+	id := uuid.New()
+	if input.ID != nil {
+		id = *input.ID
+	}
+	return &dto.CreateLogOutput{ID: id}, nil
+}
+
+func (m *mockLogUsecase) ListLogs(ctx context.Context, input dto.ListLogsInput) (*dto.ListLogsOutput, error) {
+	return nil, nil
+}
+
+type bulkResponse struct {
+	Errors bool                                `json:"errors"`
+	Items  []map[string]map[string]interface{} `json:"items"`
+}
+
+func newRouter(controller *ElasticsearchController) http.Handler {
+	r := chi.NewRouter()
+	r.Route("/es", controller.Routes)
+	return r
+}
+
+func doBulk(t *testing.T, handler http.Handler, path, body string) bulkResponse {
+	req := httptest.NewRequest("POST", path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if w.Header().Get("X-Elastic-Product") != "Elasticsearch" {
+		t.Error("Expected X-Elastic-Product header")
+	}
+
+	var response bulkResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	return response
+}
+
+func TestElasticsearchController_Bulk(t *testing.T) {
+	applicationID := uuid.New()
+	userID := uuid.New()
+	clientID := uuid.New()
+	usecase := &mockLogUsecase{}
+	handler := newRouter(NewElasticsearchController(usecase, Config{UserID: userID}))
+
+	body := `{"create":{"_id":"` + clientID.String() + `"}}
+{"@timestamp":"2025-10-28T10:00:00.123Z","message":"disk full","log":{"level":"warning","file":{"path":"/var/log/app.log"}},"host":{"name":"web-01"},"retries":3}
+{"index":{"_index":"logs-other","_id":"filebeat-42"}}
+{"log":"fluent bit line","level":"error","time":1730109600000,"application_id":"` + uuid.NewString() + `"}
+`
+	response := doBulk(t, handler, "/es/"+applicationID.String()+"/_bulk", body)
+
+	if response.Errors {
+		t.Errorf("Expected no errors, got %+v", response.Items)
+	}
+	if len(response.Items) != 2 || response.Items[0]["create"]["status"] != float64(201) {
+		t.Fatalf("Expected 2 created items, got %+v", response.Items)
+	}
+	if response.Items[0]["create"]["_id"] != clientID.String() {
+		t.Errorf("Expected client _id to be used as the log ID, got %v", response.Items[0]["create"]["_id"])
+	}
+
+	first := usecase.inputs[0]
+	if first.ApplicationID != applicationID || first.UserID != userID {
+		t.Errorf("Expected index application and configured user, got %s and %s", first.ApplicationID, first.UserID)
+	}
+	if first.Message != "disk full" || first.Level != "WARN" || first.Source != "web-01" {
+		t.Errorf("Unexpected mapped input: %+v", first)
+	}
+	if first.Timestamp == nil || first.Timestamp.Nanosecond() != 123000000 {
+		t.Errorf("Expected parsed @timestamp, got %v", first.Timestamp)
+	}
+	if first.Metadata["retries"] != int64(3) {
+		t.Errorf("Expected unmapped fields in metadata, got %v", first.Metadata)
+	}
+	if _, ok := first.Metadata["host"]; ok {
+		t.Error("Expected mapped nested fields to be removed from metadata")
+	}
+	logField := first.Metadata["log"].(map[string]interface{})
+	if _, ok := logField["level"]; ok || logField["file"] == nil {
+		t.Errorf("Expected only log.level to be removed, got %v", logField)
+	}
+
+	second := usecase.inputs[1]
+	if second.Message != "fluent bit line" || second.Level != "ERROR" || second.ApplicationID == applicationID {
+		t.Errorf("Expected document application and fields, got %+v", second)
+	}
+	if second.Timestamp == nil || !second.Timestamp.Equal(time.UnixMilli(1730109600000)) {
+		t.Errorf("Expected epoch millis timestamp, got %v", second.Timestamp)
+	}
+	if second.IdempotencyKey != "es:logs-other:filebeat-42" {
+		t.Errorf("Expected idempotency key from non-UUID _id, got %q", second.IdempotencyKey)
+	}
+}
+
+func TestElasticsearchController_BulkItemErrors(t *testing.T) {
+	usecase := &mockLogUsecase{errs: map[int]error{
+		1: &ratelimit.ExceededError{Kind: ratelimit.LimitEntries},
+		2: domainLog.ErrDuplicateLog,
+	}}
+	handler := newRouter(NewElasticsearchController(usecase, Config{ApplicationID: uuid.New(), UserID: uuid.New()}))
+
+	body := `{"index":{}}
+{"message":"throttled"}
+{"create":{}}
+{"message":"duplicate"}
+{"index":{}}
+{"no_message":true}
+{"delete":{"_id":"1"}}
+{"update":{"_id":"1"}}
+{"doc":{"message":"changed"}}
+{"index":{}}
+{"message":"ok"}
+`
+	response := doBulk(t, handler, "/es/_bulk", body)
+
+	if !response.Errors {
+		t.Error("Expected errors to be reported")
+	}
+	expected := []struct {
+		op     string
+		status float64
+	}{
+		{op: "index", status: 429},
+		{op: "create", status: 409},
+		{op: "index", status: 400},
+		{op: "delete", status: 400},
+		{op: "update", status: 400},
+		{op: "index", status: 201},
+	}
+	if len(response.Items) != len(expected) {
+		t.Fatalf("Expected %d items, got %d", len(expected), len(response.Items))
+	}
+	for i, e := range expected {
+		item, ok := response.Items[i][e.op]
+		if !ok || item["status"] != e.status {
+			t.Errorf("Expected item %d to be %s with status %v, got %+v", i, e.op, e.status, response.Items[i])
+		}
+	}
+}
+
+func TestElasticsearchController_BulkMalformed(t *testing.T) {
+	handler := newRouter(NewElasticsearchController(&mockLogUsecase{}, Config{}))
+
+	for _, body := range []string{"not json\n", `{"index":{}}` + "\n", `{"explode":{}}` + "\n{}\n"} {
+		req := httptest.NewRequest("POST", "/es/_bulk", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		w.Header().Set("X-Request-ID", "req-1")
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %q, got %d", http.StatusBadRequest, body, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), `"request_id":"req-1"`) {
+			t.Errorf("Expected request_id in error body for %q, got %s", body, w.Body.String())
+		}
+	}
+}
+
+func TestElasticsearchController_Handshake(t *testing.T) {
+	handler := newRouter(NewElasticsearchController(&mockLogUsecase{}, Config{}))
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{method: "GET", path: "/es/"},
+		{method: "HEAD", path: "/es/"},
+		{method: "GET", path: "/es/_license"},
+		{method: "HEAD", path: "/es/_index_template/filebeat-8.11.0"},
+		{method: "PUT", path: "/es/_ilm/policy/filebeat"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status %d for %s %s, got %d", http.StatusOK, tt.method, tt.path, w.Code)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/es/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var info struct {
+		Version struct {
+			Number string `json:"number"`
+		} `json:"version"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &info)
+	if info.Version.Number != compatibleVersion {
+		t.Errorf("Expected version %s, got %s", compatibleVersion, info.Version.Number)
+	}
+}
@@ -0,0 +1,111 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	appPipeline "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/pipeline"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/spool"
+)
+
+// saturation is the fill ratio at which a queue or spool is reported down,
+// since further writes are about to be rejected
+const saturation = 0.9
+
+// QueueStatsProvider exposes the ingestion pipeline's state
+type QueueStatsProvider interface {
+	Stats() appPipeline.Stats
+}
+
+// SpoolStatsProvider exposes the spooling repository's state
+type SpoolStatsProvider interface {
+	Degraded() bool
+	Stats() spool.Stats
+}
+
+// ClosedReporter is implemented by servers that stop accepting clients on shutdown
+type ClosedReporter interface {
+	Closed() bool
+}
+
+// PingCheck reports a dependency down when ping fails, such as a database round-trip
+func PingCheck(ping func(ctx context.Context) error) Check {
+	return func(ctx context.Context) ComponentStatus {
+		start := time.Now()
+		err := ping(ctx)
+		status := ComponentStatus{
+			Status:  StatusUp,
+			Details: map[string]any{"latency_ms": float64(time.Since(start).Microseconds()) / 1000},
+		}
+		if err != nil {
+			status.Status = StatusDown
+			status.Error = err.Error()
+		}
+		return status
+	}
+}
+
+// DegradeOnFailure reports check degraded rather than down when it fails,
+// for dependencies whose outage another component absorbs, such as the
+// database while failed writes are spooled to disk
+func DegradeOnFailure(check Check, reason string) Check {
+	return func(ctx context.Context) ComponentStatus {
+		status := check(ctx)
+		if status.Status == StatusDown {
+			status.Status = StatusDegraded
+			status.Error = reason + ": " + status.Error
+		}
+		return status
+	}
+}
+
+// QueueCheck reports the ingestion queue down once it is nearly full
+func QueueCheck(queue QueueStatsProvider) Check {
+	return func(ctx context.Context) ComponentStatus {
+		stats := queue.Stats()
+		status := ComponentStatus{
+			Status:  StatusUp,
+			Details: map[string]any{"depth": stats.QueueDepth, "capacity": stats.QueueCapacity},
+		}
+		if saturated(int64(stats.QueueDepth), int64(stats.QueueCapacity)) {
+			status.Status = StatusDown
+			status.Error = "ingestion queue is saturated"
+		}
+		return status
+	}
+}
+
+// SpoolCheck reports the spool degraded while writes are being spooled, and
+// down once it is nearly full
+func SpoolCheck(s SpoolStatsProvider) Check {
+	return func(ctx context.Context) ComponentStatus {
+		stats := s.Stats()
+		status := ComponentStatus{
+			Status:  StatusUp,
+			Details: map[string]any{"segments": stats.Segments, "bytes": stats.Bytes, "max_bytes": stats.MaxBytes},
+		}
+		switch {
+		case stats.MaxBytes > 0 && saturated(stats.Bytes, stats.MaxBytes):
+			status.Status = StatusDown
+			status.Error = "spool is saturated"
+		case s.Degraded():
+			status.Status = StatusDegraded
+			status.Error = "writes are being spooled until the database recovers"
+		}
+		return status
+	}
+}
+
+// ClosedCheck reports a server down once it has been closed
+func ClosedCheck(server ClosedReporter) Check {
+	return func(ctx context.Context) ComponentStatus {
+		if server.Closed() {
+			return ComponentStatus{Status: StatusDown, Error: "server is closed"}
+		}
+		return ComponentStatus{Status: StatusUp}
+	}
+}
+
+func saturated(used, capacity int64) bool {
+	return capacity > 0 && float64(used) >= saturation*float64(capacity)
+}
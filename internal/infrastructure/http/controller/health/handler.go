@@ -0,0 +1,104 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp       = "up"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+
+	defaultCheckTimeout = 2 * time.Second
+)
+
+// ComponentStatus is one component's entry in the readiness breakdown
+type ComponentStatus struct {
+	Status  string         `json:"status"`
+	Error   string         `json:"error,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// Check reports the state of one component; it must return once ctx is done
+type Check func(ctx context.Context) ComponentStatus
+
+// Response is the body of both probes
+type Response struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+}
+
+type HealthController struct {
+	Checks  map[string]Check
+	Timeout time.Duration
+}
+
+// NewHealthController creates a controller whose readiness probe runs the given checks, keyed by component name
+func NewHealthController(checks map[string]Check) *HealthController {
+	return &HealthController{
+		Checks:  checks,
+		Timeout: defaultCheckTimeout,
+	}
+}
+
+// @Summary      Liveness probe
+// @Description  Reports that the process is running and serving requests. It does not check dependencies.
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object} Response
+// @Router       /healthz [get]
+func (c *HealthController) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, http.StatusOK, Response{Status: StatusUp})
+}
+
+// @Summary      Readiness probe
+// @Description  Checks MongoDB, the spool, the ingestion queue and the SSE server. Returns 503 when any component is down; degraded components still report ready.
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object} Response
+// @Failure      503  {object} Response
+// @Router       /readyz [get]
+func (c *HealthController) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), c.Timeout)
+	defer cancel()
+
+	response := Response{Status: StatusUp, Components: make(map[string]ComponentStatus, len(c.Checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range c.Checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := check(ctx)
+			mu.Lock()
+			response.Components[name] = status
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	code := http.StatusOK
+	for _, component := range response.Components {
+		switch component.Status {
+		case StatusDown:
+			response.Status = StatusDown
+			code = http.StatusServiceUnavailable
+		case StatusDegraded:
+			if response.Status == StatusUp {
+				response.Status = StatusDegraded
+			}
+		}
+	}
+	writeResponse(w, code, response)
+}
+
+func writeResponse(w http.ResponseWriter, code int, response Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,176 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appPipeline "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/pipeline"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/spool"
+)
+
+type mockQueue struct {
+	stats appPipeline.Stats
+}
+
+func (m *mockQueue) Stats() appPipeline.Stats {
+	return m.stats
+}
+
+type mockSpool struct {
+	degraded bool
+	stats    spool.Stats
+}
+
+func (m *mockSpool) Degraded() bool {
+	return m.degraded
+}
+
+func (m *mockSpool) Stats() spool.Stats {
+	return m.stats
+}
+
+type mockServer struct {
+	closed bool
+}
+
+func (m *mockServer) Closed() bool {
+	return m.closed
+}
+
+func serveReadiness(t *testing.T, c *HealthController) (int, Response) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	c.ReadinessHandler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var response Response
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return rec.Code, response
+}
+
+func TestLivenessHandler(t *testing.T) {
+	c := NewHealthController(map[string]Check{
+		"mongodb": PingCheck(func(ctx context.Context) error { return errors.New("unreachable") }),
+	})
+	rec := httptest.NewRecorder()
+	c.LivenessHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 regardless of dependencies, got %d", rec.Code)
+	}
+	if rec.Body.String() != `{"status":"up"}`+"\n" {
+		t.Errorf("Expected an up status, got %s", rec.Body.String())
+	}
+}
+
+func TestReadinessHandler_Up(t *testing.T) {
+	c := NewHealthController(map[string]Check{
+		"mongodb": PingCheck(func(ctx context.Context) error { return nil }),
+		"queue":   QueueCheck(&mockQueue{stats: appPipeline.Stats{QueueDepth: 10, QueueCapacity: 100}}),
+		"spool":   SpoolCheck(&mockSpool{stats: spool.Stats{Bytes: 10, MaxBytes: 100}}),
+		"sse":     ClosedCheck(&mockServer{}),
+	})
+
+	code, response := serveReadiness(t, c)
+	if code != http.StatusOK || response.Status != StatusUp {
+		t.Errorf("Expected 200 and up, got %d and %s", code, response.Status)
+	}
+	if len(response.Components) != 4 {
+		t.Fatalf("Expected 4 components, got %v", response.Components)
+	}
+	if _, ok := response.Components["mongodb"].Details["latency_ms"]; !ok {
+		t.Errorf("Expected the ping latency to be reported, got %v", response.Components["mongodb"])
+	}
+	if depth := response.Components["queue"].Details["depth"]; depth != float64(10) {
+		t.Errorf("Expected a queue depth of 10, got %v", depth)
+	}
+}
+
+func TestReadinessHandler_Degraded(t *testing.T) {
+	c := NewHealthController(map[string]Check{
+		"mongodb": PingCheck(func(ctx context.Context) error { return nil }),
+		"spool":   SpoolCheck(&mockSpool{degraded: true, stats: spool.Stats{Bytes: 10}}),
+	})
+
+	code, response := serveReadiness(t, c)
+	if code != http.StatusOK || response.Status != StatusDegraded {
+		t.Errorf("Expected 200 and degraded, got %d and %s", code, response.Status)
+	}
+	if response.Components["spool"].Status != StatusDegraded {
+		t.Errorf("Expected the spool to be degraded, got %v", response.Components["spool"])
+	}
+}
+
+func TestReadinessHandler_PingDegradedWhenSpooled(t *testing.T) {
+	ping := PingCheck(func(ctx context.Context) error { return errors.New("connection refused") })
+	c := NewHealthController(map[string]Check{
+		"mongodb": DegradeOnFailure(ping, "writes are being spooled"),
+		"spool":   SpoolCheck(&mockSpool{degraded: true, stats: spool.Stats{Bytes: 10, MaxBytes: 1000}}),
+	})
+
+	code, response := serveReadiness(t, c)
+	if code != http.StatusOK || response.Status != StatusDegraded {
+		t.Errorf("Expected 200 and degraded, got %d and %s", code, response.Status)
+	}
+	if component := response.Components["mongodb"]; component.Status != StatusDegraded || component.Error == "" {
+		t.Errorf("Expected mongodb to be degraded with an error, got %v", component)
+	}
+}
+
+func TestReadinessHandler_Down(t *testing.T) {
+	tests := []struct {
+		name      string
+		component string
+		check     Check
+	}{
+		{name: "Ping fails", component: "mongodb", check: PingCheck(func(ctx context.Context) error { return errors.New("connection refused") })},
+		{name: "Queue saturated", component: "queue", check: QueueCheck(&mockQueue{stats: appPipeline.Stats{QueueDepth: 95, QueueCapacity: 100}})},
+		{name: "Spool saturated", component: "spool", check: SpoolCheck(&mockSpool{degraded: true, stats: spool.Stats{Bytes: 950, MaxBytes: 1000}})},
+		{name: "SSE server closed", component: "sse", check: ClosedCheck(&mockServer{closed: true})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewHealthController(map[string]Check{
+				tt.component: tt.check,
+				"other":      ClosedCheck(&mockServer{}),
+			})
+
+			code, response := serveReadiness(t, c)
+			if code != http.StatusServiceUnavailable || response.Status != StatusDown {
+				t.Errorf("Expected 503 and down, got %d and %s", code, response.Status)
+			}
+			if component := response.Components[tt.component]; component.Status != StatusDown || component.Error == "" {
+				t.Errorf("Expected %s to be down with an error, got %v", tt.component, component)
+			}
+			if response.Components["other"].Status != StatusUp {
+				t.Errorf("Expected other components to stay up, got %v", response.Components["other"])
+			}
+		})
+	}
+}
+
+func TestReadinessHandler_Timeout(t *testing.T) {
+	c := NewHealthController(map[string]Check{
+		"mongodb": PingCheck(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	})
+	c.Timeout = 20 * time.Millisecond
+
+	start := time.Now()
+	code, _ := serveReadiness(t, c)
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 when a check times out, got %d", code)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the probe to return after the timeout, took %v", elapsed)
+	}
+}
@@ -2,12 +2,40 @@ package log
 
 import (
 	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
 	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/httputil"
 )
 
+const (
+	// maxBatchEntries bounds the logs of one batch request
+	maxBatchEntries = 1000
+	maxBatchBytes   = 16 << 20
+)
+
+// BatchOutput reports the outcome of a batch request
+type BatchOutput struct {
+	Accepted int          `json:"accepted"`
+	Rejected int          `json:"rejected"`
+	Errors   []BatchError `json:"errors,omitempty"`
+}
+
+// BatchError describes a rejected log by its position in the request
+type BatchError struct {
+	Index  int    `json:"index"`
+	Status int    `json:"status"` // The status the log would have received from POST /logs
+	Error  string `json:"error"`
+}
+
 type LogController struct {
 	Usecase usecase.LogUsecaseInterface
 }
@@ -24,28 +52,201 @@ func NewLogController(uc usecase.LogUsecaseInterface) *LogController {
 // @Accept       json
 // @Produce      json
 // @Param        log  body  dto.CreateLogInput  true  "Log creation data including ApplicationID and UserID."
+// @Param        Idempotency-Key  header  string  false  "Client key that makes retries of this request return the original log."
+// @Success      200  {object} dto.CreateLogOutput "Repeated request: the original log is returned and not stored again."
 // @Success      201  {object} dto.CreateLogOutput
+// @Success      202  {object} dto.CreateLogOutput "Accepted for asynchronous persistence (pipeline mode)."
 // @Failure      400  {string} string "Invalid request body format, or missing/invalid ApplicationID/UserID."
+// @Failure      403  {string} string "The TLS client certificate is bound to another application."
+// @Failure      409  {string} string "A log with the client-supplied ID already exists."
+// @Failure      429  {string} string "The application exceeded its ingestion rate limit or daily quota."
 // @Failure      500  {string} string "An internal error occurred while processing the log."
+// @Failure      503  {string} string "The ingestion queue is full or shutting down."
 // @Router       /logs [post]
 func (c *LogController) CreateLogHandler(w http.ResponseWriter, r *http.Request) {
 	var input dto.CreateLogInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid request body format."})
+		httputil.WriteError(w, http.StatusBadRequest, "Invalid request body format.")
 		return
 	}
+	input.IdempotencyKey = r.Header.Get("Idempotency-Key")
 
 	output, err := c.Usecase.CreateLog(r.Context(), input)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "An internal error occurred while creating the log."})
+		createErr := usecase.ClassifyCreateError(err)
+		if createErr.Kind == usecase.CreateErrorInternal {
+			slog.ErrorContext(r.Context(), "Failed to create log", "error", err)
+		}
+		if createErr.Exceeded != nil {
+			writeRateLimitHeaders(w, createErr.Exceeded)
+		}
+		httputil.WriteCreateError(w, createErr)
+		return
+	}
+
+	status := http.StatusCreated
+	switch {
+	case output.Replayed:
+		status = http.StatusOK
+		w.Header().Set("Idempotent-Replayed", "true")
+	case output.Queued:
+		status = http.StatusAccepted
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(output)
+}
+
+// @Summary      Create log entries in a batch
+// @Description  Creates up to 1000 log entries in one request. Each entry is validated, rate limited and stored on its own; rejected entries are reported with the status they would have received from POST /logs, so clients retry only those answered with 429 or 503. Entries with a client-generated id are never stored twice.
+// @Tags         Logs
+// @Accept       json
+// @Produce      json
+// @Param        logs  body  []dto.CreateLogInput  true  "Log entries"
+// @Param        Idempotency-Key  header  string  false  "Client key that makes retries of this request store each entry once."
+// @Success      200  {object} BatchOutput
+// @Failure      400  {string} string "Invalid request body format, or an empty or oversized batch."
+// @Router       /logs/batch [post]
+func (c *LogController) CreateLogsBatchHandler(w http.ResponseWriter, r *http.Request) {
+	var inputs []dto.CreateLogInput
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBytes)).Decode(&inputs); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Invalid request body format.")
+		return
+	}
+	if len(inputs) == 0 || len(inputs) > maxBatchEntries {
+		httputil.WriteError(w, http.StatusBadRequest, "A batch must contain between 1 and "+strconv.Itoa(maxBatchEntries)+" logs.")
+		return
+	}
+
+	key := r.Header.Get("Idempotency-Key")
+	output := BatchOutput{}
+	var retryAfter int64
+	for i, input := range inputs {
+		// Retried requests repeat the same entries, so each entry gets its own key
+		if key != "" {
+			input.IdempotencyKey = key + ":" + strconv.Itoa(i)
+		}
+
+		_, err := c.Usecase.CreateLog(r.Context(), input)
+		if err == nil {
+			output.Accepted++
+			continue
+		}
+
+		createErr := usecase.ClassifyCreateError(err)
+		retryAfter = max(retryAfter, createErr.RetryAfterSeconds())
+		output.Rejected++
+		output.Errors = append(output.Errors, BatchError{Index: i, Status: httputil.CreateErrorStatus(createErr.Kind), Error: createErr.Message})
+	}
+
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// @Summary      List historical logs
+// @Description  Searches an application's logs. Time bounds and ordering apply to either the client event time (timestamp) or the ingestion time (ingested_at).
+// @Tags         Logs
+// @Produce      json
+// @Param        application_id  query  string  true   "Application ID"
+// @Param        from            query  string  false  "Inclusive lower bound (RFC3339)"
+// @Param        to              query  string  false  "Exclusive upper bound (RFC3339)"
+// @Param        level           query  string  false  "Comma-separated levels to include"
+// @Param        min_level       query  string  false  "Minimum severity to include"
+// @Param        source          query  string  false  "Exact source"
+// @Param        tag             query  string  false  "Tag filter as key:value, repeatable"
+// @Param        q               query  string  false  "Case-insensitive text to search in the message"
+// @Param        time_field      query  string  false  "timestamp (default) or ingested_at"
+// @Param        order           query  string  false  "desc (default) or asc"
+// @Param        limit           query  int     false  "Page size, up to 1000 (default 100)"
+// @Param        offset          query  int     false  "Number of logs to skip"
+// @Success      200  {object} dto.ListLogsOutput
+// @Failure      400  {string} string "Invalid query parameters."
+// @Failure      500  {string} string "An internal error occurred while listing logs."
+// @Router       /logs [get]
+func (c *LogController) ListLogsHandler(w http.ResponseWriter, r *http.Request) {
+	input, err := parseListLogsInput(r)
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	output, err := c.Usecase.ListLogs(r.Context(), input)
+	if errors.Is(err, usecase.ErrInvalidQuery) {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to list logs", "error", err)
+		httputil.WriteError(w, http.StatusInternalServerError, "An internal error occurred while listing logs.")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(output)
 }
+
+func parseListLogsInput(r *http.Request) (dto.ListLogsInput, error) {
+	q := r.URL.Query()
+	var input dto.ListLogsInput
+
+	applicationID, err := uuid.Parse(q.Get("application_id"))
+	if err != nil {
+		return input, errors.New("application_id must be a valid UUID")
+	}
+	input.ApplicationID = applicationID
+
+	for param, target := range map[string]**time.Time{"from": &input.From, "to": &input.To} {
+		if raw := q.Get(param); raw != "" {
+			parsed, err := time.Parse(time.RFC3339Nano, raw)
+			if err != nil {
+				return input, errors.New(param + " must be an RFC3339 timestamp")
+			}
+			*target = &parsed
+		}
+	}
+
+	for param, target := range map[string]*int{"limit": &input.Limit, "offset": &input.Offset} {
+		if raw := q.Get(param); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil {
+				return input, errors.New(param + " must be an integer")
+			}
+			*target = parsed
+		}
+	}
+
+	if raw := q.Get("level"); raw != "" {
+		input.Levels = strings.Split(raw, ",")
+	}
+	for _, raw := range q["tag"] {
+		key, value, ok := strings.Cut(raw, ":")
+		if !ok {
+			return input, errors.New("tag must be formatted as key:value")
+		}
+		if input.Tags == nil {
+			input.Tags = make(map[string]string)
+		}
+		input.Tags[key] = value
+	}
+
+	input.MinLevel = q.Get("min_level")
+	input.Source = q.Get("source")
+	input.Search = q.Get("q")
+	input.TimeField = q.Get("time_field")
+	input.Order = q.Get("order")
+	return input, nil
+}
+
+// writeRateLimitHeaders sets the standard rate limit headers of a 429 response
+func writeRateLimitHeaders(w http.ResponseWriter, e *ratelimit.ExceededError) {
+	w.Header().Set("X-RateLimit-Limit", strconv.FormatInt(e.Limit, 10))
+	w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(e.Remaining, 10))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(e.Reset.Unix(), 10))
+	w.Header().Set("X-RateLimit-Scope", e.Kind)
+}
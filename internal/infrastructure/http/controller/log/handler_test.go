@@ -5,21 +5,53 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	logUsecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
 	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/pipeline"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+	domainLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
 )
 
 // Mock usecase for testing
 type mockLogUsecase struct {
 	createLogError  bool
+	createLogErr    error
+	createLogErrFor func(input dto.CreateLogInput) error
 	createLogOutput *dto.CreateLogOutput
+	lastInput       dto.CreateLogInput
+	inputs          []dto.CreateLogInput
+	listLogsErr     error
+	listLogsOutput  *dto.ListLogsOutput
+	lastListInput   dto.ListLogsInput
+}
+
+func (m *mockLogUsecase) ListLogs(ctx context.Context, input dto.ListLogsInput) (*dto.ListLogsOutput, error) {
+	m.lastListInput = input
+	if m.listLogsErr != nil {
+		return nil, m.listLogsErr
+	}
+	return m.listLogsOutput, nil
 }
 
 func (m *mockLogUsecase) CreateLog(ctx context.Context, input dto.CreateLogInput) (*dto.CreateLogOutput, error) {
+	m.lastInput = input
+	m.inputs = append(m.inputs, input)
+	if m.createLogErrFor != nil {
+		if err := m.createLogErrFor(input); err != nil {
+			return nil, err
+		}
+	}
+	if m.createLogErr != nil {
+		return nil, m.createLogErr
+	}
 	if m.createLogError {
 		return nil, errors.New("usecase error")
 	}
@@ -301,3 +333,322 @@ func TestLogController_CreateLogHandler_DifferentLogLevels(t *testing.T) {
 		})
 	}
 }
+
+func TestLogController_CreateLogHandler_RateLimited(t *testing.T) {
+	reset := time.Now().Add(2 * time.Second)
+	usecase := &mockLogUsecase{
+		createLogErr: &ratelimit.ExceededError{
+			ApplicationID: uuid.NewString(),
+			Kind:          ratelimit.LimitEntries,
+			Limit:         10,
+			Remaining:     0,
+			RetryAfter:    1500 * time.Millisecond,
+			Reset:         reset,
+		},
+	}
+	controller := NewLogController(usecase)
+
+	input := dto.CreateLogInput{
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+		Message:       "Test log message",
+		Level:         "INFO",
+	}
+
+	jsonBody, err := json.Marshal(input)
+	if err != nil {
+		t.Fatalf("Failed to marshal request body: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/api/v1/logs", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	controller.CreateLogHandler(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+
+	expectedHeaders := map[string]string{
+		"Retry-After":           "2",
+		"X-RateLimit-Limit":     "10",
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Scope":     ratelimit.LimitEntries,
+	}
+	for header, expected := range expectedHeaders {
+		if actual := w.Header().Get(header); actual != expected {
+			t.Errorf("Expected header %s '%s', got '%s'", header, expected, actual)
+		}
+	}
+	if w.Header().Get("X-RateLimit-Reset") == "" {
+		t.Error("Expected X-RateLimit-Reset header to be set")
+	}
+}
+
+func TestLogController_CreateLogHandler_Queued(t *testing.T) {
+	usecase := &mockLogUsecase{
+		createLogOutput: &dto.CreateLogOutput{
+			ID:        uuid.New(),
+			Message:   "Test log message",
+			Level:     "INFO",
+			Timestamp: "2023-10-28T10:30:00Z",
+			Queued:    true,
+		},
+	}
+	controller := NewLogController(usecase)
+
+	req := httptest.NewRequest("POST", "/api/v1/logs", bytes.NewBufferString(`{"message": "Test log message", "level": "INFO"}`))
+	w := httptest.NewRecorder()
+
+	controller.CreateLogHandler(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status %d, got %d", http.StatusAccepted, w.Code)
+	}
+
+	var response dto.CreateLogOutput
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Errorf("Failed to unmarshal response: %v", err)
+	}
+	if response.ID != usecase.createLogOutput.ID {
+		t.Errorf("Expected assigned ID %s, got %s", usecase.createLogOutput.ID, response.ID)
+	}
+}
+
+func TestLogController_CreateLogHandler_QueueUnavailable(t *testing.T) {
+	for _, queueErr := range []error{pipeline.ErrQueueFull, pipeline.ErrClosed} {
+		t.Run(queueErr.Error(), func(t *testing.T) {
+			controller := NewLogController(&mockLogUsecase{createLogErr: queueErr})
+
+			req := httptest.NewRequest("POST", "/api/v1/logs", bytes.NewBufferString(`{"message": "Test log message", "level": "INFO"}`))
+			w := httptest.NewRecorder()
+
+			controller.CreateLogHandler(w, req)
+
+			if w.Code != http.StatusServiceUnavailable {
+				t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+			}
+			if w.Header().Get("Retry-After") == "" {
+				t.Error("Expected Retry-After header to be set")
+			}
+		})
+	}
+}
+
+func TestLogController_CreateLogHandler_IdempotentReplay(t *testing.T) {
+	usecase := &mockLogUsecase{
+		createLogOutput: &dto.CreateLogOutput{
+			ID:        uuid.New(),
+			Message:   "Test log message",
+			Level:     "INFO",
+			Timestamp: "2023-10-28T10:30:00Z",
+			Replayed:  true,
+		},
+	}
+	controller := NewLogController(usecase)
+
+	req := httptest.NewRequest("POST", "/api/v1/logs", bytes.NewBufferString(`{"message": "Test log message", "level": "INFO"}`))
+	req.Header.Set("Idempotency-Key", "retry-123")
+	w := httptest.NewRecorder()
+
+	controller.CreateLogHandler(w, req)
+
+	if usecase.lastInput.IdempotencyKey != "retry-123" {
+		t.Errorf("Expected idempotency key 'retry-123' to be passed to the usecase, got '%s'", usecase.lastInput.IdempotencyKey)
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("Expected Idempotent-Replayed header to be set")
+	}
+}
+
+func TestLogController_CreateLogHandler_DuplicateID(t *testing.T) {
+	controller := NewLogController(&mockLogUsecase{createLogErr: domainLog.ErrDuplicateLog})
+
+	req := httptest.NewRequest("POST", "/api/v1/logs", bytes.NewBufferString(`{"message": "Test log message", "level": "INFO"}`))
+	w := httptest.NewRecorder()
+
+	controller.CreateLogHandler(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestLogController_ListLogsHandler_Success(t *testing.T) {
+	applicationID := uuid.New()
+	usecase := &mockLogUsecase{
+		listLogsOutput: &dto.ListLogsOutput{
+			Logs:      []dto.LogOutput{{ID: uuid.New(), ApplicationID: applicationID, Message: "Stored", Level: "ERROR"}},
+			TimeField: "ingested_at",
+			Order:     "asc",
+			Limit:     50,
+		},
+	}
+	controller := NewLogController(usecase)
+
+	url := "/api/v1/logs?application_id=" + applicationID.String() +
+		"&from=2025-10-28T10:00:00.123456789Z&to=2025-10-29T10:00:00Z&level=error,fatal&tag=env:prod&tag=region:eu" +
+		"&time_field=ingested_at&order=asc&limit=50&offset=10&source=api&q=timeout"
+	req := httptest.NewRequest("GET", url, nil)
+	w := httptest.NewRecorder()
+
+	controller.ListLogsHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	input := usecase.lastListInput
+	if input.ApplicationID != applicationID {
+		t.Errorf("Expected application ID %s, got %s", applicationID, input.ApplicationID)
+	}
+	if input.From == nil || input.From.Nanosecond() != 123456789 {
+		t.Errorf("Expected from with nanoseconds, got %v", input.From)
+	}
+	if input.To == nil || len(input.Levels) != 2 || input.Tags["env"] != "prod" || input.Tags["region"] != "eu" {
+		t.Errorf("Unexpected parsed input: %+v", input)
+	}
+	if input.TimeField != "ingested_at" || input.Order != "asc" || input.Limit != 50 || input.Offset != 10 {
+		t.Errorf("Unexpected ordering or pagination: %+v", input)
+	}
+	if input.Source != "api" || input.Search != "timeout" {
+		t.Errorf("Unexpected source or search: %+v", input)
+	}
+
+	var response dto.ListLogsOutput
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(response.Logs) != 1 {
+		t.Errorf("Expected 1 log, got %d", len(response.Logs))
+	}
+}
+
+func TestLogController_ListLogsHandler_BadRequest(t *testing.T) {
+	applicationID := uuid.NewString()
+
+	tests := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{name: "Missing application ID", query: ""},
+		{name: "Invalid from", query: "application_id=" + applicationID + "&from=yesterday"},
+		{name: "Invalid limit", query: "application_id=" + applicationID + "&limit=many"},
+		{name: "Invalid tag", query: "application_id=" + applicationID + "&tag=novalue"},
+		{name: "Usecase validation", query: "application_id=" + applicationID, err: logUsecase.ErrInvalidQuery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := NewLogController(&mockLogUsecase{listLogsErr: tt.err})
+			req := httptest.NewRequest("GET", "/api/v1/logs?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			controller.ListLogsHandler(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
+
+func TestLogController_CreateLogHandler_TimestampOutOfRange(t *testing.T) {
+	controller := NewLogController(&mockLogUsecase{createLogErr: domainLog.ErrTimestampOutOfRange})
+
+	req := httptest.NewRequest("POST", "/api/v1/logs", bytes.NewBufferString(`{"message": "Test log message", "level": "INFO", "timestamp": "2001-01-01T00:00:00.000000001Z"}`))
+	w := httptest.NewRecorder()
+
+	controller.CreateLogHandler(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestLogController_CreateLogsBatchHandler(t *testing.T) {
+	usecase := &mockLogUsecase{
+		createLogOutput: &dto.CreateLogOutput{ID: uuid.New()},
+		createLogErrFor: func(input dto.CreateLogInput) error {
+			switch input.Message {
+			case "invalid":
+				return fmt.Errorf("%w: message is required", logUsecase.ErrInvalidLog)
+			case "duplicate":
+				return domainLog.ErrDuplicateLog
+			case "limited":
+				return &ratelimit.ExceededError{Kind: ratelimit.LimitEntries, RetryAfter: 1500 * time.Millisecond}
+			}
+			return nil
+		},
+	}
+	controller := NewLogController(usecase)
+
+	req := httptest.NewRequest("POST", "/api/v1/logs/batch", bytes.NewBufferString(`[
+		{"message": "first", "level": "INFO"},
+		{"message": "invalid", "level": "INFO"},
+		{"message": "duplicate", "level": "INFO"},
+		{"message": "limited", "level": "INFO"},
+		{"message": "last", "level": "INFO"}
+	]`))
+	req.Header.Set("Idempotency-Key", "batch-1")
+	w := httptest.NewRecorder()
+	controller.CreateLogsBatchHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("Retry-After") != "2" {
+		t.Errorf("Expected Retry-After 2, got %q", w.Header().Get("Retry-After"))
+	}
+
+	var output BatchOutput
+	if err := json.Unmarshal(w.Body.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if output.Accepted != 2 || output.Rejected != 3 {
+		t.Errorf("Unexpected output: %+v", output)
+	}
+	expected := []BatchError{
+		{Index: 1, Status: http.StatusBadRequest},
+		{Index: 2, Status: http.StatusConflict},
+		{Index: 3, Status: http.StatusTooManyRequests},
+	}
+	for i, e := range expected {
+		if i >= len(output.Errors) || output.Errors[i].Index != e.Index || output.Errors[i].Status != e.Status {
+			t.Errorf("Expected error %+v, got %+v", e, output.Errors)
+		}
+	}
+	if usecase.inputs[4].IdempotencyKey != "batch-1:4" {
+		t.Errorf("Expected per-entry idempotency key, got %q", usecase.inputs[4].IdempotencyKey)
+	}
+}
+
+func TestLogController_CreateLogsBatchHandler_InvalidBatch(t *testing.T) {
+	controller := NewLogController(&mockLogUsecase{})
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "Invalid JSON", body: `[{`},
+		{name: "Single object", body: `{"message": "not a batch"}`},
+		{name: "Empty batch", body: `[]`},
+		{name: "Too many entries", body: "[" + strings.Repeat(`{},`, maxBatchEntries) + "{}]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			controller.CreateLogsBatchHandler(w, httptest.NewRequest("POST", "/api/v1/logs/batch", bytes.NewBufferString(tt.body)))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
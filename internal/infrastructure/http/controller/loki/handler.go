@@ -0,0 +1,226 @@
+package loki
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/google/uuid"
+	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/httputil"
+)
+
+const (
+	DefaultApplicationLabel = "application_id"
+
+	maxBodyBytes = 16 << 20
+)
+
+var (
+	// levelLabels are checked in order before falling back to the line itself
+	levelLabels = []string{"level", "detected_level", "severity", "lvl"}
+
+	// levelField matches level=error, "level":"error" and severity: warn in logfmt and JSON lines
+	levelField = regexp.MustCompile(`(?i)\b(?:level|lvl|severity)"?\s*[=:]\s*"?([a-z]+)`)
+	// levelWord matches a bare upper-case level such as "[ERROR]" or "WARN:"
+	levelWord = regexp.MustCompile(`\b(TRACE|DEBUG|INFO|NOTICE|WARN|WARNING|ERROR|ERR|FATAL|CRITICAL|PANIC)\b`)
+)
+
+// Config controls how pushed streams are mapped to applications
+type Config struct {
+	// ApplicationLabel names the stream label holding the application ID
+	ApplicationLabel string
+	// ApplicationID is used for streams without the label and requests without
+	// a UUID in X-Scope-OrgID
+	ApplicationID uuid.UUID
+	// UserID owns pushed logs unless the X-User-ID header is sent
+	UserID uuid.UUID
+}
+
+// LokiController implements the Loki push API so that Promtail and Grafana
+// Agent can ship logs to this service unchanged
+type LokiController struct {
+	Usecase usecase.LogUsecaseInterface
+	Config  Config
+}
+
+func NewLokiController(uc usecase.LogUsecaseInterface, cfg Config) *LokiController {
+	if cfg.ApplicationLabel == "" {
+		cfg.ApplicationLabel = DefaultApplicationLabel
+	}
+	return &LokiController{
+		Usecase: uc,
+		Config:  cfg,
+	}
+}
+
+// @Summary      Push logs in the Loki format
+// @Description  Accepts Loki push requests as snappy-compressed protobuf or JSON. Stream labels become tags; the application is read from the configured label, a UUID X-Scope-OrgID, or the server default.
+// @Tags         Loki
+// @Accept       application/x-protobuf
+// @Accept       json
+// @Param        X-Scope-OrgID  header  string  false  "Tenant ID, used as the application ID when it is a UUID."
+// @Param        X-User-ID      header  string  false  "User that owns the pushed logs."
+// @Success      204  "All entries were accepted."
+// @Failure      400  {string} string "Malformed request, or entries without an application or with invalid data."
+// @Failure      413  {string} string "The request body is too large, compressed or decompressed."
+// @Failure      415  {string} string "Unsupported content type."
+// @Failure      429  {string} string "The application exceeded its ingestion rate limit or daily quota."
+// @Failure      503  {string} string "The ingestion queue is full or shutting down."
+// @Router       /loki/api/v1/push [post]
+func (c *LokiController) PushHandler(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	body, err := httputil.ReadBody(w, r, maxBodyBytes)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.Is(err, httputil.ErrBodyTooLarge) || errors.As(err, &tooLarge) {
+			httputil.WriteError(w, http.StatusRequestEntityTooLarge, "The request body is too large.")
+			return
+		}
+		httputil.WriteError(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+
+	var streams []Stream
+	switch mediaType {
+	case "application/json":
+		streams, err = decodeJSON(body)
+	case "application/x-protobuf", "":
+		streams, err = decodeProtobuf(body)
+	default:
+		httputil.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/x-protobuf or application/json.")
+		return
+	}
+	if errors.Is(err, ErrPushTooLarge) {
+		httputil.WriteError(w, http.StatusRequestEntityTooLarge, "The decompressed request body is too large.")
+		return
+	}
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defaultApplicationID := c.Config.ApplicationID
+	if tenant, err := uuid.Parse(r.Header.Get("X-Scope-OrgID")); err == nil {
+		defaultApplicationID = tenant
+	}
+	userID := c.Config.UserID
+	if headerUser, err := uuid.Parse(r.Header.Get("X-User-ID")); err == nil {
+		userID = headerUser
+	}
+
+	// Shippers retry whole batches, so each entry gets an idempotency key
+	// derived from the request body and is stored only once
+	digest := sha256.Sum256(body)
+	batchKey := "loki:" + hex.EncodeToString(digest[:16])
+
+	rejected := 0
+	index := 0
+	for _, stream := range streams {
+		for _, entry := range stream.Entries {
+			index++
+			input, ok := c.toCreateLogInput(stream, entry, defaultApplicationID, userID)
+			if !ok {
+				rejected++
+				continue
+			}
+			input.IdempotencyKey = batchKey + ":" + strconv.Itoa(index)
+
+			_, err := c.Usecase.CreateLog(r.Context(), input)
+			if err == nil {
+				continue
+			}
+			failure := usecase.ClassifyCreateError(err)
+			switch failure.Kind {
+			case usecase.CreateErrorDuplicate:
+			case usecase.CreateErrorInvalid, usecase.CreateErrorForbidden:
+				rejected++
+			case usecase.CreateErrorRateLimited, usecase.CreateErrorUnavailable:
+				httputil.WriteCreateError(w, failure)
+				return
+			default:
+				httputil.WriteError(w, http.StatusInternalServerError, "An internal error occurred while storing the logs.")
+				return
+			}
+		}
+	}
+
+	// Loki answers 400 for rejected entries, which shippers drop instead of retrying
+	if rejected > 0 {
+		httputil.WriteError(w, http.StatusBadRequest, strconv.Itoa(rejected)+" entries were rejected for missing application IDs or invalid data.")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// toCreateLogInput maps labels and structured metadata to tags, the
+// service_name or job label to Source, and the level label or line to Level
+func (c *LokiController) toCreateLogInput(stream Stream, entry Entry, applicationID, userID uuid.UUID) (dto.CreateLogInput, bool) {
+	if raw, ok := stream.Labels[c.Config.ApplicationLabel]; ok {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			return dto.CreateLogInput{}, false
+		}
+		applicationID = parsed
+	}
+	if applicationID == uuid.Nil {
+		return dto.CreateLogInput{}, false
+	}
+
+	input := dto.CreateLogInput{
+		ApplicationID: applicationID,
+		UserID:        userID,
+		Message:       entry.Line,
+		Level:         detectLevel(stream.Labels, entry).String(),
+		Tags:          make(map[string]string, len(stream.Labels)+len(entry.StructuredMetadata)),
+	}
+	for name, value := range stream.Labels {
+		if name != c.Config.ApplicationLabel {
+			input.Tags[name] = value
+		}
+	}
+	for name, value := range entry.StructuredMetadata {
+		input.Tags[name] = value
+	}
+
+	input.Source = stream.Labels["service_name"]
+	if input.Source == "" {
+		input.Source = stream.Labels["job"]
+	}
+	if !entry.Timestamp.IsZero() {
+		timestamp := entry.Timestamp
+		input.Timestamp = &timestamp
+	}
+
+	return input, true
+}
+
+func detectLevel(labels map[string]string, entry Entry) valueobjects.LogLevel {
+	for _, name := range levelLabels {
+		if level, ok := valueobjects.ParseLogLevelAlias(entry.StructuredMetadata[name]); ok {
+			return level
+		}
+		if level, ok := valueobjects.ParseLogLevelAlias(labels[name]); ok {
+			return level
+		}
+	}
+
+	if match := levelField.FindStringSubmatch(entry.Line); match != nil {
+		if level, ok := valueobjects.ParseLogLevelAlias(match[1]); ok {
+			return level
+		}
+	}
+	if match := levelWord.FindString(entry.Line); match != "" {
+		if level, ok := valueobjects.ParseLogLevelAlias(match); ok {
+			return level
+		}
+	}
+	return valueobjects.LogLevelInfo
+}
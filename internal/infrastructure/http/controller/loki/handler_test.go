@@ -0,0 +1,234 @@
+package loki
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Mock usecase for testing
+type mockLogUsecase struct {
+	inputs       []dto.CreateLogInput
+	createLogErr error
+}
+
+func (m *mockLogUsecase) CreateLog(ctx context.Context, input dto.CreateLogInput) (*dto.CreateLogOutput, error) {
+	m.inputs = append(m.inputs, input)
+	if m.createLogErr != nil {
+		return nil, m.createLogErr
+	}
+	return &dto.CreateLogOutput{}, nil
+}
+
+func (m *mockLogUsecase) ListLogs(ctx context.Context, input dto.ListLogsInput) (*dto.ListLogsOutput, error) {
+	return nil, nil
+}
+
+// encodePush builds a snappy-compressed PushRequest with one stream
+func encodePush(labels string, timestamp time.Time, line string, metadata map[string]string) []byte {
+	var ts []byte
+	ts = protowire.AppendTag(ts, 1, protowire.VarintType)
+	ts = protowire.AppendVarint(ts, uint64(timestamp.Unix()))
+	ts = protowire.AppendTag(ts, 2, protowire.VarintType)
+	ts = protowire.AppendVarint(ts, uint64(timestamp.Nanosecond()))
+
+	var entry []byte
+	entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+	entry = protowire.AppendBytes(entry, ts)
+	entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+	entry = protowire.AppendString(entry, line)
+	for name, value := range metadata {
+		var pair []byte
+		pair = protowire.AppendTag(pair, 1, protowire.BytesType)
+		pair = protowire.AppendString(pair, name)
+		pair = protowire.AppendTag(pair, 2, protowire.BytesType)
+		pair = protowire.AppendString(pair, value)
+		entry = protowire.AppendTag(entry, 3, protowire.BytesType)
+		entry = protowire.AppendBytes(entry, pair)
+	}
+
+	var stream []byte
+	stream = protowire.AppendTag(stream, 1, protowire.BytesType)
+	stream = protowire.AppendString(stream, labels)
+	stream = protowire.AppendTag(stream, 2, protowire.BytesType)
+	stream = protowire.AppendBytes(stream, entry)
+	stream = protowire.AppendTag(stream, 3, protowire.VarintType)
+	stream = protowire.AppendVarint(stream, 12345)
+
+	var request []byte
+	request = protowire.AppendTag(request, 1, protowire.BytesType)
+	request = protowire.AppendBytes(request, stream)
+	return snappy.Encode(nil, request)
+}
+
+func TestLokiController_PushHandler_Protobuf(t *testing.T) {
+	applicationID := uuid.New()
+	userID := uuid.New()
+	usecase := &mockLogUsecase{}
+	controller := NewLokiController(usecase, Config{UserID: userID})
+
+	timestamp := time.Date(2025, 10, 28, 10, 0, 0, 123456789, time.UTC)
+	labels := `{application_id="` + applicationID.String() + `", job="varlogs", filename="/var/log/app \"main\".log"}`
+	body := encodePush(labels, timestamp, `ts=2025-10-28T10:00:00Z level=warn msg="disk almost full"`, map[string]string{"trace_id": "abc123"})
+
+	req := httptest.NewRequest("POST", "/loki/api/v1/push", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	w := httptest.NewRecorder()
+
+	controller.PushHandler(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if len(usecase.inputs) != 1 {
+		t.Fatalf("Expected 1 log, got %d", len(usecase.inputs))
+	}
+
+	input := usecase.inputs[0]
+	if input.ApplicationID != applicationID || input.UserID != userID {
+		t.Errorf("Expected labelled application and configured user, got %s and %s", input.ApplicationID, input.UserID)
+	}
+	if input.Level != "WARN" || input.Source != "varlogs" {
+		t.Errorf("Expected WARN from varlogs, got %s from %s", input.Level, input.Source)
+	}
+	if input.Tags["filename"] != `/var/log/app "main".log` || input.Tags["trace_id"] != "abc123" {
+		t.Errorf("Expected labels and structured metadata in tags, got %v", input.Tags)
+	}
+	if _, ok := input.Tags["application_id"]; ok {
+		t.Error("Expected the application label not to be copied into tags")
+	}
+	if input.Timestamp == nil || !input.Timestamp.Equal(timestamp) {
+		t.Errorf("Expected timestamp %v, got %v", timestamp, input.Timestamp)
+	}
+}
+
+func TestLokiController_PushHandler_JSON(t *testing.T) {
+	tenantID := uuid.New()
+	usecase := &mockLogUsecase{}
+	controller := NewLokiController(usecase, Config{UserID: uuid.New()})
+
+	body := `{"streams":[{"stream":{"service_name":"checkout","level":"error"},"values":[
+		["1730109600000000000","payment declined"],
+		["1730109601000000000","retrying",{"attempt":"2"}]
+	]}]}`
+	req := httptest.NewRequest("POST", "/loki/api/v1/push", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Scope-OrgID", tenantID.String())
+	w := httptest.NewRecorder()
+
+	controller.PushHandler(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if len(usecase.inputs) != 2 {
+		t.Fatalf("Expected 2 logs, got %d", len(usecase.inputs))
+	}
+	if usecase.inputs[0].ApplicationID != tenantID {
+		t.Errorf("Expected tenant application %s, got %s", tenantID, usecase.inputs[0].ApplicationID)
+	}
+	if usecase.inputs[0].Level != "ERROR" || usecase.inputs[0].Source != "checkout" {
+		t.Errorf("Unexpected mapped input: %+v", usecase.inputs[0])
+	}
+	if usecase.inputs[1].Tags["attempt"] != "2" {
+		t.Errorf("Expected structured metadata in tags, got %v", usecase.inputs[1].Tags)
+	}
+	if usecase.inputs[0].IdempotencyKey == usecase.inputs[1].IdempotencyKey {
+		t.Error("Expected distinct idempotency keys per entry")
+	}
+}
+
+func TestLokiController_PushHandler_Errors(t *testing.T) {
+	validBody := `{"streams":[{"stream":{"job":"api"},"values":[["1730109600000000000","line"]]}]}`
+
+	tests := []struct {
+		name           string
+		config         Config
+		contentType    string
+		body           string
+		createLogErr   error
+		expectedStatus int
+	}{
+		{name: "Missing application", contentType: "application/json", body: validBody, expectedStatus: http.StatusBadRequest},
+		{name: "Malformed JSON", config: Config{ApplicationID: uuid.New()}, contentType: "application/json", body: `{"streams":`, expectedStatus: http.StatusBadRequest},
+		{name: "Invalid timestamp", config: Config{ApplicationID: uuid.New()}, contentType: "application/json", body: `{"streams":[{"stream":{},"values":[["yesterday","line"]]}]}`, expectedStatus: http.StatusBadRequest},
+		{name: "Malformed protobuf", config: Config{ApplicationID: uuid.New()}, contentType: "application/x-protobuf", body: "not snappy", expectedStatus: http.StatusBadRequest},
+		// A snappy header announcing more than maxBodyBytes is refused before decoding
+		{name: "Decompressed body too large", config: Config{ApplicationID: uuid.New()}, contentType: "application/x-protobuf", body: string(binary.AppendUvarint(nil, maxBodyBytes+1)), expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "Unsupported content type", config: Config{ApplicationID: uuid.New()}, contentType: "text/plain", body: validBody, expectedStatus: http.StatusUnsupportedMediaType},
+		{
+			name:           "Rate limited",
+			config:         Config{ApplicationID: uuid.New()},
+			contentType:    "application/json",
+			body:           validBody,
+			createLogErr:   &ratelimit.ExceededError{Kind: ratelimit.LimitEntries, RetryAfter: time.Second},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := NewLokiController(&mockLogUsecase{createLogErr: tt.createLogErr}, tt.config)
+
+			req := httptest.NewRequest("POST", "/loki/api/v1/push", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+
+			controller.PushHandler(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestDetectLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		line     string
+		expected valueobjects.LogLevel
+	}{
+		{name: "Level label", labels: map[string]string{"level": "warning"}, line: "ERROR in line", expected: valueobjects.LogLevelWarn},
+		{name: "Logfmt field", line: `level=debug msg="cache miss"`, expected: valueobjects.LogLevelDebug},
+		{name: "JSON field", line: `{"msg":"boom","level":"fatal"}`, expected: valueobjects.LogLevelFatal},
+		{name: "Bare word", line: "2025-10-28 10:00:00 [ERROR] connection refused", expected: valueobjects.LogLevelError},
+		{name: "Default", line: "nothing to see here", expected: valueobjects.LogLevelInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if level := detectLevel(tt.labels, Entry{Line: tt.line}); level != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, level)
+			}
+		})
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	labels, err := parseLabels(`{job="api", path="C:\\logs", quote="say \"hi\""}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if labels["job"] != "api" || labels["path"] != `C:\logs` || labels["quote"] != `say "hi"` {
+		t.Errorf("Unexpected labels: %v", labels)
+	}
+
+	for _, invalid := range []string{`job="api"`, `{job=api}`, `{job="api}`} {
+		if _, err := parseLabels(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
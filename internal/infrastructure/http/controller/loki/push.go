@@ -0,0 +1,268 @@
+package loki
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var (
+	ErrInvalidPush   = errors.New("loki: malformed push request")
+	ErrInvalidLabels = errors.New("loki: malformed stream labels")
+	ErrPushTooLarge  = errors.New("loki: push request too large")
+)
+
+// Stream is one labelled stream of a push request
+type Stream struct {
+	Labels  map[string]string
+	Entries []Entry
+}
+
+// Entry is a single log line
+type Entry struct {
+	Timestamp          time.Time
+	Line               string
+	StructuredMetadata map[string]string
+}
+
+// decodeJSON reads the JSON push format:
+// {"streams":[{"stream":{"job":"api"},"values":[["<unix ns>","line",{"trace_id":"..."}]]}]}
+func decodeJSON(body []byte) ([]Stream, error) {
+	var request struct {
+		Streams []struct {
+			Stream map[string]string   `json:"stream"`
+			Values [][]json.RawMessage `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPush, err)
+	}
+
+	streams := make([]Stream, 0, len(request.Streams))
+	for _, s := range request.Streams {
+		stream := Stream{Labels: s.Stream, Entries: make([]Entry, 0, len(s.Values))}
+		for _, value := range s.Values {
+			if len(value) < 2 || len(value) > 3 {
+				return nil, fmt.Errorf("%w: entries must be [timestamp, line, metadata?]", ErrInvalidPush)
+			}
+
+			var rawTimestamp string
+			var entry Entry
+			if err := json.Unmarshal(value[0], &rawTimestamp); err != nil {
+				return nil, fmt.Errorf("%w: timestamp must be a string", ErrInvalidPush)
+			}
+			nanos, err := strconv.ParseInt(rawTimestamp, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: timestamp must be unix nanoseconds", ErrInvalidPush)
+			}
+			entry.Timestamp = time.Unix(0, nanos).UTC()
+			if err := json.Unmarshal(value[1], &entry.Line); err != nil {
+				return nil, fmt.Errorf("%w: line must be a string", ErrInvalidPush)
+			}
+			if len(value) == 3 {
+				if err := json.Unmarshal(value[2], &entry.StructuredMetadata); err != nil {
+					return nil, fmt.Errorf("%w: structured metadata must be an object of strings", ErrInvalidPush)
+				}
+			}
+			stream.Entries = append(stream.Entries, entry)
+		}
+		streams = append(streams, stream)
+	}
+	return streams, nil
+}
+
+// decodeProtobuf reads the snappy-compressed logproto.PushRequest sent by
+// Promtail and Grafana Agent. The handful of messages involved are decoded
+// directly from the wire format:
+//
+//	PushRequest      { repeated StreamAdapter streams = 1; }
+//	StreamAdapter    { string labels = 1; repeated EntryAdapter entries = 2; }
+//	EntryAdapter     { Timestamp timestamp = 1; string line = 2; repeated LabelPairAdapter structuredMetadata = 3; }
+//	LabelPairAdapter { string name = 1; string value = 2; }
+func decodeProtobuf(compressed []byte) ([]Stream, error) {
+	// The snappy header states the decoded length, so oversized requests are
+	// refused before the buffer is allocated
+	size, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPush, err)
+	}
+	if size > maxBodyBytes {
+		return nil, ErrPushTooLarge
+	}
+
+	body, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPush, err)
+	}
+
+	var streams []Stream
+	err = forEachField(body, func(num protowire.Number, value []byte) error {
+		if num != 1 {
+			return nil
+		}
+		stream, err := decodeStream(value)
+		if err != nil {
+			return err
+		}
+		streams = append(streams, stream)
+		return nil
+	})
+	return streams, err
+}
+
+func decodeStream(b []byte) (Stream, error) {
+	var stream Stream
+	err := forEachField(b, func(num protowire.Number, value []byte) error {
+		switch num {
+		case 1:
+			labels, err := parseLabels(string(value))
+			if err != nil {
+				return err
+			}
+			stream.Labels = labels
+		case 2:
+			entry, err := decodeEntry(value)
+			if err != nil {
+				return err
+			}
+			stream.Entries = append(stream.Entries, entry)
+		}
+		return nil
+	})
+	return stream, err
+}
+
+func decodeEntry(b []byte) (Entry, error) {
+	var entry Entry
+	err := forEachField(b, func(num protowire.Number, value []byte) error {
+		switch num {
+		case 1:
+			timestamp, err := decodeTimestamp(value)
+			if err != nil {
+				return err
+			}
+			entry.Timestamp = timestamp
+		case 2:
+			entry.Line = string(value)
+		case 3:
+			var name, labelValue string
+			err := forEachField(value, func(num protowire.Number, field []byte) error {
+				switch num {
+				case 1:
+					name = string(field)
+				case 2:
+					labelValue = string(field)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if entry.StructuredMetadata == nil {
+				entry.StructuredMetadata = make(map[string]string)
+			}
+			entry.StructuredMetadata[name] = labelValue
+		}
+		return nil
+	})
+	return entry, err
+}
+
+// decodeTimestamp reads google.protobuf.Timestamp { int64 seconds = 1; int32 nanos = 2; }
+func decodeTimestamp(b []byte) (time.Time, error) {
+	var seconds, nanos int64
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 || typ != protowire.VarintType {
+			return time.Time{}, ErrInvalidPush
+		}
+		b = b[n:]
+		v, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return time.Time{}, ErrInvalidPush
+		}
+		b = b[n:]
+		switch num {
+		case 1:
+			seconds = int64(v)
+		case 2:
+			nanos = int64(int32(v))
+		}
+	}
+	return time.Unix(seconds, nanos).UTC(), nil
+}
+
+// forEachField calls fn with every length-delimited field and skips the rest
+func forEachField(b []byte, fn func(protowire.Number, []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return ErrInvalidPush
+		}
+		b = b[n:]
+
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return ErrInvalidPush
+			}
+			b = b[n:]
+			continue
+		}
+
+		value, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return ErrInvalidPush
+		}
+		b = b[n:]
+		if err := fn(num, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseLabels reads a Prometheus label set such as {job="api", env="prod"}
+func parseLabels(s string) (map[string]string, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+		return nil, ErrInvalidLabels
+	}
+	s = strings.TrimSpace(s[1 : len(s)-1])
+
+	labels := make(map[string]string)
+	for s != "" {
+		name, rest, ok := strings.Cut(s, "=")
+		name = strings.TrimSpace(name)
+		rest = strings.TrimSpace(rest)
+		if !ok || name == "" || !strings.HasPrefix(rest, `"`) {
+			return nil, ErrInvalidLabels
+		}
+
+		end := 1
+		for end < len(rest) && rest[end] != '"' {
+			if rest[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(rest) {
+			return nil, ErrInvalidLabels
+		}
+		value, err := strconv.Unquote(rest[:end+1])
+		if err != nil {
+			return nil, ErrInvalidLabels
+		}
+		labels[name] = value
+
+		s = strings.TrimSpace(rest[end+1:])
+		s = strings.TrimSpace(strings.TrimPrefix(s, ","))
+	}
+	return labels, nil
+}
@@ -0,0 +1,179 @@
+package otlp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/httputil"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+
+	maxBodyBytes = 16 << 20
+)
+
+// OTLPController implements the OTLP/HTTP logs endpoint so that OpenTelemetry
+// SDKs and collectors can export logs to this service directly
+type OTLPController struct {
+	Usecase usecase.LogUsecaseInterface
+}
+
+func NewOTLPController(uc usecase.LogUsecaseInterface) *OTLPController {
+	return &OTLPController{
+		Usecase: uc,
+	}
+}
+
+// @Summary      Export logs over OTLP/HTTP
+// @Description  Accepts an OpenTelemetry ExportLogsServiceRequest encoded as protobuf or JSON, optionally gzip-compressed. Logs are stored under the X-Application-ID and X-User-ID headers, or the application.id and user.id resource attributes.
+// @Tags         OpenTelemetry
+// @Accept       application/x-protobuf
+// @Accept       json
+// @Produce      application/x-protobuf
+// @Produce      json
+// @Param        X-Application-ID  header  string  false  "Application that owns the exported logs."
+// @Param        X-User-ID         header  string  false  "User that owns the exported logs."
+// @Param        Idempotency-Key   header  string  false  "Client key that makes retries of this request store each record once. Defaults to a digest of the body."
+// @Success      200  {string} string "ExportLogsServiceResponse, with partial_success when records were rejected."
+// @Failure      400  {string} string "Malformed request or missing application/user IDs."
+// @Failure      415  {string} string "Unsupported content type."
+// @Failure      429  {string} string "The application exceeded its ingestion rate limit or daily quota."
+// @Failure      503  {string} string "The ingestion queue is full or shutting down."
+// @Router       /v1/logs [post]
+func (c *OTLPController) ExportLogsHandler(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != contentTypeProtobuf && mediaType != contentTypeJSON {
+		writeStatus(w, contentTypeJSON, http.StatusUnsupportedMediaType, codes.InvalidArgument, "Content-Type must be application/x-protobuf or application/json.")
+		return
+	}
+
+	body, err := httputil.ReadBody(w, r, maxBodyBytes)
+	if err != nil {
+		writeStatus(w, mediaType, http.StatusBadRequest, codes.InvalidArgument, "Invalid request body.")
+		return
+	}
+
+	var request collectorpb.ExportLogsServiceRequest
+	if mediaType == contentTypeProtobuf {
+		err = proto.Unmarshal(body, &request)
+	} else {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, &request)
+	}
+	if err != nil {
+		writeStatus(w, mediaType, http.StatusBadRequest, codes.InvalidArgument, "Invalid ExportLogsServiceRequest.")
+		return
+	}
+
+	defaults := owner{}
+	defaults.applicationID, _ = uuid.Parse(r.Header.Get("X-Application-ID"))
+	defaults.userID, _ = uuid.Parse(r.Header.Get("X-User-ID"))
+
+	// Exporters retry whole requests, so each record gets an idempotency key
+	// derived from the Idempotency-Key header, or else from the request body,
+	// and is stored only once
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" {
+		digest := sha256.Sum256(body)
+		key = "otlp:" + hex.EncodeToString(digest[:16])
+	}
+
+	var rejected int64
+	var firstRejection string
+	index := 0
+	for _, resourceLogs := range request.GetResourceLogs() {
+		o, err := resolveOwner(resourceLogs.GetResource().GetAttributes(), defaults)
+		for _, scopeLogs := range resourceLogs.GetScopeLogs() {
+			for _, record := range scopeLogs.GetLogRecords() {
+				index++
+				if err != nil {
+					rejected++
+					firstRejection = firstNonEmpty(firstRejection, "Missing application or user ID; set the X-Application-ID and X-User-ID headers.")
+					continue
+				}
+
+				input := toCreateLogInput(o, resourceLogs, scopeLogs, record)
+				input.IdempotencyKey = key + ":" + strconv.Itoa(index)
+
+				_, createErr := c.Usecase.CreateLog(r.Context(), input)
+				if createErr == nil {
+					continue
+				}
+				failure := usecase.ClassifyCreateError(createErr)
+				switch failure.Kind {
+				case usecase.CreateErrorDuplicate:
+				case usecase.CreateErrorInvalid, usecase.CreateErrorForbidden:
+					rejected++
+					firstRejection = firstNonEmpty(firstRejection, failure.Message)
+				case usecase.CreateErrorRateLimited:
+					w.Header().Set("Retry-After", strconv.FormatInt(failure.RetryAfterSeconds(), 10))
+					writeStatus(w, mediaType, http.StatusTooManyRequests, codes.ResourceExhausted, failure.Message)
+					return
+				case usecase.CreateErrorUnavailable:
+					w.Header().Set("Retry-After", strconv.FormatInt(failure.RetryAfterSeconds(), 10))
+					writeStatus(w, mediaType, http.StatusServiceUnavailable, codes.Unavailable, failure.Message)
+					return
+				default:
+					writeStatus(w, mediaType, http.StatusServiceUnavailable, codes.Unavailable, "An internal error occurred while storing the logs.")
+					return
+				}
+			}
+		}
+	}
+
+	response := &collectorpb.ExportLogsServiceResponse{}
+	if rejected > 0 {
+		response.PartialSuccess = &collectorpb.ExportLogsPartialSuccess{
+			RejectedLogRecords: rejected,
+			ErrorMessage:       fmt.Sprintf("%d log records rejected: %s", rejected, firstRejection),
+		}
+	}
+	writeMessage(w, mediaType, http.StatusOK, response)
+}
+
+// writeStatus reports a failure as a google.rpc.Status, as required by
+// OTLP/HTTP, with the request ID as a RequestInfo detail
+func writeStatus(w http.ResponseWriter, mediaType string, httpStatus int, code codes.Code, message string) {
+	st := &statuspb.Status{Code: int32(code), Message: message}
+	if requestID := httputil.RequestID(w); requestID != "" {
+		if detail, err := anypb.New(&errdetails.RequestInfo{RequestId: requestID}); err == nil {
+			st.Details = append(st.Details, detail)
+		}
+	}
+	writeMessage(w, mediaType, httpStatus, st)
+}
+
+func writeMessage(w http.ResponseWriter, mediaType string, httpStatus int, message proto.Message) {
+	var body []byte
+	if mediaType == contentTypeProtobuf {
+		body, _ = proto.Marshal(message)
+	} else {
+		mediaType = contentTypeJSON
+		body, _ = protojson.Marshal(message)
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(httpStatus)
+	w.Write(body)
+}
+
+func firstNonEmpty(current, next string) string {
+	if current != "" {
+		return current
+	}
+	return next
+}
@@ -0,0 +1,304 @@
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	logUsecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Mock usecase for testing
+type mockLogUsecase struct {
+	inputs []dto.CreateLogInput
+	errs   map[int]error // Optional: error returned for the n-th call
+}
+
+func (m *mockLogUsecase) CreateLog(ctx context.Context, input dto.CreateLogInput) (*dto.CreateLogOutput, error) {
+	m.inputs = append(m.inputs, input)
+	if err := m.errs[len(m.inputs)]; err != nil {
+		return nil, err
+	}
+	return &dto.CreateLogOutput{}, nil
+}
+
+func (m *mockLogUsecase) ListLogs(ctx context.Context, input dto.ListLogsInput) (*dto.ListLogsOutput, error) {
+	return nil, nil
+}
+
+func stringAttr(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func newExportRequest(resourceAttrs []*commonpb.KeyValue, records ...*logspb.LogRecord) *collectorpb.ExportLogsServiceRequest {
+	return &collectorpb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: &resourcepb.Resource{Attributes: resourceAttrs},
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      &commonpb.InstrumentationScope{Name: "checkout-logger", Version: "1.2.0"},
+				LogRecords: records,
+			}},
+		}},
+	}
+}
+
+func newRecord(body string, severity logspb.SeverityNumber) *logspb.LogRecord {
+	return &logspb.LogRecord{
+		TimeUnixNano:   uint64(time.Date(2025, 10, 28, 10, 0, 0, 123456789, time.UTC).UnixNano()),
+		SeverityNumber: severity,
+		Body:           &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: body}},
+	}
+}
+
+func TestOTLPController_ExportLogsHandler_Protobuf(t *testing.T) {
+	applicationID := uuid.New()
+	userID := uuid.New()
+	usecase := &mockLogUsecase{}
+	controller := NewOTLPController(usecase)
+
+	record := newRecord("payment declined", logspb.SeverityNumber_SEVERITY_NUMBER_ERROR2)
+	record.TraceId = []byte{0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03, 0xd2, 0x69, 0xb6, 0x33, 0x81, 0x3f, 0xc6, 0x0c}
+	record.SpanId = []byte{0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, 0x74}
+	record.Attributes = []*commonpb.KeyValue{
+		stringAttr("http.method", "POST"),
+		{Key: "retry.count", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: 3}}},
+	}
+	request := newExportRequest([]*commonpb.KeyValue{stringAttr("service.name", "checkout")}, record)
+	body, _ := proto.Marshal(request)
+
+	req := httptest.NewRequest("POST", "/v1/logs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Application-ID", applicationID.String())
+	req.Header.Set("X-User-ID", userID.String())
+	w := httptest.NewRecorder()
+
+	controller.ExportLogsHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if w.Header().Get("Content-Type") != "application/x-protobuf" {
+		t.Errorf("Expected protobuf response, got %s", w.Header().Get("Content-Type"))
+	}
+	var response collectorpb.ExportLogsServiceResponse
+	if err := proto.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.GetPartialSuccess() != nil {
+		t.Errorf("Expected no partial success, got %v", response.GetPartialSuccess())
+	}
+
+	if len(usecase.inputs) != 1 {
+		t.Fatalf("Expected 1 log, got %d", len(usecase.inputs))
+	}
+	input := usecase.inputs[0]
+	if input.ApplicationID != applicationID || input.UserID != userID {
+		t.Errorf("Expected header application and user, got %s and %s", input.ApplicationID, input.UserID)
+	}
+	if input.Message != "payment declined" || input.Level != "ERROR" || input.Source != "checkout" {
+		t.Errorf("Unexpected mapped input: %+v", input)
+	}
+	if input.Tags["trace_id"] != "5b8efff798038103d269b633813fc60c" || input.Tags["span_id"] != "eee19b7ec3c1b174" {
+		t.Errorf("Expected trace context in tags, got %v", input.Tags)
+	}
+	if input.Tags["http_method"] != "POST" || input.Tags["service_name"] != "checkout" {
+		t.Errorf("Expected string attributes in tags, got %v", input.Tags)
+	}
+	if input.Metadata["retry_count"] != int64(3) || input.Metadata["scope_name"] != "checkout-logger" {
+		t.Errorf("Expected other attributes in metadata, got %v", input.Metadata)
+	}
+	if input.Timestamp == nil || input.Timestamp.Nanosecond() != 123456789 {
+		t.Errorf("Expected event timestamp with nanoseconds, got %v", input.Timestamp)
+	}
+	if !strings.HasPrefix(input.IdempotencyKey, "otlp:") || !strings.HasSuffix(input.IdempotencyKey, ":1") {
+		t.Errorf("Expected an idempotency key derived from the body without the header, got %q", input.IdempotencyKey)
+	}
+}
+
+func TestOTLPController_ExportLogsHandler_JSONWithResourceOwner(t *testing.T) {
+	applicationID := uuid.New()
+	userID := uuid.New()
+	usecase := &mockLogUsecase{}
+	controller := NewOTLPController(usecase)
+
+	request := newExportRequest(
+		[]*commonpb.KeyValue{stringAttr(ApplicationIDAttribute, applicationID.String()), stringAttr(UserIDAttribute, userID.String())},
+		newRecord("first", logspb.SeverityNumber_SEVERITY_NUMBER_INFO),
+		newRecord("second", logspb.SeverityNumber_SEVERITY_NUMBER_WARN),
+	)
+	body, _ := protojson.Marshal(request)
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write(body)
+	gz.Close()
+
+	req := httptest.NewRequest("POST", "/v1/logs", &gzipped)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("Idempotency-Key", "export-1")
+	w := httptest.NewRecorder()
+
+	controller.ExportLogsHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if len(usecase.inputs) != 2 {
+		t.Fatalf("Expected 2 logs, got %d", len(usecase.inputs))
+	}
+	if usecase.inputs[0].ApplicationID != applicationID || usecase.inputs[1].Level != "WARN" {
+		t.Errorf("Unexpected mapped inputs: %+v", usecase.inputs)
+	}
+	if _, ok := usecase.inputs[0].Tags[ApplicationIDAttribute]; ok {
+		t.Error("Expected owner attributes not to be copied into tags")
+	}
+	if usecase.inputs[0].IdempotencyKey != "export-1:1" || usecase.inputs[1].IdempotencyKey != "export-1:2" {
+		t.Errorf("Expected idempotency keys per record derived from the header, got %q and %q", usecase.inputs[0].IdempotencyKey, usecase.inputs[1].IdempotencyKey)
+	}
+}
+
+func TestOTLPController_ExportLogsHandler_BodyDerivedKeys(t *testing.T) {
+	export := func(message string) []dto.CreateLogInput {
+		usecase := &mockLogUsecase{}
+		controller := NewOTLPController(usecase)
+
+		request := newExportRequest(nil, newRecord(message, 0), newRecord("second", 0))
+		body, _ := proto.Marshal(request)
+		req := httptest.NewRequest("POST", "/v1/logs", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		req.Header.Set("X-Application-ID", uuid.NewString())
+		req.Header.Set("X-User-ID", uuid.NewString())
+		w := httptest.NewRecorder()
+
+		controller.ExportLogsHandler(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		return usecase.inputs
+	}
+
+	first, retry, other := export("first"), export("first"), export("other")
+	if first[0].IdempotencyKey != retry[0].IdempotencyKey || first[1].IdempotencyKey != retry[1].IdempotencyKey {
+		t.Errorf("Expected a retried export to reuse its keys, got %q and %q", first[0].IdempotencyKey, retry[0].IdempotencyKey)
+	}
+	if first[0].IdempotencyKey == first[1].IdempotencyKey {
+		t.Errorf("Expected a key per record, got %q twice", first[0].IdempotencyKey)
+	}
+	if first[0].IdempotencyKey == other[0].IdempotencyKey {
+		t.Errorf("Expected different bodies to get different keys, got %q", first[0].IdempotencyKey)
+	}
+}
+
+func TestOTLPController_ExportLogsHandler_PartialSuccess(t *testing.T) {
+	usecase := &mockLogUsecase{errs: map[int]error{1: fmt.Errorf("%w: message is required", logUsecase.ErrInvalidLog)}}
+	controller := NewOTLPController(usecase)
+
+	request := newExportRequest(nil, newRecord("", 0), newRecord("kept", 0))
+	body, _ := protojson.Marshal(request)
+
+	req := httptest.NewRequest("POST", "/v1/logs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Application-ID", uuid.NewString())
+	req.Header.Set("X-User-ID", uuid.NewString())
+	w := httptest.NewRecorder()
+
+	controller.ExportLogsHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var response collectorpb.ExportLogsServiceResponse
+	if err := protojson.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.GetPartialSuccess().GetRejectedLogRecords() != 1 {
+		t.Errorf("Expected 1 rejected record, got %v", response.GetPartialSuccess())
+	}
+}
+
+func TestOTLPController_ExportLogsHandler_Errors(t *testing.T) {
+	validBody, _ := proto.Marshal(newExportRequest(nil, newRecord("message", 0)))
+
+	tests := []struct {
+		name           string
+		contentType    string
+		body           []byte
+		errs           map[int]error
+		expectedStatus int
+		retryAfter     string
+	}{
+		{name: "Unsupported content type", contentType: "text/plain", body: validBody, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "Malformed protobuf", contentType: "application/x-protobuf", body: []byte{0xff, 0xff}, expectedStatus: http.StatusBadRequest},
+		{
+			name:           "Rate limited",
+			contentType:    "application/x-protobuf",
+			body:           validBody,
+			errs:           map[int]error{1: &ratelimit.ExceededError{Kind: ratelimit.LimitEntries, RetryAfter: 2100 * time.Millisecond}},
+			expectedStatus: http.StatusTooManyRequests,
+			retryAfter:     "3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := NewOTLPController(&mockLogUsecase{errs: tt.errs})
+
+			req := httptest.NewRequest("POST", "/v1/logs", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req.Header.Set("X-Application-ID", uuid.NewString())
+			req.Header.Set("X-User-ID", uuid.NewString())
+			w := httptest.NewRecorder()
+
+			controller.ExportLogsHandler(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Expected Retry-After %q, got %q", tt.retryAfter, got)
+			}
+		})
+	}
+}
+
+func TestSeverityToLevel(t *testing.T) {
+	tests := []struct {
+		number   logspb.SeverityNumber
+		text     string
+		expected valueobjects.LogLevel
+	}{
+		{number: logspb.SeverityNumber_SEVERITY_NUMBER_TRACE4, expected: valueobjects.LogLevelTrace},
+		{number: logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG, expected: valueobjects.LogLevelDebug},
+		{number: logspb.SeverityNumber_SEVERITY_NUMBER_INFO3, expected: valueobjects.LogLevelInfo},
+		{number: logspb.SeverityNumber_SEVERITY_NUMBER_WARN2, expected: valueobjects.LogLevelWarn},
+		{number: logspb.SeverityNumber_SEVERITY_NUMBER_ERROR4, expected: valueobjects.LogLevelError},
+		{number: logspb.SeverityNumber_SEVERITY_NUMBER_FATAL, expected: valueobjects.LogLevelFatal},
+		{text: "warning", expected: valueobjects.LogLevelWarn},
+		{text: "debug", expected: valueobjects.LogLevelDebug},
+		{text: "", expected: valueobjects.LogLevelInfo},
+	}
+
+	for _, tt := range tests {
+		if level := severityToLevel(tt.number, tt.text); level != tt.expected {
+			t.Errorf("Expected %s for severity %d %q, got %s", tt.expected, tt.number, tt.text, level)
+		}
+	}
+}
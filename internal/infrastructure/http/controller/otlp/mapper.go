@@ -0,0 +1,206 @@
+package otlp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+)
+
+const (
+	// ApplicationIDAttribute and UserIDAttribute identify the owner of a
+	// resource's logs when the X-Application-ID / X-User-ID headers are not sent
+	ApplicationIDAttribute = "application.id"
+	UserIDAttribute        = "user.id"
+)
+
+var ErrMissingOwner = errors.New("otlp: application and user IDs are required")
+
+// owner is the application and user a batch of records is stored under
+type owner struct {
+	applicationID uuid.UUID
+	userID        uuid.UUID
+}
+
+// resolveOwner prefers the resource attributes over the request defaults
+func resolveOwner(resource []*commonpb.KeyValue, defaults owner) (owner, error) {
+	o := defaults
+	for _, kv := range resource {
+		switch kv.GetKey() {
+		case ApplicationIDAttribute:
+			if id, err := uuid.Parse(kv.GetValue().GetStringValue()); err == nil {
+				o.applicationID = id
+			}
+		case UserIDAttribute:
+			if id, err := uuid.Parse(kv.GetValue().GetStringValue()); err == nil {
+				o.userID = id
+			}
+		}
+	}
+	if o.applicationID == uuid.Nil || o.userID == uuid.Nil {
+		return owner{}, ErrMissingOwner
+	}
+	return o, nil
+}
+
+// toCreateLogInput maps a log record and its resource and scope. String
+// attributes become tags and every other attribute becomes metadata; record
+// attributes take precedence over resource attributes. Dots in attribute keys
+// are replaced with underscores so that tags can be filtered on.
+func toCreateLogInput(o owner, resource *logspb.ResourceLogs, scope *logspb.ScopeLogs, record *logspb.LogRecord) dto.CreateLogInput {
+	input := dto.CreateLogInput{
+		ApplicationID: o.applicationID,
+		UserID:        o.userID,
+		Message:       bodyString(record.GetBody()),
+		Level:         severityToLevel(record.GetSeverityNumber(), record.GetSeverityText()).String(),
+		Tags:          make(map[string]string),
+		Metadata:      make(map[string]interface{}),
+	}
+
+	for _, kv := range resource.GetResource().GetAttributes() {
+		switch kv.GetKey() {
+		case ApplicationIDAttribute, UserIDAttribute:
+			continue
+		case "service.name":
+			input.Source = kv.GetValue().GetStringValue()
+		}
+		addAttribute(&input, kv)
+	}
+	for _, kv := range record.GetAttributes() {
+		addAttribute(&input, kv)
+	}
+
+	if name := scope.GetScope().GetName(); name != "" {
+		input.Metadata["scope_name"] = name
+	}
+	if version := scope.GetScope().GetVersion(); version != "" {
+		input.Metadata["scope_version"] = version
+	}
+	if text := record.GetSeverityText(); text != "" {
+		input.Metadata["severity_text"] = text
+	}
+	if number := record.GetSeverityNumber(); number != logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED {
+		input.Metadata["severity_number"] = int32(number)
+	}
+	if _, ok := record.GetBody().GetValue().(*commonpb.AnyValue_StringValue); !ok && record.GetBody() != nil {
+		input.Metadata["body"] = anyValue(record.GetBody())
+	}
+
+	// Trace context is kept as tags so logs can be looked up by trace
+	if traceID := record.GetTraceId(); len(traceID) > 0 {
+		input.Tags["trace_id"] = hex.EncodeToString(traceID)
+	}
+	if spanID := record.GetSpanId(); len(spanID) > 0 {
+		input.Tags["span_id"] = hex.EncodeToString(spanID)
+	}
+	if flags := record.GetFlags(); flags != 0 {
+		input.Metadata["trace_flags"] = flags
+	}
+
+	timestamp := record.GetTimeUnixNano()
+	if timestamp == 0 {
+		timestamp = record.GetObservedTimeUnixNano()
+	}
+	if timestamp != 0 {
+		t := time.Unix(0, int64(timestamp)).UTC()
+		input.Timestamp = &t
+	}
+
+	return input
+}
+
+// severityToLevel maps each OTLP severity range to a level (1-4 TRACE, 5-8
+// DEBUG, 9-12 INFO, 13-16 WARN, 17-20 ERROR, 21-24 FATAL), falling back to the
+// severity text and then INFO when the number is unspecified
+func severityToLevel(number logspb.SeverityNumber, text string) valueobjects.LogLevel {
+	switch {
+	case number >= logspb.SeverityNumber_SEVERITY_NUMBER_FATAL:
+		return valueobjects.LogLevelFatal
+	case number >= logspb.SeverityNumber_SEVERITY_NUMBER_ERROR:
+		return valueobjects.LogLevelError
+	case number >= logspb.SeverityNumber_SEVERITY_NUMBER_WARN:
+		return valueobjects.LogLevelWarn
+	case number >= logspb.SeverityNumber_SEVERITY_NUMBER_INFO:
+		return valueobjects.LogLevelInfo
+	case number >= logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG:
+		return valueobjects.LogLevelDebug
+	case number >= logspb.SeverityNumber_SEVERITY_NUMBER_TRACE:
+		return valueobjects.LogLevelTrace
+	}
+
+	if level, ok := valueobjects.ParseLogLevelAlias(text); ok {
+		return level
+	}
+	return valueobjects.LogLevelInfo
+}
+
+func addAttribute(input *dto.CreateLogInput, kv *commonpb.KeyValue) {
+	key := sanitizeKey(kv.GetKey())
+	if key == "" {
+		return
+	}
+	if s, ok := kv.GetValue().GetValue().(*commonpb.AnyValue_StringValue); ok {
+		input.Tags[key] = s.StringValue
+		delete(input.Metadata, key)
+		return
+	}
+	input.Metadata[key] = anyValue(kv.GetValue())
+	delete(input.Tags, key)
+}
+
+func sanitizeKey(key string) string {
+	key = strings.ReplaceAll(key, ".", "_")
+	return strings.ReplaceAll(key, "$", "")
+}
+
+// bodyString renders the record body as the log message; structured bodies are encoded as JSON
+func bodyString(body *commonpb.AnyValue) string {
+	if s, ok := body.GetValue().(*commonpb.AnyValue_StringValue); ok {
+		return s.StringValue
+	}
+	if body == nil || body.GetValue() == nil {
+		return ""
+	}
+	encoded, err := json.Marshal(anyValue(body))
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+// anyValue converts an OTLP value to plain Go types
+func anyValue(v *commonpb.AnyValue) interface{} {
+	switch value := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return value.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return value.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		return value.IntValue
+	case *commonpb.AnyValue_DoubleValue:
+		return value.DoubleValue
+	case *commonpb.AnyValue_BytesValue:
+		return hex.EncodeToString(value.BytesValue)
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]interface{}, 0, len(value.ArrayValue.GetValues()))
+		for _, item := range value.ArrayValue.GetValues() {
+			values = append(values, anyValue(item))
+		}
+		return values
+	case *commonpb.AnyValue_KvlistValue:
+		values := make(map[string]interface{}, len(value.KvlistValue.GetValues()))
+		for _, kv := range value.KvlistValue.GetValues() {
+			values[sanitizeKey(kv.GetKey())] = anyValue(kv.GetValue())
+		}
+		return values
+	default:
+		return nil
+	}
+}
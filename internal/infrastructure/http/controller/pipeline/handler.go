@@ -0,0 +1,35 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"net/http"
+
+	appPipeline "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/pipeline"
+)
+
+// StatsProvider exposes the ingestion pipeline's state
+type StatsProvider interface {
+	Stats() appPipeline.Stats
+}
+
+type PipelineController struct {
+	Pipeline StatsProvider
+}
+
+func NewPipelineController(p StatsProvider) *PipelineController {
+	return &PipelineController{
+		Pipeline: p,
+	}
+}
+
+// @Summary      Ingestion pipeline statistics
+// @Description  Returns queue depth, throughput counters and flush latency of the asynchronous ingestion pipeline.
+// @Tags         Pipeline
+// @Produce      json
+// @Success      200  {object} appPipeline.Stats
+// @Router       /pipeline/stats [get]
+func (c *PipelineController) StatsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(c.Pipeline.Stats())
+}
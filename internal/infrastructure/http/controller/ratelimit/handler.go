@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	appRatelimit "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/httputil"
+)
+
+// PolicyStore abstracts the runtime-configurable limiter
+type PolicyStore interface {
+	Policy(applicationID string) (appRatelimit.Policy, bool)
+	SetPolicy(applicationID string, p appRatelimit.Policy) error
+	ResetPolicy(applicationID string)
+}
+
+// PolicyOutput is the representation of an application's effective limits
+type PolicyOutput struct {
+	ApplicationID string              `json:"application_id"`
+	Override      bool                `json:"override"`
+	Policy        appRatelimit.Policy `json:"policy"`
+}
+
+type RateLimitController struct {
+	Store PolicyStore
+}
+
+func NewRateLimitController(store PolicyStore) *RateLimitController {
+	return &RateLimitController{
+		Store: store,
+	}
+}
+
+// @Summary      Get application rate limits
+// @Description  Returns the ingestion limits currently applied to an application.
+// @Tags         Rate Limits
+// @Produce      json
+// @Param        applicationID  path  string  true  "Application ID"
+// @Success      200  {object} PolicyOutput
+// @Failure      400  {string} string "Invalid application ID."
+// @Router       /applications/{applicationID}/limits [get]
+func (c *RateLimitController) GetLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	applicationID, ok := applicationIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	c.writePolicy(w, applicationID)
+}
+
+// @Summary      Set application rate limits
+// @Description  Overrides the ingestion limits for an application at runtime. Zero values mean unlimited. Overrides are kept in memory only: they are lost on restart and are not shared between instances.
+// @Tags         Rate Limits
+// @Accept       json
+// @Produce      json
+// @Param        applicationID  path  string               true  "Application ID"
+// @Param        policy         body  appRatelimit.Policy  true  "Rate limit policy"
+// @Param        Authorization  header  string  true  "Bearer admin token"
+// @Success      200  {object} PolicyOutput
+// @Failure      400  {string} string "Invalid application ID or policy."
+// @Failure      401  {string} string "Missing or invalid admin token."
+// @Failure      403  {string} string "Administrative routes are disabled (no ADMIN_TOKEN)."
+// @Router       /applications/{applicationID}/limits [put]
+func (c *RateLimitController) SetLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	applicationID, ok := applicationIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var policy appRatelimit.Policy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Invalid request body format.")
+		return
+	}
+
+	if err := c.Store.SetPolicy(applicationID, policy); err != nil {
+		if errors.Is(err, appRatelimit.ErrInvalidPolicy) {
+			httputil.WriteError(w, http.StatusBadRequest, "Rate limit values cannot be negative.")
+			return
+		}
+		httputil.WriteError(w, http.StatusInternalServerError, "An internal error occurred while updating the limits.")
+		return
+	}
+
+	c.writePolicy(w, applicationID)
+}
+
+// @Summary      Reset application rate limits
+// @Description  Removes the application's override so that the default limits apply again.
+// @Tags         Rate Limits
+// @Produce      json
+// @Param        applicationID  path  string  true  "Application ID"
+// @Param        Authorization  header  string  true  "Bearer admin token"
+// @Success      200  {object} PolicyOutput
+// @Failure      400  {string} string "Invalid application ID."
+// @Failure      401  {string} string "Missing or invalid admin token."
+// @Failure      403  {string} string "Administrative routes are disabled (no ADMIN_TOKEN)."
+// @Router       /applications/{applicationID}/limits [delete]
+func (c *RateLimitController) ResetLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	applicationID, ok := applicationIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	c.Store.ResetPolicy(applicationID)
+	c.writePolicy(w, applicationID)
+}
+
+func (c *RateLimitController) writePolicy(w http.ResponseWriter, applicationID string) {
+	policy, override := c.Store.Policy(applicationID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(PolicyOutput{
+		ApplicationID: applicationID,
+		Override:      override,
+		Policy:        policy,
+	})
+}
+
+func applicationIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "applicationID"))
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Invalid application ID.")
+		return "", false
+	}
+	return id.String(), true
+}
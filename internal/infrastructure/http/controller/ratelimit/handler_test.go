@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	appRatelimit "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+)
+
+func newRequest(method, applicationID string, body []byte) *http.Request {
+	req := httptest.NewRequest(method, "/api/v1/applications/"+applicationID+"/limits", bytes.NewBuffer(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("applicationID", applicationID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestRateLimitController_SetAndGetLimits(t *testing.T) {
+	limiter := appRatelimit.NewLimiter(appRatelimit.Policy{EntriesPerSecond: 100})
+	controller := NewRateLimitController(limiter)
+	applicationID := uuid.NewString()
+
+	body, _ := json.Marshal(appRatelimit.Policy{EntriesPerSecond: 5, DailyQuota: 1000})
+	w := httptest.NewRecorder()
+	controller.SetLimitsHandler(w, newRequest("PUT", applicationID, body))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	controller.GetLimitsHandler(w, newRequest("GET", applicationID, nil))
+
+	var output PolicyOutput
+	if err := json.Unmarshal(w.Body.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if !output.Override {
+		t.Error("Expected override to be reported")
+	}
+	if output.Policy.EntriesPerSecond != 5 || output.Policy.DailyQuota != 1000 {
+		t.Errorf("Expected updated policy, got %+v", output.Policy)
+	}
+}
+
+func TestRateLimitController_ResetLimits(t *testing.T) {
+	limiter := appRatelimit.NewLimiter(appRatelimit.Policy{EntriesPerSecond: 100})
+	controller := NewRateLimitController(limiter)
+	applicationID := uuid.NewString()
+	limiter.SetPolicy(applicationID, appRatelimit.Policy{EntriesPerSecond: 1})
+
+	w := httptest.NewRecorder()
+	controller.ResetLimitsHandler(w, newRequest("DELETE", applicationID, nil))
+
+	var output PolicyOutput
+	if err := json.Unmarshal(w.Body.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if output.Override {
+		t.Error("Expected override to be removed")
+	}
+	if output.Policy.EntriesPerSecond != 100 {
+		t.Errorf("Expected default policy, got %+v", output.Policy)
+	}
+}
+
+func TestRateLimitController_InvalidInput(t *testing.T) {
+	controller := NewRateLimitController(appRatelimit.NewLimiter(appRatelimit.Policy{}))
+
+	tests := []struct {
+		name          string
+		applicationID string
+		body          string
+	}{
+		{name: "Invalid application ID", applicationID: "not-a-uuid", body: `{}`},
+		{name: "Invalid JSON", applicationID: uuid.NewString(), body: `invalid json`},
+		{name: "Negative values", applicationID: uuid.NewString(), body: `{"entries_per_second": -1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			controller.SetLimitsHandler(w, newRequest("PUT", tt.applicationID, []byte(tt.body)))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package reload
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/config"
+)
+
+// Reloader applies the reloadable settings of the configuration
+type Reloader interface {
+	Reload(trigger string) config.ReloadStatus
+	Status() config.ReloadStatus
+}
+
+type ReloadController struct {
+	Reloader Reloader
+}
+
+func NewReloadController(r Reloader) *ReloadController {
+	return &ReloadController{
+		Reloader: r,
+	}
+}
+
+// @Summary      Configuration reload status
+// @Description  Returns the outcome of the latest configuration reload: when it happened, what triggered it, the settings it applied and those that need a restart.
+// @Tags         Configuration
+// @Produce      json
+// @Success      200  {object} config.ReloadStatus
+// @Router       /config/reload [get]
+func (c *ReloadController) StatusHandler(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, http.StatusOK, c.Reloader.Status())
+}
+
+// @Summary      Reload the configuration
+// @Description  Reads the configuration file and environment again and applies the reloadable settings, like SIGHUP. Nothing is applied when the new configuration is invalid.
+// @Tags         Configuration
+// @Produce      json
+// @Param        Authorization  header  string  true  "Bearer admin token"
+// @Success      200  {object} config.ReloadStatus
+// @Failure      401  {string} string "Missing or invalid admin token."
+// @Failure      403  {string} string "Administrative routes are disabled (no ADMIN_TOKEN)."
+// @Failure      422  {object} config.ReloadStatus
+// @Router       /config/reload [post]
+func (c *ReloadController) ReloadHandler(w http.ResponseWriter, r *http.Request) {
+	status := c.Reloader.Reload(config.TriggerAPI)
+	code := http.StatusOK
+	if !status.Success {
+		code = http.StatusUnprocessableEntity
+	}
+	writeStatus(w, code, status)
+}
+
+func writeStatus(w http.ResponseWriter, code int, status config.ReloadStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,82 @@
+package reload
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/config"
+)
+
+type mockReloader struct {
+	status   config.ReloadStatus
+	triggers []string
+}
+
+func (m *mockReloader) Reload(trigger string) config.ReloadStatus {
+	m.triggers = append(m.triggers, trigger)
+	m.status.Trigger = trigger
+	return m.status
+}
+
+func (m *mockReloader) Status() config.ReloadStatus {
+	return m.status
+}
+
+func TestReloadHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     config.ReloadStatus
+		wantStatus int
+	}{
+		{name: "Applied", status: config.ReloadStatus{Success: true, Applied: []string{"sse.max_clients"}}, wantStatus: http.StatusOK},
+		{name: "Invalid configuration", status: config.ReloadStatus{Error: "sse.buffer_size must be at least 1"}, wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reloader := &mockReloader{status: tt.status}
+			c := NewReloadController(reloader)
+
+			rec := httptest.NewRecorder()
+			c.ReloadHandler(rec, httptest.NewRequest(http.MethodPost, "/api/v1/config/reload", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			var got config.ReloadStatus
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if got.Trigger != config.TriggerAPI || got.Error != tt.status.Error {
+				t.Errorf("Expected the reload outcome, got %+v", got)
+			}
+			if len(reloader.triggers) != 1 {
+				t.Errorf("Expected one reload, got %d", len(reloader.triggers))
+			}
+		})
+	}
+}
+
+func TestStatusHandler(t *testing.T) {
+	reloader := &mockReloader{status: config.ReloadStatus{Trigger: config.TriggerSignal, Success: true}}
+	c := NewReloadController(reloader)
+
+	rec := httptest.NewRecorder()
+	c.StatusHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/config/reload", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+	if len(reloader.triggers) != 0 {
+		t.Error("Expected the status not to trigger a reload")
+	}
+	var got config.ReloadStatus
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if got.Trigger != config.TriggerSignal || !got.Success {
+		t.Errorf("Expected the latest status, got %+v", got)
+	}
+}
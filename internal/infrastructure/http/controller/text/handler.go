@@ -0,0 +1,281 @@
+package text
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/parser"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/httputil"
+)
+
+const (
+	maxBodyBytes = 16 << 20
+	maxLineBytes = 1 << 20
+	// maxReportedErrors bounds the per-line errors in a response
+	maxReportedErrors = 100
+)
+
+// ParserStore abstracts the runtime-configurable parser registry
+type ParserStore interface {
+	Parser(applicationID string) *parser.Parser
+	Config(applicationID string) (parser.Config, bool)
+	SetConfig(applicationID string, cfg parser.Config) error
+	ResetConfig(applicationID string)
+}
+
+// IngestOutput summarises a text ingestion request
+type IngestOutput struct {
+	Accepted int         `json:"accepted"`
+	Rejected int         `json:"rejected"`
+	Unparsed int         `json:"unparsed"` // Lines that did not match the parser and were stored raw
+	Errors   []LineError `json:"errors,omitempty"`
+}
+
+// LineError describes a rejected line, numbered from 1
+type LineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// ParserOutput is the representation of an application's effective parser
+type ParserOutput struct {
+	ApplicationID string        `json:"application_id"`
+	Override      bool          `json:"override"`
+	Config        parser.Config `json:"config"`
+}
+
+type TextController struct {
+	Usecase usecase.LogUsecaseInterface
+	Parsers ParserStore
+}
+
+func NewTextController(uc usecase.LogUsecaseInterface, parsers ParserStore) *TextController {
+	return &TextController{
+		Usecase: uc,
+		Parsers: parsers,
+	}
+}
+
+// @Summary      Ingest text log lines
+// @Description  Parses each line of a text/plain body with the application's parser (logfmt, regex or grok) and stores it as a log. Lines that do not match are stored raw at the parser's default level.
+// @Tags         Logs
+// @Accept       plain
+// @Produce      json
+// @Param        X-Application-ID  header  string  false  "Application ID (or application_id query parameter)"
+// @Param        X-User-ID         header  string  false  "User ID (or user_id query parameter)"
+// @Param        Idempotency-Key   header  string  false  "Client key that makes retries of this request store each line once."
+// @Success      200  {object} IngestOutput
+// @Failure      400  {string} string "Missing or invalid application or user ID."
+// @Failure      413  {string} string "The body or a line is too large."
+// @Failure      415  {string} string "Content-Type must be text/plain."
+// @Failure      429  {string} string "The application exceeded its ingestion rate limit or daily quota."
+// @Failure      503  {string} string "The ingestion queue is full or shutting down."
+// @Router       /logs/text [post]
+func (c *TextController) IngestHandler(w http.ResponseWriter, r *http.Request) {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != "text/plain" {
+			httputil.WriteError(w, http.StatusUnsupportedMediaType, "Content-Type must be text/plain.")
+			return
+		}
+	}
+
+	applicationID, err := uuid.Parse(headerOrQuery(r, "X-Application-ID", "application_id"))
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "A valid X-Application-ID header or application_id parameter is required.")
+		return
+	}
+	userID, err := uuid.Parse(headerOrQuery(r, "X-User-ID", "user_id"))
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "A valid X-User-ID header or user_id parameter is required.")
+		return
+	}
+
+	p := c.Parsers.Parser(applicationID.String())
+	key := r.Header.Get("Idempotency-Key")
+	output := IngestOutput{}
+	reject := func(line int, message string) {
+		output.Rejected++
+		if len(output.Errors) < maxReportedErrors {
+			output.Errors = append(output.Errors, LineError{Line: line, Error: message})
+		}
+	}
+
+	scanner := bufio.NewScanner(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	scanner.Buffer(make([]byte, 64*1024), maxLineBytes)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		result := p.Parse(line)
+		if !result.Parsed {
+			output.Unparsed++
+		}
+		input := dto.CreateLogInput{
+			ApplicationID: applicationID,
+			UserID:        userID,
+			Message:       result.Message,
+			Level:         result.Level,
+			Source:        result.Source,
+			Tags:          result.Tags,
+			Metadata:      result.Metadata,
+			Timestamp:     result.Timestamp,
+		}
+		// Retried requests repeat the same lines, so each line gets its own key
+		if key != "" {
+			input.IdempotencyKey = key + ":" + strconv.Itoa(lineNumber)
+		}
+
+		_, err := c.Usecase.CreateLog(r.Context(), input)
+		if err == nil {
+			output.Accepted++
+			continue
+		}
+		failure := usecase.ClassifyCreateError(err)
+		switch failure.Kind {
+		case usecase.CreateErrorDuplicate:
+			output.Accepted++
+		case usecase.CreateErrorInvalid, usecase.CreateErrorForbidden:
+			reject(lineNumber, failure.Message)
+		case usecase.CreateErrorRateLimited:
+			failure.Message = "Rate limit exceeded for this application after " + strconv.Itoa(output.Accepted) + " lines."
+			httputil.WriteCreateError(w, failure)
+			return
+		case usecase.CreateErrorUnavailable:
+			httputil.WriteCreateError(w, failure)
+			return
+		default:
+			httputil.WriteError(w, http.StatusInternalServerError, "An internal error occurred while storing the logs.")
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.Is(err, bufio.ErrTooLong) || errors.As(err, &tooLarge) {
+			httputil.WriteError(w, http.StatusRequestEntityTooLarge, "The request body or one of its lines is too large.")
+			return
+		}
+		httputil.WriteError(w, http.StatusBadRequest, "Invalid request body.")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// @Summary      Get application text parser
+// @Description  Returns the parser applied to an application's text lines.
+// @Tags         Parsers
+// @Produce      json
+// @Param        applicationID  path  string  true  "Application ID"
+// @Success      200  {object} ParserOutput
+// @Failure      400  {string} string "Invalid application ID."
+// @Router       /applications/{applicationID}/parser [get]
+func (c *TextController) GetParserHandler(w http.ResponseWriter, r *http.Request) {
+	applicationID, ok := applicationIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	c.writeParser(w, applicationID)
+}
+
+// @Summary      Set application text parser
+// @Description  Overrides the parser for an application's text lines at runtime. Overrides are kept in memory only: they are lost on restart and are not shared between instances.
+// @Tags         Parsers
+// @Accept       json
+// @Produce      json
+// @Param        applicationID  path  string         true  "Application ID"
+// @Param        config         body  parser.Config  true  "Parser configuration"
+// @Param        Authorization  header  string  true  "Bearer admin token"
+// @Success      200  {object} ParserOutput
+// @Failure      400  {string} string "Invalid application ID or parser configuration."
+// @Failure      401  {string} string "Missing or invalid admin token."
+// @Failure      403  {string} string "Administrative routes are disabled (no ADMIN_TOKEN)."
+// @Router       /applications/{applicationID}/parser [put]
+func (c *TextController) SetParserHandler(w http.ResponseWriter, r *http.Request) {
+	applicationID, ok := applicationIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var cfg parser.Config
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Invalid request body format.")
+		return
+	}
+
+	if err := c.Parsers.SetConfig(applicationID, cfg); err != nil {
+		if errors.Is(err, parser.ErrInvalidConfig) {
+			httputil.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		httputil.WriteError(w, http.StatusInternalServerError, "An internal error occurred while updating the parser.")
+		return
+	}
+
+	c.writeParser(w, applicationID)
+}
+
+// @Summary      Reset application text parser
+// @Description  Removes the application's parser override so that the default parser applies again.
+// @Tags         Parsers
+// @Produce      json
+// @Param        applicationID  path  string  true  "Application ID"
+// @Param        Authorization  header  string  true  "Bearer admin token"
+// @Success      200  {object} ParserOutput
+// @Failure      400  {string} string "Invalid application ID."
+// @Failure      401  {string} string "Missing or invalid admin token."
+// @Failure      403  {string} string "Administrative routes are disabled (no ADMIN_TOKEN)."
+// @Router       /applications/{applicationID}/parser [delete]
+func (c *TextController) ResetParserHandler(w http.ResponseWriter, r *http.Request) {
+	applicationID, ok := applicationIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	c.Parsers.ResetConfig(applicationID)
+	c.writeParser(w, applicationID)
+}
+
+func (c *TextController) writeParser(w http.ResponseWriter, applicationID string) {
+	cfg, override := c.Parsers.Config(applicationID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ParserOutput{
+		ApplicationID: applicationID,
+		Override:      override,
+		Config:        cfg,
+	})
+}
+
+func headerOrQuery(r *http.Request, header, param string) string {
+	if value := r.Header.Get(header); value != "" {
+		return value
+	}
+	return r.URL.Query().Get(param)
+}
+
+func applicationIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "applicationID"))
+	if err != nil {
+		httputil.WriteError(w, http.StatusBadRequest, "Invalid application ID.")
+		return "", false
+	}
+	return id.String(), true
+}
@@ -0,0 +1,242 @@
+package text
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/parser"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+)
+
+// Mock usecase for testing
+type mockLogUsecase struct {
+	inputs []dto.CreateLogInput
+	err    func(input dto.CreateLogInput) error
+}
+
+func (m *mockLogUsecase) CreateLog(ctx context.Context, input dto.CreateLogInput) (*dto.CreateLogOutput, error) {
+	m.inputs = append(m.inputs, input)
+	if m.err != nil {
+		if err := m.err(input); err != nil {
+			return nil, err
+		}
+	}
+	return &dto.CreateLogOutput{ID: uuid.New(), Message: input.Message, Level: input.Level}, nil
+}
+
+func (m *mockLogUsecase) ListLogs(ctx context.Context, input dto.ListLogsInput) (*dto.ListLogsOutput, error) {
+	return nil, nil
+}
+
+func newController(t *testing.T, uc *mockLogUsecase) *TextController {
+	registry, err := parser.NewRegistry(parser.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	return NewTextController(uc, registry)
+}
+
+func newIngestRequest(body string) *http.Request {
+	req := httptest.NewRequest("POST", "/api/v1/logs/text", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("X-Application-ID", uuid.NewString())
+	req.Header.Set("X-User-ID", uuid.NewString())
+	return req
+}
+
+func newParserRequest(method, applicationID string, body []byte) *http.Request {
+	req := httptest.NewRequest(method, "/api/v1/applications/"+applicationID+"/parser", bytes.NewBuffer(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("applicationID", applicationID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestTextController_Ingest(t *testing.T) {
+	uc := &mockLogUsecase{err: func(input dto.CreateLogInput) error {
+		if input.Message == "rejected" {
+			return usecase.ErrInvalidLog
+		}
+		return nil
+	}}
+	controller := newController(t, uc)
+
+	body := "level=error logger=db msg=\"connection lost\" retry=3\r\n\n" +
+		"plain text line\n" +
+		"msg=rejected\n"
+	req := newIngestRequest(body)
+	req.Header.Set("Idempotency-Key", "batch-1")
+	w := httptest.NewRecorder()
+	controller.IngestHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var output IngestOutput
+	if err := json.Unmarshal(w.Body.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if output.Accepted != 2 || output.Rejected != 1 || output.Unparsed != 1 {
+		t.Errorf("Unexpected output: %+v", output)
+	}
+	if len(output.Errors) != 1 || output.Errors[0].Line != 4 {
+		t.Errorf("Expected error for line 4, got %+v", output.Errors)
+	}
+
+	first := uc.inputs[0]
+	if first.Message != "connection lost" || first.Level != "ERROR" || first.Source != "db" || first.Metadata["retry"] != "3" {
+		t.Errorf("Unexpected parsed input: %+v", first)
+	}
+	if first.IdempotencyKey != "batch-1:1" || uc.inputs[1].IdempotencyKey != "batch-1:3" {
+		t.Errorf("Expected per-line idempotency keys, got %q and %q", first.IdempotencyKey, uc.inputs[1].IdempotencyKey)
+	}
+	if uc.inputs[1].Message != "plain text line" || uc.inputs[1].Metadata[parser.UnparsedField] != true {
+		t.Errorf("Expected raw line to be stored unparsed, got %+v", uc.inputs[1])
+	}
+}
+
+func TestTextController_IngestUsesApplicationParser(t *testing.T) {
+	uc := &mockLogUsecase{}
+	controller := newController(t, uc)
+	applicationID := uuid.NewString()
+	controller.Parsers.SetConfig(applicationID, parser.Config{
+		Format:  parser.FormatGrok,
+		Pattern: `%{LOGLEVEL:level} %{GREEDYDATA:message}`,
+	})
+
+	req := httptest.NewRequest("POST", "/api/v1/logs/text?application_id="+applicationID+"&user_id="+uuid.NewString(), strings.NewReader("WARN disk almost full"))
+	w := httptest.NewRecorder()
+	controller.IngestHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if uc.inputs[0].Message != "disk almost full" || uc.inputs[0].Level != "WARN" {
+		t.Errorf("Unexpected parsed input: %+v", uc.inputs[0])
+	}
+}
+
+func TestTextController_IngestErrors(t *testing.T) {
+	limited := func(input dto.CreateLogInput) error {
+		return &ratelimit.ExceededError{Kind: ratelimit.LimitEntries, RetryAfter: 1500 * time.Millisecond}
+	}
+
+	tests := []struct {
+		name           string
+		request        func() *http.Request
+		err            func(input dto.CreateLogInput) error
+		expectedStatus int
+	}{
+		{
+			name: "Missing application",
+			request: func() *http.Request {
+				req := newIngestRequest("msg=hi")
+				req.Header.Del("X-Application-ID")
+				return req
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "JSON content type",
+			request: func() *http.Request {
+				req := newIngestRequest(`{"message":"hi"}`)
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "Line too long",
+			request:        func() *http.Request { return newIngestRequest(strings.Repeat("a", maxLineBytes+1)) },
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Rate limited",
+			request:        func() *http.Request { return newIngestRequest("msg=hi") },
+			err:            limited,
+			expectedStatus: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := newController(t, &mockLogUsecase{err: tt.err})
+			w := httptest.NewRecorder()
+			controller.IngestHandler(w, tt.request())
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedStatus == http.StatusTooManyRequests && w.Header().Get("Retry-After") != "2" {
+				t.Errorf("Expected Retry-After 2, got %q", w.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestTextController_SetGetAndResetParser(t *testing.T) {
+	controller := newController(t, &mockLogUsecase{})
+	applicationID := uuid.NewString()
+
+	body, _ := json.Marshal(parser.Config{Format: parser.FormatRegex, Pattern: `^(?P<msg>.*)$`})
+	w := httptest.NewRecorder()
+	controller.SetParserHandler(w, newParserRequest("PUT", applicationID, body))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	controller.GetParserHandler(w, newParserRequest("GET", applicationID, nil))
+
+	var output ParserOutput
+	if err := json.Unmarshal(w.Body.Bytes(), &output); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if !output.Override || output.Config.Format != parser.FormatRegex {
+		t.Errorf("Expected regex override, got %+v", output)
+	}
+
+	w = httptest.NewRecorder()
+	controller.ResetParserHandler(w, newParserRequest("DELETE", applicationID, nil))
+
+	output = ParserOutput{}
+	json.Unmarshal(w.Body.Bytes(), &output)
+	if output.Override || output.Config.Format != parser.FormatLogfmt {
+		t.Errorf("Expected default parser after reset, got %+v", output)
+	}
+}
+
+func TestTextController_SetParserErrors(t *testing.T) {
+	controller := newController(t, &mockLogUsecase{})
+
+	tests := []struct {
+		name          string
+		applicationID string
+		body          string
+	}{
+		{name: "Invalid application ID", applicationID: "not-a-uuid", body: `{}`},
+		{name: "Invalid body", applicationID: uuid.NewString(), body: `{`},
+		{name: "Invalid pattern", applicationID: uuid.NewString(), body: `{"format":"regex","pattern":"("}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			controller.SetParserHandler(w, newParserRequest("PUT", tt.applicationID, []byte(tt.body)))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// Origins holds the origins allowed by CORS, globally and per application,
+// which can be replaced while the server is running without affecting
+// requests in flight
+type Origins struct {
+	policy atomic.Pointer[originPolicy]
+}
+
+type originPolicy struct {
+	global       originSet
+	applications map[string]originSet
+}
+
+type originSet struct {
+	any     bool
+	origins map[string]struct{}
+}
+
+// NewOrigins allows the global origins on every route; "*" or an empty list
+// allows any origin. Origins listed for an application replace the global
+// ones on the routes of that application, where an empty list allows none.
+func NewOrigins(global []string, applications map[string][]string) *Origins {
+	o := &Origins{}
+	o.Set(global, applications)
+	return o
+}
+
+// Set replaces the allowed origins
+func (o *Origins) Set(global []string, applications map[string][]string) {
+	policy := &originPolicy{global: newOriginSet(global), applications: make(map[string]originSet, len(applications))}
+	policy.global.any = policy.global.any || len(global) == 0
+	for applicationID, origins := range applications {
+		policy.applications[strings.ToLower(applicationID)] = newOriginSet(origins)
+	}
+	o.policy.Store(policy)
+}
+
+// Allowed reports whether a request from origin may be served for an
+// application; an empty applicationID checks the global origins
+func (o *Origins) Allowed(applicationID, origin string) bool {
+	policy := o.policy.Load()
+	if set, ok := policy.applications[strings.ToLower(applicationID)]; ok {
+		return set.allows(origin)
+	}
+	return policy.global.allows(origin)
+}
+
+func newOriginSet(origins []string) originSet {
+	set := originSet{origins: make(map[string]struct{}, len(origins))}
+	for _, origin := range origins {
+		if origin == "*" {
+			set.any = true
+		}
+		set.origins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = struct{}{}
+	}
+	return set
+}
+
+func (s originSet) allows(origin string) bool {
+	if s.any {
+		return true
+	}
+	_, ok := s.origins[strings.ToLower(origin)]
+	return ok
+}
+
+// requestApplication finds the application a request is about, before
+// routing: the ID in /api/v1/events/{id} or /api/v1/applications/{id}/...,
+// or the application_id query parameter used by log searches
+func requestApplication(r *http.Request) string {
+	for _, prefix := range []string{"/api/v1/events/", "/api/v1/applications/"} {
+		if rest, ok := strings.CutPrefix(r.URL.Path, prefix); ok {
+			applicationID, _, _ := strings.Cut(rest, "/")
+			return applicationID
+		}
+	}
+	return r.URL.Query().Get("application_id")
+}
@@ -0,0 +1,35 @@
+package httputil
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrBodyTooLarge is returned when a decompressed body exceeds the limit
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// ReadBody reads the request body, decompressing it when the client sent
+// Content-Encoding: gzip. maxBytes caps both the bytes on the wire and the
+// decompressed size, so a small gzip bomb cannot exhaust memory.
+func ReadBody(w http.ResponseWriter, r *http.Request, maxBytes int64) ([]byte, error) {
+	var reader io.Reader = http.MaxBytesReader(w, r.Body, maxBytes)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		reader = io.LimitReader(gz, maxBytes+1)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxBytes {
+		return nil, ErrBodyTooLarge
+	}
+	return body, nil
+}
@@ -0,0 +1,59 @@
+package httputil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func gzipped(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(data)); err != nil {
+		t.Fatalf("Failed to compress body: %v", err)
+	}
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestReadBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     []byte
+		encoding string
+		want     string
+		wantErr  bool
+	}{
+		{name: "Plain body", body: []byte("hello"), want: "hello"},
+		{name: "Gzip body", body: gzipped(t, "hello"), encoding: "gzip", want: "hello"},
+		{name: "Plain body too large", body: []byte(strings.Repeat("a", 33)), wantErr: true},
+		{name: "Decompressed body too large", body: gzipped(t, strings.Repeat("a", 256)), encoding: "gzip", wantErr: true},
+		{name: "Malformed gzip", body: []byte("not gzip"), encoding: "gzip", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+
+			body, err := ReadBody(httptest.NewRecorder(), req, 32)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got body %q", body)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if string(body) != tt.want {
+				t.Errorf("Expected body %q, got %q", tt.want, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Package httputil holds the response and request helpers shared by the HTTP
+// controllers, so that every ingestion API reports errors the same way.
+package httputil
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+)
+
+// RequestIDHeader carries the request ID set by the router on every response
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID returns the ID the router set on the response, or ""
+func RequestID(w http.ResponseWriter) string {
+	return w.Header().Get(RequestIDHeader)
+}
+
+// WriteError responds with a JSON error, including the request ID the router
+// set on the response so that clients can quote it in reports
+func WriteError(w http.ResponseWriter, status int, message string) {
+	body := map[string]string{"error": message}
+	if requestID := RequestID(w); requestID != "" {
+		body["request_id"] = requestID
+	}
+	WriteJSON(w, status, body)
+}
+
+// WriteJSON responds with body encoded as JSON
+func WriteJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// CreateErrorStatus maps the kind of a CreateLog error to its HTTP status
+func CreateErrorStatus(kind usecase.CreateErrorKind) int {
+	switch kind {
+	case usecase.CreateErrorInvalid:
+		return http.StatusBadRequest
+	case usecase.CreateErrorForbidden:
+		return http.StatusForbidden
+	case usecase.CreateErrorDuplicate:
+		return http.StatusConflict
+	case usecase.CreateErrorRateLimited:
+		return http.StatusTooManyRequests
+	case usecase.CreateErrorUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// WriteCreateError responds to a failed CreateLog, with Retry-After set when
+// the client may send the entry again
+func WriteCreateError(w http.ResponseWriter, e usecase.CreateError) {
+	if seconds := e.RetryAfterSeconds(); seconds > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+	WriteError(w, CreateErrorStatus(e.Kind), e.Message)
+}
@@ -0,0 +1,38 @@
+package httputil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name      string
+		requestID string
+		want      map[string]string
+	}{
+		{name: "With request ID", requestID: "req-123", want: map[string]string{"error": "boom", "request_id": "req-123"}},
+		{name: "Without request ID", want: map[string]string{"error": "boom"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if tt.requestID != "" {
+				w.Header().Set(RequestIDHeader, tt.requestID)
+			}
+			WriteError(w, http.StatusBadRequest, "boom")
+
+			if w.Code != http.StatusBadRequest || w.Header().Get("Content-Type") != "application/json" {
+				t.Errorf("Expected a 400 JSON response, got %d %s", w.Code, w.Header().Get("Content-Type"))
+			}
+			var body map[string]string
+			json.NewDecoder(w.Body).Decode(&body)
+			if len(body) != len(tt.want) || body["error"] != tt.want["error"] || body["request_id"] != tt.want["request_id"] {
+				t.Errorf("Expected %v, got %v", tt.want, body)
+			}
+		})
+	}
+}
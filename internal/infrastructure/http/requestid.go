@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	applicationLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/httputil"
+)
+
+// maxRequestIDLength bounds the IDs accepted from clients and proxies
+const maxRequestIDLength = 128
+
+// RequestID identifies every request by the X-Request-ID header a client or
+// proxy sent, or a new UUID. The ID is echoed in the response header, where
+// controllers read it for error bodies, and attached to the request context
+// for the service's logs and the metadata of stored logs.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(httputil.RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+			r.Header.Set(httputil.RequestIDHeader, requestID)
+		}
+		w.Header().Set(httputil.RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(applicationLog.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// validRequestID accepts up to maxRequestIDLength printable ASCII characters,
+// so that client IDs cannot break log lines or response headers
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < 0x21 || requestID[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	logCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/log"
+)
+
+func TestRegisterRoutes_RequestID(t *testing.T) {
+	router := RegisterRoutes(RouterConfig{LogController: logCtrl.NewLogController(nil)})
+
+	tests := []struct {
+		name     string
+		sent     string
+		keepSent bool
+	}{
+		{"Generated", "", false},
+		{"Propagated", "trace-4bf92f3577b34da6", true},
+		{"Replaced when invalid", "line\tbreak", false},
+		{"Replaced when too long", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/logs", strings.NewReader("{"))
+			if tt.sent != "" {
+				req.Header.Set("X-Request-ID", tt.sent)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			requestID := rr.Header().Get("X-Request-ID")
+			if tt.keepSent && requestID != tt.sent {
+				t.Errorf("Expected request ID %q, got %q", tt.sent, requestID)
+			}
+			if !tt.keepSent {
+				if _, err := uuid.Parse(requestID); err != nil {
+					t.Errorf("Expected a generated UUID, got %q", requestID)
+				}
+			}
+
+			var body map[string]string
+			if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+				t.Fatalf("Expected a JSON error body, got %v", err)
+			}
+			if rr.Code != http.StatusBadRequest || body["request_id"] != requestID {
+				t.Errorf("Expected a 400 error with request ID %q, got %d %v", requestID, rr.Code, body)
+			}
+		})
+	}
+}
+
+func TestRegisterRoutes_RecoversPanics(t *testing.T) {
+	router := RegisterRoutes(RouterConfig{SSEServer: panicking{}})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/events/app", nil))
+
+	var body map[string]string
+	json.NewDecoder(rr.Body).Decode(&body)
+	if rr.Code != http.StatusInternalServerError || body["request_id"] == "" {
+		t.Errorf("Expected a 500 error with a request ID, got %d %v", rr.Code, body)
+	}
+}
+
+type panicking struct{}
+
+func (panicking) HTTPHandler(http.ResponseWriter, *http.Request) {
+	panic("boom")
+}
@@ -1,46 +1,122 @@
 package http
 
 import (
+	"io/fs"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	esCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/elasticsearch"
+	healthCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/health"
 	logCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/log"
+	lokiCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/loki"
+	otlpCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/otlp"
+	pipelineCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/pipeline"
+	rateLimitCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/ratelimit"
+	reloadCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/reload"
+	textCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/text"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/metrics"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
 type RouterConfig struct {
-	LogController *logCtrl.LogController
-	SSEServer     interface {
+	LogController       *logCtrl.LogController
+	RateLimitController *rateLimitCtrl.RateLimitController
+	PipelineController  *pipelineCtrl.PipelineController
+	// ReloadController reports and triggers configuration reloads
+	ReloadController *reloadCtrl.ReloadController
+	OTLPController   *otlpCtrl.OTLPController
+	LokiController   *lokiCtrl.LokiController
+	TextController   *textCtrl.TextController
+	// ElasticsearchController is mounted under /es, the path configured in Filebeat or Fluent Bit
+	ElasticsearchController *esCtrl.ElasticsearchController
+	// Dashboard holds the static files of the web dashboard served under /dashboard/
+	Dashboard fs.FS
+	// HealthController serves the /healthz liveness and /readyz readiness probes
+	HealthController *healthCtrl.HealthController
+	// Origins lists the origins allowed by CORS, including for SSE; nil allows any origin
+	Origins *Origins
+	// ClientIdentities binds verified TLS client certificates to the application they ingest for
+	ClientIdentities *ClientIdentities
+	// AdminToken authorizes the administrative routes; they are disabled when empty
+	AdminToken string
+	// Metrics instruments every request and is served under /metrics
+	Metrics   *metrics.Metrics
+	SSEServer interface {
 		HTTPHandler(http.ResponseWriter, *http.Request)
 	}
 }
 
 func RegisterRoutes(cfg RouterConfig) http.Handler {
 	r := chi.NewRouter()
+	r.Use(RequestID)
 
+	origins := cfg.Origins
+	if origins == nil {
+		origins = NewOrigins(nil, nil)
+	}
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-API-Key"},
-		ExposedHeaders:   []string{"Link"},
+		// Checked on every request so that the origins can be reloaded
+		AllowOriginFunc: func(r *http.Request, origin string) bool {
+			return origins.Allowed(requestApplication(r), origin)
+		},
+		AllowedMethods:   []string{"GET", "HEAD", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-API-Key", "Idempotency-Key", "Content-Encoding", "X-Request-ID", "X-Application-ID", "X-User-ID", "X-Scope-OrgID"},
+		ExposedHeaders:   []string{"Link", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-RateLimit-Scope", "Idempotent-Replayed", "X-Elastic-Product", "X-Request-ID"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
 
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	if cfg.Metrics != nil {
+		r.Use(cfg.Metrics.Middleware)
+	}
+	r.Use(logRequests)
+	r.Use(recoverPanics)
+	// Ingestion routes refuse clients without a bound certificate under mutual TLS
+	requireIdentity := func(next http.Handler) http.Handler { return next }
+	if cfg.ClientIdentities != nil {
+		r.Use(cfg.ClientIdentities.Middleware)
+		requireIdentity = cfg.ClientIdentities.RequireIdentity
+	}
+	requireAdmin := RequireAdmin(cfg.AdminToken)
 
 	r.Route("/api/v1", func(r chi.Router) {
 		// Log routes
-		r.Post("/logs", cfg.LogController.CreateLogHandler)
+		r.With(requireIdentity).Post("/logs", cfg.LogController.CreateLogHandler)
+		r.With(requireIdentity).Post("/logs/batch", cfg.LogController.CreateLogsBatchHandler)
+		r.Get("/logs", cfg.LogController.ListLogsHandler)
 
 		// OPTIONS for CORS preflight
 		r.Options("/logs", func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
 		})
 
+		// Plain text and logfmt lines, parsed per application
+		if cfg.TextController != nil {
+			r.With(requireIdentity).Post("/logs/text", cfg.TextController.IngestHandler)
+			r.Get("/applications/{applicationID}/parser", cfg.TextController.GetParserHandler)
+			r.With(requireAdmin).Put("/applications/{applicationID}/parser", cfg.TextController.SetParserHandler)
+			r.With(requireAdmin).Delete("/applications/{applicationID}/parser", cfg.TextController.ResetParserHandler)
+		}
+
+		// Per-application ingestion limits
+		if cfg.RateLimitController != nil {
+			r.Get("/applications/{applicationID}/limits", cfg.RateLimitController.GetLimitsHandler)
+			r.With(requireAdmin).Put("/applications/{applicationID}/limits", cfg.RateLimitController.SetLimitsHandler)
+			r.With(requireAdmin).Delete("/applications/{applicationID}/limits", cfg.RateLimitController.ResetLimitsHandler)
+		}
+
+		// Asynchronous ingestion pipeline observability
+		if cfg.PipelineController != nil {
+			r.Get("/pipeline/stats", cfg.PipelineController.StatsHandler)
+		}
+
+		// Runtime configuration reloads
+		if cfg.ReloadController != nil {
+			r.Get("/config/reload", cfg.ReloadController.StatusHandler)
+			r.With(requireAdmin).Post("/config/reload", cfg.ReloadController.ReloadHandler)
+		}
+
 		// SSE route for log events by applicationID
 		r.Get("/events/{applicationID}", func(w http.ResponseWriter, req *http.Request) {
 			applicationID := chi.URLParam(req, "applicationID")
@@ -52,6 +128,41 @@ func RegisterRoutes(cfg RouterConfig) http.Handler {
 		})
 	})
 
+	// OpenTelemetry OTLP/HTTP logs endpoint, at the path exporters use by default
+	if cfg.OTLPController != nil {
+		r.With(requireIdentity).Post("/v1/logs", cfg.OTLPController.ExportLogsHandler)
+	}
+
+	// Grafana Loki push API for Promtail and Grafana Agent
+	if cfg.LokiController != nil {
+		r.With(requireIdentity).Post("/loki/api/v1/push", cfg.LokiController.PushHandler)
+	}
+
+	// Elasticsearch _bulk compatibility for Filebeat and Fluent Bit
+	if cfg.ElasticsearchController != nil {
+		r.With(requireIdentity).Route("/es", cfg.ElasticsearchController.Routes)
+	}
+
+	// Embedded web dashboard for live tail and search
+	if cfg.Dashboard != nil {
+		r.Get("/", func(w http.ResponseWriter, req *http.Request) {
+			http.Redirect(w, req, "/dashboard/", http.StatusFound)
+		})
+		r.Handle("/dashboard", http.RedirectHandler("/dashboard/", http.StatusMovedPermanently))
+		r.Handle("/dashboard/*", http.StripPrefix("/dashboard/", http.FileServer(http.FS(cfg.Dashboard))))
+	}
+
+	// Liveness and readiness probes for orchestrators
+	if cfg.HealthController != nil {
+		r.Get("/healthz", cfg.HealthController.LivenessHandler)
+		r.Get("/readyz", cfg.HealthController.ReadinessHandler)
+	}
+
+	// Prometheus scrape endpoint
+	if cfg.Metrics != nil {
+		r.Method(http.MethodGet, "/metrics", cfg.Metrics.Handler())
+	}
+
 	r.Handle("/docs/*", http.StripPrefix("/docs/", http.FileServer(http.Dir("docs"))))
 
 	r.Handle("/swagger/*", httpSwagger.Handler(
@@ -0,0 +1,256 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/parser"
+	appRatelimit "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/ratelimit"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/config"
+	rateLimitCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/ratelimit"
+	reloadCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/reload"
+	textCtrl "github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/http/controller/text"
+	"github.com/rubensantoniorosa2704/LoggingSSE/web"
+)
+
+func TestRegisterRoutes_Dashboard(t *testing.T) {
+	router := RegisterRoutes(RouterConfig{Dashboard: web.Dashboard()})
+
+	tests := []struct {
+		path     string
+		status   int
+		location string
+		contains string
+	}{
+		{"/", http.StatusFound, "/dashboard/", ""},
+		{"/dashboard", http.StatusMovedPermanently, "/dashboard/", ""},
+		{"/dashboard/", http.StatusOK, "", "<title>LoggingSSE Dashboard</title>"},
+		{"/dashboard/app.js", http.StatusOK, "", "/api/v1/events/"},
+		{"/dashboard/missing.js", http.StatusNotFound, "", ""},
+	}
+
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rr.Code != tt.status {
+			t.Errorf("Expected status %d for %s, got %d", tt.status, tt.path, rr.Code)
+		}
+		if location := rr.Header().Get("Location"); location != tt.location {
+			t.Errorf("Expected location %q for %s, got %q", tt.location, tt.path, location)
+		}
+		if !strings.Contains(rr.Body.String(), tt.contains) {
+			t.Errorf("Expected body of %s to contain %q", tt.path, tt.contains)
+		}
+	}
+}
+
+func TestRegisterRoutes_WithoutDashboard(t *testing.T) {
+	router := RegisterRoutes(RouterConfig{})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/dashboard/", nil))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rr.Code)
+	}
+}
+
+func TestRegisterRoutes_CORSOrigins(t *testing.T) {
+	origins := NewOrigins([]string{"https://app.example.com"}, nil)
+	router := RegisterRoutes(RouterConfig{Origins: origins, Dashboard: web.Dashboard()})
+
+	allowOrigin := func(origin string) string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/dashboard/", nil)
+		req.Header.Set("Origin", origin)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	if got := allowOrigin("https://app.example.com"); got != "https://app.example.com" {
+		t.Errorf("Expected the configured origin to be allowed, got %q", got)
+	}
+	if got := allowOrigin("https://evil.example.com"); got != "" {
+		t.Errorf("Expected other origins to be refused, got %q", got)
+	}
+
+	// Replacing the origins applies to the next request without rebuilding the router
+	origins.Set([]string{"https://evil.example.com"}, nil)
+	if got := allowOrigin("https://evil.example.com"); got != "https://evil.example.com" {
+		t.Errorf("Expected the reloaded origin to be allowed, got %q", got)
+	}
+	if got := allowOrigin("https://app.example.com"); got != "" {
+		t.Errorf("Expected the removed origin to be refused, got %q", got)
+	}
+
+	origins.Set([]string{"*"}, nil)
+	if got := allowOrigin("https://any.example.com"); got == "" {
+		t.Error("Expected any origin to be allowed with *")
+	}
+}
+
+func TestRegisterRoutes_ApplicationCORSOrigins(t *testing.T) {
+	applicationID := "0b6f3f3e-7a3c-4d7e-9f43-8f0a2e8c1d55"
+	lockedID := "5e2d9c41-8b0a-4f6e-a1c3-2d4b6f8a0c12"
+	origins := NewOrigins([]string{"*"}, map[string][]string{
+		applicationID: {"https://team.example.com"},
+		lockedID:      {},
+	})
+	router := RegisterRoutes(RouterConfig{Origins: origins})
+
+	tests := []struct {
+		name   string
+		path   string
+		origin string
+		want   string
+	}{
+		{name: "Application stream from its origin", path: "/api/v1/events/" + applicationID, origin: "https://team.example.com", want: "https://team.example.com"},
+		{name: "Application stream from another origin", path: "/api/v1/events/" + applicationID, origin: "https://other.example.com", want: ""},
+		{name: "Application search from another origin", path: "/api/v1/logs?application_id=" + applicationID, origin: "https://other.example.com", want: ""},
+		{name: "Application with no origins", path: "/api/v1/events/" + lockedID, origin: "https://team.example.com", want: ""},
+		{name: "Other application", path: "/api/v1/events/7d1c3a52-2b1e-4a4a-8f1e-0f3c2a6b9e10", origin: "https://other.example.com", want: "https://other.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if got := rr.Header().Get("Access-Control-Allow-Origin"); got != tt.want {
+				t.Errorf("Expected allowed origin %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRegisterRoutes_AdminRoutes(t *testing.T) {
+	limiter := appRatelimit.NewLimiter(appRatelimit.Policy{})
+	applicationID := uuid.NewString()
+
+	tests := []struct {
+		name          string
+		adminToken    string
+		authorization string
+		status        int
+	}{
+		{name: "Disabled without a token", authorization: "Bearer secret", status: http.StatusForbidden},
+		{name: "Missing token", adminToken: "secret", status: http.StatusUnauthorized},
+		{name: "Wrong token", adminToken: "secret", authorization: "Bearer guess", status: http.StatusUnauthorized},
+		{name: "Valid token", adminToken: "secret", authorization: "Bearer secret", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := RegisterRoutes(RouterConfig{
+				RateLimitController: rateLimitCtrl.NewRateLimitController(limiter),
+				AdminToken:          tt.adminToken,
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/applications/"+applicationID+"/limits", strings.NewReader(`{"entries_per_second":1}`))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	// Reading the effective limits stays public
+	router := RegisterRoutes(RouterConfig{RateLimitController: rateLimitCtrl.NewRateLimitController(limiter)})
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/applications/"+applicationID+"/limits", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected GET to stay public, got %d", rr.Code)
+	}
+}
+
+func TestRegisterRoutes_ParserOverridesRequireAdmin(t *testing.T) {
+	parsers, err := parser.NewRegistry(parser.Config{})
+	if err != nil {
+		t.Fatalf("NewRegistry: %v", err)
+	}
+	router := RegisterRoutes(RouterConfig{
+		TextController: textCtrl.NewTextController(nil, parsers),
+		AdminToken:     "secret",
+	})
+	path := "/api/v1/applications/" + uuid.NewString() + "/parser"
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, path, nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d without a token, got %d", http.StatusUnauthorized, rr.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, path, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d with the admin token, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected GET to stay public, got %d", rr.Code)
+	}
+}
+
+type stubReloader struct {
+	reloads int
+}
+
+func (s *stubReloader) Reload(trigger string) config.ReloadStatus {
+	s.reloads++
+	return config.ReloadStatus{Trigger: trigger, Success: true}
+}
+
+func (s *stubReloader) Status() config.ReloadStatus {
+	return config.ReloadStatus{}
+}
+
+func TestRegisterRoutes_ConfigReloadRequiresAdmin(t *testing.T) {
+	reloader := &stubReloader{}
+	router := RegisterRoutes(RouterConfig{
+		ReloadController: reloadCtrl.NewReloadController(reloader),
+		AdminToken:       "secret",
+	})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/config/reload", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d without a token, got %d", http.StatusUnauthorized, rr.Code)
+	}
+	if reloader.reloads != 0 {
+		t.Errorf("Expected no reload without a token, got %d", reloader.reloads)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/config/reload", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d with the admin token, got %d", http.StatusOK, rr.Code)
+	}
+	if reloader.reloads != 1 {
+		t.Errorf("Expected 1 reload, got %d", reloader.reloads)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/config/reload", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected GET to stay public, got %d", rr.Code)
+	}
+}
@@ -1,27 +1,98 @@
 package sse
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	r3sse "github.com/r3labs/sse/v2"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/metrics"
 )
 
+const (
+	// ShutdownEvent is the event type sent to every client before the server closes its streams
+	ShutdownEvent = "shutdown"
+
+	// shutdownGrace lets each stream write the shutdown event before it is closed
+	shutdownGrace = 500 * time.Millisecond
+	// shutdownRetry asks EventSource clients to reconnect after one second, typically to another instance
+	shutdownRetry = "1000"
+)
+
+var shutdownPayload = []byte(`{"reason":"server shutting down","reconnect":true}`)
+
+// Limits bounds the clients a server accepts; zero values are unlimited
+type Limits struct {
+	MaxClients          int
+	MaxClientsPerStream int
+	// BufferSize is the number of events a stream queues before new ones are dropped
+	BufferSize int
+}
+
 type Server struct {
-	server *r3sse.Server
+	server  *r3sse.Server
+	metrics *metrics.Transport
+	limits  Limits
+
+	// streamMu serialises stream creation and removal so that each stream is
+	// counted once, and none is created after shutdown
+	streamMu      sync.Mutex
+	streams       map[string]struct{}
+	clients       int
+	streamClients map[string]int
+	closed        atomic.Bool
+}
+
+// Option configures optional Server behaviour
+type Option func(*Server)
+
+// WithMetrics reports subscribers, streams and published or dropped events
+func WithMetrics(t *metrics.Transport) Option {
+	return func(s *Server) {
+		s.metrics = t
+	}
 }
 
-func NewServer() *Server {
-	s := r3sse.New()
-	// Configure CORS headers for SSE
-	s.Headers = map[string]string{
-		"Access-Control-Allow-Origin":  "*",
-		"Access-Control-Allow-Methods": "GET, OPTIONS",
-		"Access-Control-Allow-Headers": "Accept, Authorization, Content-Type, X-CSRF-Token, X-API-Key",
-		"Cache-Control":                "no-cache",
-		"Connection":                   "keep-alive",
+// WithLimits caps the clients connected at once, in total and per stream,
+// and sets the event buffer of each stream
+func WithLimits(l Limits) Option {
+	return func(s *Server) {
+		s.limits = l
 	}
-	return &Server{server: s}
+}
+
+// SetLimits replaces the limits at runtime. Connected clients are kept even
+// when they now exceed a limit, and a new buffer size applies to streams
+// created afterwards.
+func (s *Server) SetLimits(l Limits) {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	s.limits = l
+	if l.BufferSize > 0 {
+		s.server.BufferSize = l.BufferSize
+	}
+}
+
+func NewServer(opts ...Option) *Server {
+	s := &Server{server: r3sse.New(), streams: make(map[string]struct{}), streamClients: make(map[string]int)}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.limits.BufferSize > 0 {
+		s.server.BufferSize = s.limits.BufferSize
+	}
+
+	// CORS headers are set by the router, which knows the allowed origins
+	s.server.Headers = map[string]string{
+		"Cache-Control": "no-cache",
+		"Connection":    "keep-alive",
+	}
+	s.server.OnSubscribe = func(string, *r3sse.Subscriber) { s.metrics.Subscribed() }
+	s.server.OnUnsubscribe = func(string, *r3sse.Subscriber) { s.metrics.Unsubscribed() }
+	return s
 }
 
 func (s *Server) HTTPHandler(w http.ResponseWriter, r *http.Request) {
@@ -31,21 +102,114 @@ func (s *Server) HTTPHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.server.CreateStream(streamName)
-	log.Printf("New client connected to SSE channel (ApplicationID): %s", streamName)
+	logger := slog.With("application_id", streamName, "remote_addr", r.RemoteAddr)
+	if code, message := s.acquire(streamName); code != 0 {
+		logger.WarnContext(r.Context(), "SSE client refused", "status", code, "reason", message)
+		http.Error(w, message, code)
+		return
+	}
+	defer s.release(streamName)
+
+	connected := time.Now()
+	logger.InfoContext(r.Context(), "SSE client connected")
 	s.server.ServeHTTP(w, r)
+	logger.InfoContext(r.Context(), "SSE client disconnected", "duration", time.Since(connected), "shutdown", s.closed.Load())
 }
 
+// Publish queues an event for a stream's subscribers without blocking; when
+// a slow client has let the stream's buffer fill up the event is dropped
+// rather than stalling ingestion, and counted in events_dropped_total.
+// Applications without connected clients have no stream, so nothing is dropped.
 func (s *Server) Publish(channel string, data []byte) {
-	s.server.Publish(channel, &r3sse.Event{
-		Data: data,
-	})
+	switch {
+	case s.server.TryPublish(channel, &r3sse.Event{Data: data}):
+		s.metrics.Published()
+	case s.server.StreamExists(channel):
+		s.metrics.Dropped()
+	}
 }
 
 func (s *Server) StreamExists(channel string) bool {
 	return s.server.StreamExists(channel)
 }
 
+// acquire registers a client of a stream, creating the stream on first use,
+// or returns the status and message to refuse it with
+func (s *Server) acquire(streamName string) (int, string) {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+
+	switch {
+	case s.closed.Load():
+		return http.StatusServiceUnavailable, "Server is shutting down, please reconnect."
+	case s.limits.MaxClients > 0 && s.clients >= s.limits.MaxClients:
+		return http.StatusServiceUnavailable, "Too many clients are connected, please retry later."
+	case s.limits.MaxClientsPerStream > 0 && s.streamClients[streamName] >= s.limits.MaxClientsPerStream:
+		return http.StatusTooManyRequests, "Too many clients are connected to this application."
+	}
+
+	if _, ok := s.streams[streamName]; !ok {
+		s.server.CreateStream(streamName)
+		s.streams[streamName] = struct{}{}
+		s.metrics.StreamOpened()
+	}
+	s.clients++
+	s.streamClients[streamName]++
+	return 0, ""
+}
+
+// release unregisters a client of a stream, removing the stream once its
+// last client has disconnected
+func (s *Server) release(streamName string) {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+
+	s.clients--
+	if s.streamClients[streamName]--; s.streamClients[streamName] == 0 {
+		delete(s.streamClients, streamName)
+		delete(s.streams, streamName)
+		s.metrics.StreamClosed()
+		s.server.RemoveStream(streamName)
+	}
+}
+
+// Shutdown refuses new clients, sends a shutdown event to every connected
+// client so that it reconnects elsewhere, and closes all streams, which ends
+// their requests. It returns early if ctx is done before the event is flushed.
+func (s *Server) Shutdown(ctx context.Context) {
+	s.streamMu.Lock()
+	s.closed.Store(true)
+	streams := make([]string, 0, len(s.streams))
+	for name := range s.streams {
+		streams = append(streams, name)
+	}
+	s.streamMu.Unlock()
+
+	for _, name := range streams {
+		s.server.TryPublish(name, &r3sse.Event{
+			Event: []byte(ShutdownEvent),
+			Data:  shutdownPayload,
+			Retry: []byte(shutdownRetry),
+		})
+	}
+	if len(streams) > 0 {
+		select {
+		case <-time.After(shutdownGrace):
+		case <-ctx.Done():
+		}
+	}
+	s.server.Close()
+}
+
+// Close closes all streams without notifying clients
 func (s *Server) Close() {
+	s.streamMu.Lock()
+	s.closed.Store(true)
+	s.streamMu.Unlock()
 	s.server.Close()
 }
+
+// Closed reports whether Close has been called
+func (s *Server) Closed() bool {
+	return s.closed.Load()
+}
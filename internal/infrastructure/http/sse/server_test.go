@@ -0,0 +1,214 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/metrics"
+)
+
+func TestServer_Shutdown(t *testing.T) {
+	s := NewServer()
+	httpServer := httptest.NewServer(http.HandlerFunc(s.HTTPHandler))
+	defer httpServer.Close()
+
+	resp, err := http.Get(httpServer.URL + "?stream=app")
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer resp.Body.Close()
+	if !s.StreamExists("app") {
+		t.Fatal("Expected the stream to be created")
+	}
+
+	s.Publish("app", []byte(`{"message":"before"}`))
+	done := make(chan struct{})
+	go func() {
+		s.Shutdown(context.Background())
+		close(done)
+	}()
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	body := strings.Join(lines, "\n")
+	if !strings.Contains(body, `data: {"message":"before"}`) {
+		t.Errorf("Expected events published before shutdown to be delivered, got:\n%s", body)
+	}
+	if !strings.Contains(body, "event: "+ShutdownEvent) || !strings.Contains(body, "retry: "+shutdownRetry) {
+		t.Errorf("Expected a shutdown event, got:\n%s", body)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Shutdown to return")
+	}
+	if !s.Closed() || s.StreamExists("app") {
+		t.Error("Expected the server to be closed without streams")
+	}
+
+	resp, err = http.Get(httpServer.URL + "?stream=app")
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected new clients to be refused with 503, got %d", resp.StatusCode)
+	}
+}
+
+func TestServer_Limits(t *testing.T) {
+	s := NewServer(WithLimits(Limits{MaxClients: 2, MaxClientsPerStream: 1}))
+	httpServer := httptest.NewServer(http.HandlerFunc(s.HTTPHandler))
+	defer httpServer.Close()
+
+	connect := func(stream string) *http.Response {
+		t.Helper()
+		resp, err := http.Get(httpServer.URL + "?stream=" + stream)
+		if err != nil {
+			t.Fatalf("Failed to connect: %v", err)
+		}
+		return resp
+	}
+
+	first := connect("app-1")
+	if first.StatusCode != http.StatusOK {
+		t.Fatalf("Expected the first client to connect, got %d", first.StatusCode)
+	}
+
+	sameStream := connect("app-1")
+	sameStream.Body.Close()
+	if sameStream.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 over the per-stream limit, got %d", sameStream.StatusCode)
+	}
+
+	second := connect("app-2")
+	defer second.Body.Close()
+	if second.StatusCode != http.StatusOK {
+		t.Fatalf("Expected a client of another stream to connect, got %d", second.StatusCode)
+	}
+
+	third := connect("app-3")
+	third.Body.Close()
+	if third.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 over the total limit, got %d", third.StatusCode)
+	}
+
+	// Slots are released when a client disconnects
+	first.Body.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp := connect("app-1")
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the slot to be released after disconnecting, got %d", resp.StatusCode)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// lockedBuffer collects log output written from the server's goroutines
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestServer_LogsConnections(t *testing.T) {
+	var logs lockedBuffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	s := NewServer()
+	httpServer := httptest.NewServer(http.HandlerFunc(s.HTTPHandler))
+	defer httpServer.Close()
+
+	resp, err := http.Get(httpServer.URL + "?stream=app")
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	resp.Body.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(logs.String(), "SSE client disconnected") {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the disconnection to be logged, got:\n%s", logs.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	output := logs.String()
+	if !strings.Contains(output, `msg="SSE client connected" application_id=app`) {
+		t.Errorf("Expected the connection to be logged with its application, got:\n%s", output)
+	}
+	if !strings.Contains(output, "duration=") {
+		t.Errorf("Expected the disconnection to be logged with its duration, got:\n%s", output)
+	}
+}
+
+func TestServer_StreamMetrics(t *testing.T) {
+	m := metrics.New(metrics.Config{})
+	s := NewServer(WithMetrics(m.Transport("sse")))
+	httpServer := httptest.NewServer(http.HandlerFunc(s.HTTPHandler))
+	defer httpServer.Close()
+
+	scrape := func() string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+		return w.Body.String()
+	}
+
+	resp, err := http.Get(httpServer.URL + "?stream=app")
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	if body := scrape(); !strings.Contains(body, `loggingsse_streams{transport="sse"} 1`) {
+		t.Errorf("Expected one open stream, got:\n%s", body)
+	}
+
+	// The stream is removed, and the gauge decremented, when its last client disconnects
+	resp.Body.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for s.StreamExists("app") {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the stream to be removed after its client disconnected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	body := scrape()
+	if !strings.Contains(body, `loggingsse_streams{transport="sse"} 0`) {
+		t.Errorf("Expected no open streams, got:\n%s", body)
+	}
+
+	// Events for applications without clients are not counted as dropped
+	s.Publish("app", []byte(`{"message":"nobody listens"}`))
+	if body := scrape(); strings.Contains(body, `loggingsse_events_dropped_total{transport="sse"} 1`) {
+		t.Errorf("Expected no dropped events, got:\n%s", body)
+	}
+}
@@ -0,0 +1,57 @@
+// Package logging builds the structured logger the service reports about
+// itself with, as opposed to the logs it ingests.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	applicationLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+)
+
+// ParseLevel accepts debug, info, warn and error, in any case
+func ParseLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q", level)
+	}
+	return l, nil
+}
+
+// New returns a logger writing to w as "text" (logfmt) or "json" lines.
+// Records logged with a request context carry its request_id.
+func New(w io.Writer, format string, level slog.Leveler) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch format {
+	case "", "text":
+		handler = slog.NewTextHandler(w, opts)
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	return slog.New(requestHandler{handler}), nil
+}
+
+// requestHandler adds the request ID of the record's context
+type requestHandler struct {
+	slog.Handler
+}
+
+func (h requestHandler) Handle(ctx context.Context, r slog.Record) error {
+	if requestID, ok := applicationLog.RequestID(ctx); ok {
+		r.AddAttrs(slog.String(applicationLog.RequestIDField, requestID))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h requestHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h requestHandler) WithGroup(name string) slog.Handler {
+	return requestHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	applicationLog "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+)
+
+func TestNew_JSONWithRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "json", slog.LevelInfo)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx := applicationLog.WithRequestID(context.Background(), "req-1")
+	logger.With("component", "sse").InfoContext(ctx, "Client connected", "application_id", "app")
+	logger.Debug("Hidden below the level")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 record, got %d: %q", len(lines), buf.String())
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q", lines[0])
+	}
+	for key, want := range map[string]string{"msg": "Client connected", "request_id": "req-1", "component": "sse", "application_id": "app"} {
+		if record[key] != want {
+			t.Errorf("Expected %s %q, got %v", key, want, record[key])
+		}
+	}
+}
+
+func TestNew_Text(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "text", slog.LevelDebug)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	logger.Debug("Starting", "port", 8080)
+	if !strings.Contains(buf.String(), `level=DEBUG msg=Starting port=8080`) {
+		t.Errorf("Expected a logfmt record, got %q", buf.String())
+	}
+}
+
+func TestNew_UnknownFormat(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "xml", slog.LevelInfo); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for input, want := range map[string]slog.Level{"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warn": slog.LevelWarn, "error": slog.LevelError} {
+		got, err := ParseLevel(input)
+		if err != nil || got != want {
+			t.Errorf("Expected %s for %q, got %s, %v", want, input, got, err)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
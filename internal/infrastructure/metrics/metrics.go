@@ -0,0 +1,194 @@
+// Package metrics exposes Prometheus metrics for ingestion, delivery to
+// subscribers, storage and the HTTP API.
+//
+// Label values are kept to small, fixed sets: HTTP requests are labelled by
+// route pattern rather than path, and only the first MaxApplications
+// application IDs get their own series, later ones share the "other" label.
+package metrics
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "loggingsse"
+
+	defaultMaxApplications = 100
+
+	// otherApplication labels applications beyond MaxApplications
+	otherApplication = "other"
+)
+
+type Config struct {
+	// MaxApplications caps the application IDs labelled individually; 0 uses the default
+	MaxApplications int
+}
+
+// Metrics owns a registry with every collector of the service
+type Metrics struct {
+	registry *prometheus.Registry
+
+	logsIngested *prometheus.CounterVec
+	logsRejected *prometheus.CounterVec
+
+	repoDuration *prometheus.HistogramVec
+	repoErrors   *prometheus.CounterVec
+
+	subscribers     *prometheus.GaugeVec
+	streams         *prometheus.GaugeVec
+	eventsPublished *prometheus.CounterVec
+	eventsDropped   *prometheus.CounterVec
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpInFlight prometheus.Gauge
+
+	configReloads    *prometheus.CounterVec
+	configLastReload prometheus.Gauge
+
+	maxApplications int
+	mu              sync.Mutex
+	applications    map[string]struct{}
+}
+
+func New(cfg Config) *Metrics {
+	if cfg.MaxApplications <= 0 {
+		cfg.MaxApplications = defaultMaxApplications
+	}
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		logsIngested: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logs_ingested_total",
+			Help:      "Logs accepted for storage, by application and level.",
+		}, []string{"application", "level"}),
+		logsRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logs_rejected_total",
+			Help:      "Log entries refused before storage, by reason.",
+		}, []string{"reason"}),
+		repoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "Latency of log repository operations.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"operation"}),
+		repoErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repository_errors_total",
+			Help:      "Failed log repository operations.",
+		}, []string{"operation"}),
+		subscribers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "subscribers",
+			Help:      "Connected live tail subscribers, by transport.",
+		}, []string{"transport"}),
+		streams: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "streams",
+			Help:      "Application streams open for live tail, by transport.",
+		}, []string{"transport"}),
+		eventsPublished: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_published_total",
+			Help:      "Log events handed to live tail streams, by transport.",
+		}, []string{"transport"}),
+		eventsDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_dropped_total",
+			Help:      "Log events not delivered because a stream or subscriber fell behind, by transport.",
+		}, []string{"transport"}),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests, by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency, by method and route pattern. Event streams are excluded.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		httpInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests being served, including open event streams.",
+		}),
+		configReloads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "config_reloads_total",
+			Help:      "Configuration reload attempts, by result.",
+		}, []string{"result"}),
+		configLastReload: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "config_last_reload_success_timestamp_seconds",
+			Help:      "Time of the last successful configuration reload, or of startup.",
+		}),
+		maxApplications: cfg.MaxApplications,
+		applications:    make(map[string]struct{}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.logsIngested, m.logsRejected,
+		m.repoDuration, m.repoErrors,
+		m.subscribers, m.streams, m.eventsPublished, m.eventsDropped,
+		m.httpRequests, m.httpDuration, m.httpInFlight,
+		m.configReloads, m.configLastReload,
+	)
+	m.configLastReload.SetToCurrentTime()
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Registry returns the registry so that callers can add their own collectors
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// LogIngested counts a log accepted for storage
+func (m *Metrics) LogIngested(applicationID, level string) {
+	m.logsIngested.WithLabelValues(m.applicationLabel(applicationID), level).Inc()
+}
+
+// LogRejected counts an entry refused before storage
+func (m *Metrics) LogRejected(reason string) {
+	m.logsRejected.WithLabelValues(reason).Inc()
+}
+
+// ConfigReloaded counts a configuration reload attempt
+func (m *Metrics) ConfigReloaded(success bool) {
+	if !success {
+		m.configReloads.WithLabelValues("failure").Inc()
+		return
+	}
+	m.configReloads.WithLabelValues("success").Inc()
+	m.configLastReload.SetToCurrentTime()
+}
+
+// applicationLabel returns the application ID while fewer than MaxApplications
+// have been seen, and "other" afterwards
+func (m *Metrics) applicationLabel(applicationID string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.applications[applicationID]; ok {
+		return applicationID
+	}
+	if len(m.applications) >= m.maxApplications {
+		return otherApplication
+	}
+	m.applications[applicationID] = struct{}{}
+	return applicationID
+}
@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func expectSeries(t *testing.T, body string, series ...string) {
+	t.Helper()
+	for _, s := range series {
+		if !strings.Contains(body, s+"\n") {
+			t.Errorf("Expected series %q, got:\n%s", s, body)
+		}
+	}
+}
+
+func TestMetrics_BoundsApplications(t *testing.T) {
+	m := New(Config{MaxApplications: 2})
+	first, second := uuid.NewString(), uuid.NewString()
+
+	m.LogIngested(first, "INFO")
+	m.LogIngested(second, "ERROR")
+	for i := 0; i < 3; i++ {
+		m.LogIngested(uuid.NewString(), "INFO")
+	}
+	m.LogIngested(first, "INFO")
+	m.LogRejected("invalid_level")
+
+	expectSeries(t, scrape(t, m),
+		fmt.Sprintf(`loggingsse_logs_ingested_total{application=%q,level="INFO"} 2`, first),
+		fmt.Sprintf(`loggingsse_logs_ingested_total{application=%q,level="ERROR"} 1`, second),
+		`loggingsse_logs_ingested_total{application="other",level="INFO"} 3`,
+		`loggingsse_logs_rejected_total{reason="invalid_level"} 1`,
+	)
+}
+
+func TestMiddleware_LabelsRoutePattern(t *testing.T) {
+	m := New(Config{})
+	r := chi.NewRouter()
+	r.Use(m.Middleware)
+	r.Get("/apps/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	r.Get("/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+	})
+
+	for _, path := range []string{"/apps/1", "/apps/2", "/events", "/wp-login.php"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PROPFIND", "/apps/1", nil))
+
+	body := scrape(t, m)
+	expectSeries(t, body,
+		`loggingsse_http_requests_total{method="GET",route="/apps/{id}",status="202"} 2`,
+		`loggingsse_http_requests_total{method="GET",route="/events",status="200"} 1`,
+		`loggingsse_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`loggingsse_http_requests_total{method="OTHER",route="unmatched",status="405"} 1`,
+		`loggingsse_http_request_duration_seconds_count{method="GET",route="/apps/{id}"} 2`,
+		`loggingsse_http_requests_in_flight 0`,
+	)
+	if strings.Contains(body, `loggingsse_http_request_duration_seconds_count{method="GET",route="/events"}`) {
+		t.Error("Expected event streams to be left out of the latency histogram")
+	}
+}
+
+type mockLogRepository struct {
+	err error
+}
+
+func (m *mockLogRepository) Create(ctx context.Context, l *log.Log) error {
+	return m.err
+}
+
+func (m *mockLogRepository) CreateMany(ctx context.Context, logs []*log.Log) error {
+	return log.ErrDuplicateLog
+}
+
+func (m *mockLogRepository) Find(ctx context.Context, query log.Query) ([]*log.Log, error) {
+	return nil, nil
+}
+
+func TestRepository_ObservesOperations(t *testing.T) {
+	m := New(Config{})
+	repo := NewRepository(&mockLogRepository{err: errors.New("connection refused")}, m)
+
+	if err := repo.Create(context.Background(), &log.Log{}); err == nil {
+		t.Error("Expected the repository error to be returned")
+	}
+	if err := repo.CreateMany(context.Background(), nil); !errors.Is(err, log.ErrDuplicateLog) {
+		t.Errorf("Expected ErrDuplicateLog, got %v", err)
+	}
+	if _, err := repo.Find(context.Background(), log.Query{}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	body := scrape(t, m)
+	expectSeries(t, body,
+		`loggingsse_repository_errors_total{operation="create"} 1`,
+		`loggingsse_repository_operation_duration_seconds_count{operation="create"} 1`,
+		`loggingsse_repository_operation_duration_seconds_count{operation="create_many"} 1`,
+		`loggingsse_repository_operation_duration_seconds_count{operation="find"} 1`,
+	)
+	if strings.Contains(body, `loggingsse_repository_errors_total{operation="create_many"}`) {
+		t.Error("Expected duplicate IDs not to count as errors")
+	}
+}
+
+func TestTransport(t *testing.T) {
+	m := New(Config{})
+	sse := m.Transport("sse")
+	sse.StreamOpened()
+	sse.Subscribed()
+	sse.Subscribed()
+	sse.Unsubscribed()
+	sse.Published()
+	sse.Dropped()
+
+	var disabled *Transport
+	disabled.Subscribed()
+	disabled.Dropped()
+
+	expectSeries(t, scrape(t, m),
+		`loggingsse_streams{transport="sse"} 1`,
+		`loggingsse_subscribers{transport="sse"} 1`,
+		`loggingsse_events_published_total{transport="sse"} 1`,
+		`loggingsse_events_dropped_total{transport="sse"} 1`,
+	)
+}
+
+func TestMetrics_ConfigReloaded(t *testing.T) {
+	m := New(Config{})
+	m.ConfigReloaded(true)
+	m.ConfigReloaded(false)
+	m.ConfigReloaded(false)
+
+	body := scrape(t, m)
+	expectSeries(t, body,
+		`loggingsse_config_reloads_total{result="success"} 1`,
+		`loggingsse_config_reloads_total{result="failure"} 2`,
+	)
+	if !strings.Contains(body, "loggingsse_config_last_reload_success_timestamp_seconds ") {
+		t.Errorf("Expected the last reload time, got:\n%s", body)
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+)
+
+// Repository decorates a LogRepository with latency and error metrics per
+// operation. Duplicate IDs are reported by the repository on purpose and are
+// not counted as errors.
+type Repository struct {
+	inner   log.LogRepository
+	metrics *Metrics
+}
+
+// NewRepository wraps inner so that its operations are measured
+func NewRepository(inner log.LogRepository, m *Metrics) *Repository {
+	return &Repository{inner: inner, metrics: m}
+}
+
+func (r *Repository) Create(ctx context.Context, l *log.Log) error {
+	return r.observe("create", func() error {
+		return r.inner.Create(ctx, l)
+	})
+}
+
+func (r *Repository) CreateMany(ctx context.Context, logs []*log.Log) error {
+	return r.observe("create_many", func() error {
+		return r.inner.CreateMany(ctx, logs)
+	})
+}
+
+func (r *Repository) Find(ctx context.Context, query log.Query) ([]*log.Log, error) {
+	var logs []*log.Log
+	err := r.observe("find", func() error {
+		var err error
+		logs, err = r.inner.Find(ctx, query)
+		return err
+	})
+	return logs, err
+}
+
+func (r *Repository) observe(operation string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	r.metrics.repoDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, log.ErrDuplicateLog) {
+		r.metrics.repoErrors.WithLabelValues(operation).Inc()
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Transport records delivery metrics for one live tail transport, such as
+// SSE or gRPC. A nil *Transport discards everything, so publishers can use
+// one unconditionally.
+type Transport struct {
+	subscribers prometheus.Gauge
+	streams     prometheus.Gauge
+	published   prometheus.Counter
+	dropped     prometheus.Counter
+}
+
+// Transport returns the delivery metrics labelled with the given transport name
+func (m *Metrics) Transport(name string) *Transport {
+	return &Transport{
+		subscribers: m.subscribers.WithLabelValues(name),
+		streams:     m.streams.WithLabelValues(name),
+		published:   m.eventsPublished.WithLabelValues(name),
+		dropped:     m.eventsDropped.WithLabelValues(name),
+	}
+}
+
+func (t *Transport) Subscribed() {
+	if t != nil {
+		t.subscribers.Inc()
+	}
+}
+
+func (t *Transport) Unsubscribed() {
+	if t != nil {
+		t.subscribers.Dec()
+	}
+}
+
+func (t *Transport) StreamOpened() {
+	if t != nil {
+		t.streams.Inc()
+	}
+}
+
+func (t *Transport) StreamClosed() {
+	if t != nil {
+		t.streams.Dec()
+	}
+}
+
+func (t *Transport) Published() {
+	if t != nil {
+		t.published.Inc()
+	}
+}
+
+func (t *Transport) Dropped() {
+	if t != nil {
+		t.dropped.Inc()
+	}
+}
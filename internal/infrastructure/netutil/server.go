@@ -0,0 +1,129 @@
+// Package netutil holds the connection handling shared by the TCP and UDP log
+// listeners: serving in the background, bounding idle connections, and
+// closing everything on shutdown.
+package netutil
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+// Server tracks the sockets, connections and goroutines of one listener so
+// that Close can stop them all and wait for in-flight messages
+type Server struct {
+	// name prefixes log messages, e.g. "GELF TCP"
+	name string
+
+	mu      sync.Mutex
+	sockets []io.Closer
+	conns   map[net.Conn]struct{}
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+func NewServer(name string) *Server {
+	return &Server{name: name, conns: make(map[net.Conn]struct{})}
+}
+
+// Go runs fn in the background, for example a UDP read loop over a socket
+// registered with Track. Close waits for it to return.
+func (s *Server) Go(fn func()) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		fn()
+	}()
+}
+
+// Track registers a socket to close on shutdown
+func (s *Server) Track(socket io.Closer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sockets = append(s.sockets, socket)
+}
+
+// Serve accepts connections in the background and runs handle for each in
+// its own goroutine. Reads fail once a connection stays silent for
+// idleTimeout, and the connection is closed when handle returns.
+func (s *Server) Serve(listener net.Listener, idleTimeout time.Duration, handle func(net.Conn)) {
+	s.Track(listener)
+	s.Go(func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if !s.Closed() {
+					slog.Error(s.name+" accept failed", "error", err)
+				}
+				return
+			}
+
+			s.mu.Lock()
+			if s.closed {
+				s.mu.Unlock()
+				conn.Close()
+				return
+			}
+			s.conns[conn] = struct{}{}
+			s.wg.Add(1)
+			s.mu.Unlock()
+
+			go s.serveConn(&idleConn{Conn: conn, timeout: idleTimeout}, handle)
+		}
+	})
+}
+
+func (s *Server) serveConn(conn *idleConn, handle func(net.Conn)) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn.Conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	handle(conn)
+}
+
+// Close closes the sockets and open connections, then waits for every
+// goroutine of the server to return
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	var errs []error
+	for _, socket := range s.sockets {
+		errs = append(errs, socket.Close())
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Closed reports whether Close has been called, so that read loops can tell
+// a shutdown from a failure
+func (s *Server) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// idleConn extends the read deadline before every read, so that a connection
+// is dropped only after staying silent for the whole timeout
+type idleConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *idleConn) Read(p []byte) (int, error) {
+	if c.timeout > 0 {
+		c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+	}
+	return c.Conn.Read(p)
+}
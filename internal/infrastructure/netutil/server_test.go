@@ -0,0 +1,78 @@
+package netutil
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestServer_IdleTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := NewServer("test")
+	defer s.Close()
+
+	readErr := make(chan error, 1)
+	s.Serve(listener, 50*time.Millisecond, func(conn net.Conn) {
+		_, err := io.ReadAll(conn)
+		readErr <- err
+	})
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	select {
+	case err := <-readErr:
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Errorf("Expected the idle connection to time out, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the idle connection to be dropped")
+	}
+}
+
+func TestServer_CloseEndsConnections(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := NewServer("test")
+
+	connected := make(chan struct{})
+	s.Serve(listener, time.Minute, func(conn net.Conn) {
+		close(connected)
+		io.Copy(io.Discard, conn)
+	})
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	<-connected
+
+	done := make(chan struct{})
+	go func() {
+		s.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Close to end open connections and return")
+	}
+	if !s.Closed() {
+		t.Error("Expected the server to report it is closed")
+	}
+	if _, err := net.Dial("tcp", listener.Addr().String()); err == nil {
+		t.Error("Expected the listener to be closed")
+	}
+}
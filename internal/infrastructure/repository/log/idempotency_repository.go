@@ -0,0 +1,126 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+)
+
+const IdempotencyCollection = "idempotency_keys"
+
+type IdempotencyRepository struct {
+	collection *mongo.Collection
+	window     time.Duration
+	now        func() time.Time
+}
+
+func NewIdempotencyRepository(client *mongo.Client, databaseName string, window time.Duration) *IdempotencyRepository {
+	collection := client.Database(databaseName).Collection(IdempotencyCollection)
+
+	return &IdempotencyRepository{
+		collection: collection,
+		window:     window,
+		now:        time.Now,
+	}
+}
+
+// indexOptionsConflict is the server error returned when an index exists
+// under the same name with different options
+const indexOptionsConflict = 85
+
+// ttlIndexName is the TTL index that expires keys after the window
+const ttlIndexName = "created_at_ttl"
+
+// EnsureIndexes creates the unique key index and the TTL index that expires
+// keys after the window. When the window has changed since the TTL index was
+// created, its expiry is updated in place with collMod.
+func (r *IdempotencyRepository) EnsureIndexes(ctx context.Context) error {
+	expireAfter := int32(r.window.Seconds())
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "application_id", Value: 1}, {Key: "key", Value: 1}},
+		Options: options.Index().SetName("application_key_unique").SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("mongodb: failed to create idempotency indexes: %w", err)
+	}
+
+	_, err = r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetName(ttlIndexName).SetExpireAfterSeconds(expireAfter),
+	})
+
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(indexOptionsConflict) {
+		err = r.collection.Database().RunCommand(ctx, bson.D{
+			{Key: "collMod", Value: r.collection.Name()},
+			{Key: "index", Value: bson.D{
+				{Key: "name", Value: ttlIndexName},
+				{Key: "expireAfterSeconds", Value: expireAfter},
+			}},
+		}).Err()
+		if err != nil {
+			return fmt.Errorf("mongodb: failed to update idempotency window: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("mongodb: failed to create idempotency indexes: %w", err)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record *log.IdempotencyRecord) (*log.IdempotencyRecord, error) {
+	// A second attempt covers keys released or expired between insert and lookup
+	for attempt := 0; attempt < 2; attempt++ {
+		_, err := r.collection.InsertOne(ctx, record)
+		if err == nil {
+			return nil, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, fmt.Errorf("mongodb: failed to reserve idempotency key: %w", err)
+		}
+
+		filter := bson.M{"application_id": record.ApplicationID, "key": record.Key}
+		var existing log.IdempotencyRecord
+		err = r.collection.FindOne(ctx, filter).Decode(&existing)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mongodb: failed to load idempotency key: %w", err)
+		}
+
+		// The TTL monitor only runs periodically, so expire stale keys eagerly
+		if existing.CreatedAt.Before(r.now().Add(-r.window)) {
+			filter["created_at"] = existing.CreatedAt
+			if _, err := r.collection.DeleteOne(ctx, filter); err != nil {
+				return nil, fmt.Errorf("mongodb: failed to expire idempotency key: %w", err)
+			}
+			continue
+		}
+
+		return &existing, nil
+	}
+
+	return nil, fmt.Errorf("mongodb: idempotency key %q is being reserved concurrently", record.Key)
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, applicationID uuid.UUID, key string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"application_id": applicationID, "key": key})
+
+	if err != nil {
+		return fmt.Errorf("mongodb: failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package log
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+func TestIdempotencyRepository_Reserve_Integration(t *testing.T) {
+	client, cleanup := setupTestMongoDB(t)
+	if client == nil {
+		return // Test was skipped
+	}
+	defer cleanup()
+
+	testDB := "loggingdb_test"
+	repo := NewIdempotencyRepository(client, testDB, time.Hour)
+
+	ctx := context.Background()
+	if err := repo.EnsureIndexes(ctx); err != nil {
+		t.Fatalf("Failed to create indexes: %v", err)
+	}
+
+	applicationID := uuid.New()
+	original, err := log.New("Idempotency test", valueobjects.LogLevelInfo, applicationID, uuid.New())
+	if err != nil {
+		t.Fatalf("Failed to create test log: %v", err)
+	}
+
+	record := &log.IdempotencyRecord{ApplicationID: applicationID, Key: "key-1", Log: original, CreatedAt: time.Now()}
+	existing, err := repo.Reserve(ctx, record)
+	if err != nil {
+		t.Fatalf("Failed to reserve key: %v", err)
+	}
+	if existing != nil {
+		t.Fatalf("Expected new key to be reserved, got existing record %+v", existing)
+	}
+
+	// The same key returns the original log
+	retry, _ := log.New("Idempotency test", valueobjects.LogLevelInfo, applicationID, uuid.New())
+	existing, err = repo.Reserve(ctx, &log.IdempotencyRecord{ApplicationID: applicationID, Key: "key-1", Log: retry, CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("Failed to look up existing key: %v", err)
+	}
+	if existing == nil || existing.Log.ID != original.ID {
+		t.Fatalf("Expected original log %s to be returned, got %+v", original.ID, existing)
+	}
+
+	// Keys are scoped per application
+	existing, err = repo.Reserve(ctx, &log.IdempotencyRecord{ApplicationID: uuid.New(), Key: "key-1", Log: retry, CreatedAt: time.Now()})
+	if err != nil || existing != nil {
+		t.Errorf("Expected key to be free for another application, got %+v, %v", existing, err)
+	}
+
+	// A released key can be reserved again
+	if err := repo.Release(ctx, applicationID, "key-1"); err != nil {
+		t.Fatalf("Failed to release key: %v", err)
+	}
+	existing, err = repo.Reserve(ctx, &log.IdempotencyRecord{ApplicationID: applicationID, Key: "key-1", Log: retry, CreatedAt: time.Now()})
+	if err != nil || existing != nil {
+		t.Errorf("Expected released key to be reserved again, got %+v, %v", existing, err)
+	}
+}
+
+func TestIdempotencyRepository_EnsureIndexes_WindowChange_Integration(t *testing.T) {
+	client, cleanup := setupTestMongoDB(t)
+	if client == nil {
+		return // Test was skipped
+	}
+	defer cleanup()
+
+	testDB := "loggingdb_test"
+	ctx := context.Background()
+	if err := NewIdempotencyRepository(client, testDB, time.Hour).EnsureIndexes(ctx); err != nil {
+		t.Fatalf("Failed to create indexes: %v", err)
+	}
+
+	// Restarting with a different window updates the TTL index instead of failing
+	if err := NewIdempotencyRepository(client, testDB, 2*time.Hour).EnsureIndexes(ctx); err != nil {
+		t.Fatalf("Failed to update the idempotency window: %v", err)
+	}
+}
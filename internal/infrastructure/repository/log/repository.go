@@ -2,9 +2,13 @@ package log
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
 )
@@ -23,12 +27,121 @@ func NewLogRepository(client *mongo.Client, databaseName string) *LogRepository
 	}
 }
 
+// EnsureIndexes creates the indexes used to query an application's logs by either time field
+func (r *LogRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "application_id", Value: 1}, {Key: "timestamp", Value: -1}},
+			Options: options.Index().SetName("application_timestamp"),
+		},
+		{
+			Keys:    bson.D{{Key: "application_id", Value: 1}, {Key: "ingested_at", Value: -1}},
+			Options: options.Index().SetName("application_ingested_at"),
+		},
+	})
+
+	if err != nil {
+		return fmt.Errorf("mongodb: failed to create log indexes: %w", err)
+	}
+
+	return nil
+}
+
 func (r *LogRepository) Create(ctx context.Context, l *log.Log) error {
 	_, err := r.collection.InsertOne(ctx, l)
 
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("mongodb: failed to insert log %s: %w", l.ID, log.ErrDuplicateLog)
+	}
 	if err != nil {
 		return fmt.Errorf("mongodb: failed to insert log: %w", err)
 	}
 
 	return nil
 }
+
+func (r *LogRepository) CreateMany(ctx context.Context, logs []*log.Log) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, len(logs))
+	for i, l := range logs {
+		documents[i] = l
+	}
+
+	_, err := r.collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+
+	// Unordered inserts keep going past duplicates, so a batch where every
+	// failure is a duplicate key has still been fully persisted
+	if onlyDuplicates(err) {
+		return fmt.Errorf("mongodb: some of %d logs already exist: %w", len(logs), log.ErrDuplicateLog)
+	}
+	if err != nil {
+		return fmt.Errorf("mongodb: failed to insert %d logs: %w", len(logs), err)
+	}
+
+	return nil
+}
+
+func onlyDuplicates(err error) bool {
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) || bwe.WriteConcernError != nil || len(bwe.WriteErrors) == 0 {
+		return false
+	}
+	for _, we := range bwe.WriteErrors {
+		if !mongo.IsDuplicateKeyError(we) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *LogRepository) Find(ctx context.Context, q log.Query) ([]*log.Log, error) {
+	field := string(q.TimeField)
+
+	filter := bson.M{"application_id": q.ApplicationID}
+	timeRange := bson.M{}
+	if !q.From.IsZero() {
+		timeRange["$gte"] = q.From
+	}
+	if !q.To.IsZero() {
+		timeRange["$lt"] = q.To
+	}
+	if len(timeRange) > 0 {
+		filter[field] = timeRange
+	}
+	if len(q.Levels) > 0 {
+		filter["level"] = bson.M{"$in": q.Levels}
+	}
+	if q.Source != "" {
+		filter["source"] = q.Source
+	}
+	for key, value := range q.Tags {
+		filter["tags."+key] = value
+	}
+	if q.Search != "" {
+		filter["message"] = bson.M{"$regex": regexp.QuoteMeta(q.Search), "$options": "i"}
+	}
+
+	direction := 1
+	if q.Descending {
+		direction = -1
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: field, Value: direction}, {Key: "_id", Value: direction}}).
+		SetSkip(int64(q.Offset)).
+		SetLimit(int64(q.Limit))
+
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("mongodb: failed to query logs: %w", err)
+	}
+
+	logs := make([]*log.Log, 0)
+	if err := cursor.All(ctx, &logs); err != nil {
+		return nil, fmt.Errorf("mongodb: failed to decode logs: %w", err)
+	}
+
+	return logs, nil
+}
@@ -191,6 +191,53 @@ func TestLogRepository_Create_Multiple_Integration(t *testing.T) {
 	}
 }
 
+func TestLogRepository_CreateMany_Integration(t *testing.T) {
+	client, cleanup := setupTestMongoDB(t)
+	if client == nil {
+		return // Test was skipped
+	}
+	defer cleanup()
+
+	// Setup repository
+	testDB := "loggingdb_test"
+	repo := NewLogRepository(client, testDB)
+
+	applicationID := uuid.New()
+	userID := uuid.New()
+
+	logs := make([]*log.Log, 5)
+	for i := range logs {
+		testLog, err := log.New("Bulk insert test", valueobjects.LogLevelInfo, applicationID, userID)
+		if err != nil {
+			t.Fatalf("Failed to create test log %d: %v", i, err)
+		}
+		logs[i] = testLog
+	}
+
+	// Insert all logs in a single round-trip
+	ctx := context.Background()
+	if err := repo.CreateMany(ctx, logs); err != nil {
+		t.Fatalf("Failed to bulk insert logs: %v", err)
+	}
+
+	collection := client.Database(testDB).Collection(LogsCollection)
+	count, err := collection.CountDocuments(ctx, map[string]interface{}{
+		"application_id": applicationID,
+	})
+	if err != nil {
+		t.Errorf("Failed to count documents: %v", err)
+	}
+
+	if count != int64(len(logs)) {
+		t.Errorf("Expected %d logs in database, got %d", len(logs), count)
+	}
+
+	// An empty batch is a no-op
+	if err := repo.CreateMany(ctx, nil); err != nil {
+		t.Errorf("Expected empty batch to succeed, got %v", err)
+	}
+}
+
 func TestLogRepository_Create_ContextCancellation_Integration(t *testing.T) {
 	client, cleanup := setupTestMongoDB(t)
 	if client == nil {
@@ -0,0 +1,175 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+)
+
+const (
+	defaultReplayInterval = 5 * time.Second
+	replayBatchSize       = 500
+	replayTimeout         = 30 * time.Second
+)
+
+// Repository decorates a LogRepository so that inserts which fail are written
+// to the local spool and acknowledged. A background replayer drains the spool
+// into the wrapped repository once it is reachable again; because replays reuse
+// the original log IDs, duplicates are ignored rather than inserted twice.
+type Repository struct {
+	inner    log.LogRepository
+	spool    *Spool
+	interval time.Duration
+
+	// degraded routes writes straight to the spool while the backlog drains,
+	// so requests do not wait on a database that is known to be down
+	degraded atomic.Bool
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewRepository wraps inner with spooling and starts the replayer
+func NewRepository(inner log.LogRepository, spool *Spool, replayInterval time.Duration) *Repository {
+	if replayInterval <= 0 {
+		replayInterval = defaultReplayInterval
+	}
+
+	r := &Repository{
+		inner:    inner,
+		spool:    spool,
+		interval: replayInterval,
+		stop:     make(chan struct{}),
+	}
+	r.degraded.Store(!spool.Empty())
+
+	r.wg.Add(1)
+	go r.replayLoop()
+	return r
+}
+
+func (r *Repository) Create(ctx context.Context, l *log.Log) error {
+	return r.write(ctx, []*log.Log{l}, func() error {
+		return r.inner.Create(ctx, l)
+	})
+}
+
+func (r *Repository) CreateMany(ctx context.Context, logs []*log.Log) error {
+	return r.write(ctx, logs, func() error {
+		return r.inner.CreateMany(ctx, logs)
+	})
+}
+
+// Find queries the wrapped repository; spooled logs become visible once replayed
+func (r *Repository) Find(ctx context.Context, query log.Query) ([]*log.Log, error) {
+	return r.inner.Find(ctx, query)
+}
+
+// Degraded reports whether writes are currently being spooled
+func (r *Repository) Degraded() bool {
+	return r.degraded.Load()
+}
+
+// Stats returns the spool's disk usage
+func (r *Repository) Stats() Stats {
+	return r.spool.Stats()
+}
+
+// Close stops the replayer and closes the spool
+func (r *Repository) Close() error {
+	close(r.stop)
+	r.wg.Wait()
+	return r.spool.Close()
+}
+
+func (r *Repository) write(ctx context.Context, logs []*log.Log, insert func() error) error {
+	if !r.degraded.Load() {
+		err := insert()
+		if err == nil || errors.Is(err, log.ErrDuplicateLog) {
+			return err
+		}
+		slog.Warn("Insert failed, spooling logs to disk", "logs", len(logs), "error", err)
+		r.degraded.Store(true)
+
+		if spoolErr := r.spool.Append(logs); spoolErr != nil {
+			return errors.Join(err, spoolErr)
+		}
+		return nil
+	}
+
+	if err := r.spool.Append(logs); err != nil {
+		return fmt.Errorf("failed to spool logs: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) replayLoop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			if !r.degraded.Load() && r.spool.Empty() {
+				continue
+			}
+			replayed, err := r.Replay()
+			if err != nil {
+				slog.Warn("Spool replay paused", "replayed", replayed, "error", err)
+				continue
+			}
+			if replayed > 0 {
+				slog.Info("Spool replay recovered logs", "replayed", replayed)
+			}
+		}
+	}
+}
+
+// Replay drains every spooled segment into the wrapped repository, oldest
+// first, and leaves degraded mode once the spool is empty.
+func (r *Repository) Replay() (int, error) {
+	segments, err := r.spool.Seal()
+	if err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for _, name := range segments {
+		logs, err := r.spool.Read(name)
+		if err != nil {
+			return replayed, err
+		}
+
+		for start := 0; start < len(logs); start += replayBatchSize {
+			batch := logs[start:min(start+replayBatchSize, len(logs))]
+
+			ctx, cancel := context.WithTimeout(context.Background(), replayTimeout)
+			err := r.inner.CreateMany(ctx, batch)
+			cancel()
+			if err != nil && !errors.Is(err, log.ErrDuplicateLog) {
+				return replayed, err
+			}
+			replayed += len(batch)
+		}
+
+		if err := r.spool.Remove(name); err != nil {
+			return replayed, err
+		}
+	}
+
+	// Writes that raced with the replay stay spooled and are picked up on the next tick
+	if r.spool.Empty() {
+		r.degraded.Store(false)
+	}
+	return replayed, nil
+}
@@ -0,0 +1,188 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+)
+
+// mockLogRepository simulates MongoDB, rejecting duplicate IDs like the unique _id index
+type mockLogRepository struct {
+	mu     sync.Mutex
+	down   bool
+	stored map[uuid.UUID]*log.Log
+	calls  int
+}
+
+func newMockLogRepository() *mockLogRepository {
+	return &mockLogRepository{stored: make(map[uuid.UUID]*log.Log)}
+}
+
+func (m *mockLogRepository) Create(ctx context.Context, l *log.Log) error {
+	return m.CreateMany(ctx, []*log.Log{l})
+}
+
+func (m *mockLogRepository) CreateMany(ctx context.Context, logs []*log.Log) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	if m.down {
+		return errors.New("server selection timeout")
+	}
+	duplicate := false
+	for _, l := range logs {
+		if _, exists := m.stored[l.ID]; exists {
+			duplicate = true
+			continue
+		}
+		m.stored[l.ID] = l
+	}
+	if duplicate {
+		return fmt.Errorf("mock: %w", log.ErrDuplicateLog)
+	}
+	return nil
+}
+
+func (m *mockLogRepository) Find(ctx context.Context, query log.Query) ([]*log.Log, error) {
+	return nil, nil
+}
+
+func (m *mockLogRepository) setDown(down bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.down = down
+}
+
+func (m *mockLogRepository) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.stored)
+}
+
+func newTestRepository(t *testing.T, inner log.LogRepository) *Repository {
+	s, err := Open(t.TempDir(), Config{})
+	if err != nil {
+		t.Fatalf("Failed to open spool: %v", err)
+	}
+	r := NewRepository(inner, s, time.Hour)
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func TestRepository_PassThroughWhenHealthy(t *testing.T) {
+	inner := newMockLogRepository()
+	r := newTestRepository(t, inner)
+
+	if err := r.Create(context.Background(), newTestLog(t)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if inner.count() != 1 {
+		t.Errorf("Expected 1 stored log, got %d", inner.count())
+	}
+	if r.Degraded() {
+		t.Error("Expected repository not to be degraded")
+	}
+}
+
+func TestRepository_SpoolsAndReplays(t *testing.T) {
+	inner := newMockLogRepository()
+	inner.setDown(true)
+	r := newTestRepository(t, inner)
+
+	logs := []*log.Log{newTestLog(t), newTestLog(t), newTestLog(t)}
+	for _, l := range logs {
+		if err := r.Create(context.Background(), l); err != nil {
+			t.Fatalf("Expected spooled write to be acknowledged, got %v", err)
+		}
+	}
+
+	if !r.Degraded() {
+		t.Fatal("Expected repository to be degraded after a failed insert")
+	}
+	if inner.calls != 1 {
+		t.Errorf("Expected writes to bypass the database once degraded, got %d calls", inner.calls)
+	}
+
+	// Replay fails while the database is still down and keeps the data
+	if _, err := r.Replay(); err == nil {
+		t.Error("Expected replay to fail while the database is down")
+	}
+	if r.spool.Empty() {
+		t.Fatal("Expected spool to keep entries after a failed replay")
+	}
+
+	inner.setDown(false)
+	replayed, err := r.Replay()
+	if err != nil {
+		t.Fatalf("Unexpected replay error: %v", err)
+	}
+	if replayed != len(logs) {
+		t.Errorf("Expected %d logs replayed, got %d", len(logs), replayed)
+	}
+	if inner.count() != len(logs) {
+		t.Errorf("Expected %d stored logs, got %d", len(logs), inner.count())
+	}
+	if r.Degraded() {
+		t.Error("Expected repository to recover once the spool is drained")
+	}
+	if !r.spool.Empty() {
+		t.Error("Expected spool to be empty after replay")
+	}
+}
+
+func TestRepository_ReplayIsIdempotent(t *testing.T) {
+	inner := newMockLogRepository()
+	r := newTestRepository(t, inner)
+
+	// A log that made it to the database before the connection dropped
+	persisted := newTestLog(t)
+	inner.Create(context.Background(), persisted)
+
+	r.degraded.Store(true)
+	r.CreateMany(context.Background(), []*log.Log{persisted, newTestLog(t)})
+
+	if _, err := r.Replay(); err != nil {
+		t.Fatalf("Expected duplicates to be ignored during replay, got %v", err)
+	}
+	if inner.count() != 2 {
+		t.Errorf("Expected 2 distinct stored logs, got %d", inner.count())
+	}
+}
+
+func TestRepository_SpoolFullReturnsError(t *testing.T) {
+	inner := newMockLogRepository()
+	inner.setDown(true)
+
+	s, err := Open(t.TempDir(), Config{MaxBytes: 10})
+	if err != nil {
+		t.Fatalf("Failed to open spool: %v", err)
+	}
+	r := NewRepository(inner, s, time.Hour)
+	defer r.Close()
+
+	err = r.Create(context.Background(), newTestLog(t))
+	if !errors.Is(err, ErrSpoolFull) {
+		t.Errorf("Expected ErrSpoolFull, got %v", err)
+	}
+}
+
+func TestRepository_StartsDegradedWithLeftoverSpool(t *testing.T) {
+	dir := t.TempDir()
+	s, _ := Open(dir, Config{})
+	s.Append([]*log.Log{newTestLog(t)})
+	s.Close()
+
+	reopened, _ := Open(dir, Config{})
+	r := NewRepository(newMockLogRepository(), reopened, time.Hour)
+	defer r.Close()
+
+	if !r.Degraded() {
+		t.Error("Expected repository to start degraded when the spool has leftovers")
+	}
+}
@@ -0,0 +1,298 @@
+package spool
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+)
+
+var ErrSpoolFull = errors.New("spool size limit reached")
+
+const (
+	segmentPrefix = "segment-"
+	segmentSuffix = ".jsonl"
+)
+
+// Config bounds the disk space used by the spool
+type Config struct {
+	MaxBytes     int64
+	SegmentBytes int64
+}
+
+// DefaultConfig returns a 1 GiB spool split into 16 MiB segments
+func DefaultConfig() Config {
+	return Config{
+		MaxBytes:     1 << 30,
+		SegmentBytes: 16 << 20,
+	}
+}
+
+// Stats is a snapshot of the spool's disk usage
+type Stats struct {
+	Segments     int   `json:"segments"`
+	Bytes        int64 `json:"bytes"`
+	MaxBytes     int64 `json:"max_bytes"`
+	CorruptLines int64 `json:"corrupt_lines"`
+}
+
+// Spool is a disk-backed write-ahead log of entries that could not be
+// persisted. Entries are appended as JSON lines to numbered segment files.
+type Spool struct {
+	dir string
+	cfg Config
+
+	mu          sync.Mutex
+	current     *os.File
+	currentSeq  uint64
+	currentSize int64
+	nextSeq     uint64
+	sealed      map[string]int64
+
+	// corrupt counts lines skipped by Read because they could not be decoded
+	corrupt atomic.Int64
+}
+
+// Open creates the spool directory if needed and picks up any segments left by a previous run
+func Open(dir string, cfg Config) (*Spool, error) {
+	defaults := DefaultConfig()
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaults.MaxBytes
+	}
+	if cfg.SegmentBytes <= 0 {
+		cfg.SegmentBytes = defaults.SegmentBytes
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("spool: failed to create directory: %w", err)
+	}
+
+	s := &Spool{dir: dir, cfg: cfg, sealed: make(map[string]int64)}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("spool: failed to read directory: %w", err)
+	}
+	for _, entry := range entries {
+		seq, ok := parseSegmentName(entry.Name())
+		if !ok {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("spool: failed to stat segment: %w", err)
+		}
+		s.sealed[entry.Name()] = info.Size()
+		if seq >= s.nextSeq {
+			s.nextSeq = seq + 1
+		}
+	}
+
+	return s, nil
+}
+
+// Append durably writes the logs to the current segment, rotating when it grows too large
+func (s *Spool) Append(logs []*log.Log) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, l := range logs {
+		if err := encoder.Encode(l); err != nil {
+			return fmt.Errorf("spool: failed to encode log %s: %w", l.ID, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.totalBytes()+int64(buf.Len()) > s.cfg.MaxBytes {
+		return ErrSpoolFull
+	}
+
+	if s.current != nil && s.currentSize+int64(buf.Len()) > s.cfg.SegmentBytes {
+		if err := s.sealCurrent(); err != nil {
+			return err
+		}
+	}
+	if s.current == nil {
+		if err := s.openSegment(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.current.Write(buf.Bytes())
+	s.currentSize += int64(n)
+	if err != nil {
+		return fmt.Errorf("spool: failed to write segment: %w", err)
+	}
+	if err := s.current.Sync(); err != nil {
+		return fmt.Errorf("spool: failed to sync segment: %w", err)
+	}
+	return nil
+}
+
+// Seal closes the segment being written so that everything spooled so far
+// can be replayed, and returns all sealed segment names, oldest first.
+func (s *Spool) Seal() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current != nil {
+		if err := s.sealCurrent(); err != nil {
+			return nil, err
+		}
+	}
+
+	names := make([]string, 0, len(s.sealed))
+	for name := range s.sealed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Read decodes every entry in a sealed segment. Lines that cannot be decoded,
+// such as a torn trailing line left by a crash mid-write, are skipped and
+// counted so that the records after them are still replayed.
+func (s *Spool) Read(name string) ([]*log.Log, error) {
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("spool: failed to open segment: %w", err)
+	}
+	defer f.Close()
+
+	var logs []*log.Log
+	var skipped int64
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), int(s.cfg.SegmentBytes)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var l log.Log
+		if err := json.Unmarshal(line, &l); err != nil {
+			skipped++
+			continue
+		}
+		logs = append(logs, &l)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("spool: failed to read segment: %w", err)
+	}
+	if skipped > 0 {
+		s.corrupt.Add(skipped)
+		slog.Warn("Skipped undecodable spool lines", "segment", name, "lines", skipped)
+	}
+	return logs, nil
+}
+
+// Remove deletes a sealed segment once its entries have been replayed
+func (s *Spool) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("spool: failed to remove segment: %w", err)
+	}
+	delete(s.sealed, name)
+	return nil
+}
+
+// Empty reports whether there is nothing left to replay
+func (s *Spool) Empty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sealed) == 0 && s.currentSize == 0
+}
+
+// Stats returns the number of segments and bytes currently spooled
+func (s *Spool) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	segments := len(s.sealed)
+	if s.current != nil {
+		segments++
+	}
+	return Stats{
+		Segments:     segments,
+		Bytes:        s.totalBytes(),
+		MaxBytes:     s.cfg.MaxBytes,
+		CorruptLines: s.corrupt.Load(),
+	}
+}
+
+// Close closes the segment being written; its content is replayed on the next Open
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current == nil {
+		return nil
+	}
+	return s.sealCurrent()
+}
+
+func (s *Spool) totalBytes() int64 {
+	total := s.currentSize
+	for _, size := range s.sealed {
+		total += size
+	}
+	return total
+}
+
+func (s *Spool) openSegment() error {
+	seq := s.nextSeq
+	f, err := os.OpenFile(filepath.Join(s.dir, segmentName(seq)), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("spool: failed to create segment: %w", err)
+	}
+	s.nextSeq++
+	s.current = f
+	s.currentSeq = seq
+	s.currentSize = 0
+	return nil
+}
+
+func (s *Spool) sealCurrent() error {
+	name := segmentName(s.currentSeq)
+	size := s.currentSize
+	err := s.current.Close()
+	s.current = nil
+	s.currentSize = 0
+
+	if size == 0 {
+		os.Remove(filepath.Join(s.dir, name))
+	} else {
+		s.sealed[name] = size
+	}
+	if err != nil {
+		return fmt.Errorf("spool: failed to close segment: %w", err)
+	}
+	return nil
+}
+
+func segmentName(seq uint64) string {
+	return fmt.Sprintf("%s%020d%s", segmentPrefix, seq, segmentSuffix)
+}
+
+func parseSegmentName(name string) (uint64, bool) {
+	if !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+		return 0, false
+	}
+	var seq uint64
+	if _, err := fmt.Sscanf(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix), "%d", &seq); err != nil {
+		return 0, false
+	}
+	return seq, true
+}
@@ -0,0 +1,200 @@
+package spool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+func newTestLog(t *testing.T) *log.Log {
+	l, err := log.New("Spool test message", valueobjects.LogLevelError, uuid.New(), uuid.New())
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	l.Tags["environment"] = "test"
+	l.Metadata["attempt"] = 2.0
+	return l
+}
+
+func TestSpool_AppendAndRead(t *testing.T) {
+	s, err := Open(t.TempDir(), Config{})
+	if err != nil {
+		t.Fatalf("Failed to open spool: %v", err)
+	}
+
+	original := newTestLog(t)
+	if err := s.Append([]*log.Log{original, newTestLog(t)}); err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+
+	segments, err := s.Seal()
+	if err != nil {
+		t.Fatalf("Failed to seal: %v", err)
+	}
+	if len(segments) != 1 {
+		t.Fatalf("Expected 1 segment, got %d", len(segments))
+	}
+
+	logs, err := s.Read(segments[0])
+	if err != nil {
+		t.Fatalf("Failed to read segment: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("Expected 2 logs, got %d", len(logs))
+	}
+
+	restored := logs[0]
+	if restored.ID != original.ID {
+		t.Errorf("Expected ID %s, got %s", original.ID, restored.ID)
+	}
+	if restored.Message != original.Message || restored.Level != original.Level {
+		t.Errorf("Expected message and level to round-trip, got %+v", restored)
+	}
+	if !restored.Timestamp.Equal(original.Timestamp) {
+		t.Errorf("Expected timestamp %v, got %v", original.Timestamp, restored.Timestamp)
+	}
+	if restored.Tags["environment"] != "test" || restored.Metadata["attempt"] != 2.0 {
+		t.Errorf("Expected tags and metadata to round-trip, got %v %v", restored.Tags, restored.Metadata)
+	}
+
+	if err := s.Remove(segments[0]); err != nil {
+		t.Fatalf("Failed to remove segment: %v", err)
+	}
+	if !s.Empty() {
+		t.Error("Expected spool to be empty after removing the only segment")
+	}
+}
+
+func TestSpool_RotatesSegments(t *testing.T) {
+	s, err := Open(t.TempDir(), Config{SegmentBytes: 512})
+	if err != nil {
+		t.Fatalf("Failed to open spool: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := s.Append([]*log.Log{newTestLog(t)}); err != nil {
+			t.Fatalf("Failed to append: %v", err)
+		}
+	}
+
+	segments, _ := s.Seal()
+	if len(segments) < 2 {
+		t.Errorf("Expected multiple segments, got %d", len(segments))
+	}
+
+	total := 0
+	for _, name := range segments {
+		logs, err := s.Read(name)
+		if err != nil {
+			t.Fatalf("Failed to read segment %s: %v", name, err)
+		}
+		total += len(logs)
+	}
+	if total != 10 {
+		t.Errorf("Expected 10 logs across segments, got %d", total)
+	}
+}
+
+func TestSpool_MaxBytes(t *testing.T) {
+	s, err := Open(t.TempDir(), Config{MaxBytes: 600})
+	if err != nil {
+		t.Fatalf("Failed to open spool: %v", err)
+	}
+
+	var appendErr error
+	for i := 0; i < 10 && appendErr == nil; i++ {
+		appendErr = s.Append([]*log.Log{newTestLog(t)})
+	}
+
+	if appendErr != ErrSpoolFull {
+		t.Errorf("Expected ErrSpoolFull, got %v", appendErr)
+	}
+	if stats := s.Stats(); stats.Bytes > 600 {
+		t.Errorf("Expected spool to stay within 600 bytes, got %d", stats.Bytes)
+	}
+}
+
+func TestSpool_ReopenPicksUpSegments(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, Config{})
+	if err != nil {
+		t.Fatalf("Failed to open spool: %v", err)
+	}
+	s.Append([]*log.Log{newTestLog(t)})
+	s.Close()
+
+	reopened, err := Open(dir, Config{})
+	if err != nil {
+		t.Fatalf("Failed to reopen spool: %v", err)
+	}
+	if reopened.Empty() {
+		t.Fatal("Expected reopened spool to contain the previous segment")
+	}
+
+	// New segments must not overwrite the existing one
+	reopened.Append([]*log.Log{newTestLog(t)})
+	segments, _ := reopened.Seal()
+	if len(segments) != 2 {
+		t.Errorf("Expected 2 segments after reopen, got %d", len(segments))
+	}
+}
+
+func TestSpool_IgnoresTornTrailingLine(t *testing.T) {
+	dir := t.TempDir()
+	s, _ := Open(dir, Config{})
+	s.Append([]*log.Log{newTestLog(t)})
+	segments, _ := s.Seal()
+
+	f, err := os.OpenFile(filepath.Join(dir, segments[0]), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("Failed to open segment: %v", err)
+	}
+	f.WriteString(`{"id":"truncat`)
+	f.Close()
+
+	logs, err := s.Read(segments[0])
+	if err != nil {
+		t.Fatalf("Unexpected error reading torn segment: %v", err)
+	}
+	if len(logs) != 1 {
+		t.Errorf("Expected 1 complete log, got %d", len(logs))
+	}
+}
+
+func TestSpool_SkipsCorruptLineAndKeepsLaterRecords(t *testing.T) {
+	dir := t.TempDir()
+	s, _ := Open(dir, Config{})
+	s.Append([]*log.Log{newTestLog(t)})
+	segments, _ := s.Seal()
+
+	f, err := os.OpenFile(filepath.Join(dir, segments[0]), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("Failed to open segment: %v", err)
+	}
+	f.WriteString("not json\n")
+	f.Close()
+
+	// A valid record following the corrupt line must still be replayed
+	later, _ := Open(t.TempDir(), Config{})
+	later.Append([]*log.Log{newTestLog(t)})
+	laterSegments, _ := later.Seal()
+	data, _ := os.ReadFile(filepath.Join(later.dir, laterSegments[0]))
+	f, _ = os.OpenFile(filepath.Join(dir, segments[0]), os.O_APPEND|os.O_WRONLY, 0o644)
+	f.Write(data)
+	f.Close()
+
+	logs, err := s.Read(segments[0])
+	if err != nil {
+		t.Fatalf("Unexpected error reading corrupt segment: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Errorf("Expected both valid logs around the corrupt line, got %d", len(logs))
+	}
+	if got := s.Stats().CorruptLines; got != 1 {
+		t.Errorf("Expected 1 corrupt line counted, got %d", got)
+	}
+}
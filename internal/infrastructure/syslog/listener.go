@@ -0,0 +1,258 @@
+package syslog
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	usecase "github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/infrastructure/netutil"
+)
+
+const (
+	// maxMessageSize bounds a single message; larger UDP datagrams are truncated
+	// and larger TCP frames close the connection
+	maxMessageSize = 64 * 1024
+	createTimeout  = 10 * time.Second
+	tcpIdleTimeout = 5 * time.Minute
+)
+
+var ErrNoApplication = errors.New("syslog: no application configured for sender")
+
+// Config describes one listener. Messages are routed to the application
+// configured for the sender's IP address or hostname, falling back to
+// ApplicationID.
+type Config struct {
+	Network       string      // "udp" or "tcp"
+	Address       string      // e.g. ":514"
+	TLS           *tls.Config // Optional: serves TCP connections over TLS
+	ApplicationID uuid.UUID
+	UserID        uuid.UUID
+	Senders       map[string]uuid.UUID // Optional: sender IP or hostname -> application ID
+}
+
+// Listener receives syslog messages and creates a log for each one
+type Listener struct {
+	cfg     Config
+	usecase usecase.LogUsecaseInterface
+	now     func() time.Time
+	server  *netutil.Server
+
+	packet net.PacketConn
+	stream net.Listener
+}
+
+func NewListener(cfg Config, uc usecase.LogUsecaseInterface) *Listener {
+	return &Listener{
+		cfg:     cfg,
+		usecase: uc,
+		now:     time.Now,
+		server:  netutil.NewServer("Syslog " + strings.ToUpper(cfg.Network)),
+	}
+}
+
+// Listen binds the configured address and serves it in the background
+func (l *Listener) Listen() error {
+	switch l.cfg.Network {
+	case "udp":
+		conn, err := net.ListenPacket("udp", l.cfg.Address)
+		if err != nil {
+			return fmt.Errorf("syslog: failed to listen on udp %s: %w", l.cfg.Address, err)
+		}
+		l.packet = conn
+		l.server.Track(conn)
+		l.server.Go(l.serveUDP)
+	case "tcp":
+		listener, err := net.Listen("tcp", l.cfg.Address)
+		if err != nil {
+			return fmt.Errorf("syslog: failed to listen on tcp %s: %w", l.cfg.Address, err)
+		}
+		if l.cfg.TLS != nil {
+			listener = tls.NewListener(listener, l.cfg.TLS)
+		}
+		l.stream = listener
+		l.server.Serve(listener, tcpIdleTimeout, l.serveConn)
+	default:
+		return fmt.Errorf("syslog: unsupported network %q", l.cfg.Network)
+	}
+	return nil
+}
+
+// Addr returns the bound address, or nil before Listen
+func (l *Listener) Addr() net.Addr {
+	if l.packet != nil {
+		return l.packet.LocalAddr()
+	}
+	if l.stream != nil {
+		return l.stream.Addr()
+	}
+	return nil
+}
+
+// Close stops accepting messages and waits for open connections to finish
+func (l *Listener) Close() error {
+	return l.server.Close()
+}
+
+func (l *Listener) serveUDP() {
+	buf := make([]byte, maxMessageSize)
+	for {
+		n, addr, err := l.packet.ReadFrom(buf)
+		if err != nil {
+			if !l.server.Closed() {
+				slog.Error("Syslog UDP read failed", "error", err)
+			}
+			return
+		}
+		l.handle(buf[:n], hostOf(addr))
+	}
+}
+
+func (l *Listener) serveConn(conn net.Conn) {
+	sender := hostOf(conn.RemoteAddr())
+	reader := bufio.NewReaderSize(conn, maxMessageSize)
+	for {
+		frame, err := readFrame(reader)
+		if len(frame) > 0 {
+			l.handle(frame, sender)
+		}
+		if err != nil {
+			if err != io.EOF && !l.server.Closed() {
+				slog.Warn("Syslog connection closed", "sender", sender, "error", err)
+			}
+			return
+		}
+	}
+}
+
+// readFrame reads one message using octet counting ("<len> <msg>") when the
+// frame starts with a digit, and newline-delimited framing otherwise (RFC 6587)
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	first, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if first[0] >= '0' && first[0] <= '9' {
+		prefix, err := r.ReadSlice(' ')
+		if err != nil {
+			return nil, err
+		}
+		length, err := strconv.Atoi(string(prefix[:len(prefix)-1]))
+		if err != nil || length <= 0 || length > maxMessageSize {
+			return nil, fmt.Errorf("invalid frame length %q", prefix)
+		}
+		frame := make([]byte, length)
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return nil, err
+		}
+		return frame, nil
+	}
+
+	line, err := r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("frame exceeds %d bytes", maxMessageSize)
+	}
+	return append([]byte(nil), line...), err
+}
+
+func (l *Listener) handle(data []byte, sender string) {
+	msg, err := Parse(data, l.now())
+	if errors.Is(err, ErrEmptyMessage) {
+		return
+	}
+	if err != nil {
+		slog.Warn("Syslog message dropped", "sender", sender, "error", err)
+		return
+	}
+
+	input, err := l.toInput(msg, sender)
+	if err != nil {
+		slog.Warn("Syslog message dropped", "sender", sender, "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), createTimeout)
+	defer cancel()
+	if _, err := l.usecase.CreateLog(ctx, input); err != nil {
+		slog.Warn("Syslog message dropped", "sender", sender, "error", err)
+	}
+}
+
+// toInput maps hostname and app-name to Source, structured data to Tags
+// ("<sd-id>/<param>") and the remaining header fields to Metadata
+func (l *Listener) toInput(msg *Message, sender string) (dto.CreateLogInput, error) {
+	applicationID, ok := l.route(sender, msg.Hostname)
+	if !ok {
+		return dto.CreateLogInput{}, fmt.Errorf("%w %s", ErrNoApplication, sender)
+	}
+
+	input := dto.CreateLogInput{
+		ApplicationID: applicationID,
+		UserID:        l.cfg.UserID,
+		Message:       msg.Text,
+		Level:         msg.Level().String(),
+		Metadata: map[string]interface{}{
+			"syslog_facility": msg.Facility,
+			"syslog_severity": msg.Severity,
+		},
+	}
+
+	var source []string
+	for _, part := range []string{msg.Hostname, msg.AppName} {
+		if part != "" {
+			source = append(source, part)
+		}
+	}
+	input.Source = strings.Join(source, "/")
+
+	if msg.ProcID != "" {
+		input.Metadata["proc_id"] = msg.ProcID
+	}
+	if msg.MsgID != "" {
+		input.Metadata["msg_id"] = msg.MsgID
+	}
+	if len(msg.StructuredData) > 0 {
+		input.Tags = make(map[string]string)
+		for id, params := range msg.StructuredData {
+			for name, value := range params {
+				input.Tags[id+"/"+name] = value
+			}
+		}
+	}
+	if !msg.Timestamp.IsZero() {
+		timestamp := msg.Timestamp
+		input.Timestamp = &timestamp
+	}
+
+	return input, nil
+}
+
+// route prefers the sender's address, then the hostname in the message
+func (l *Listener) route(sender, hostname string) (uuid.UUID, bool) {
+	if id, ok := l.cfg.Senders[sender]; ok {
+		return id, true
+	}
+	if id, ok := l.cfg.Senders[hostname]; ok && hostname != "" {
+		return id, true
+	}
+	return l.cfg.ApplicationID, l.cfg.ApplicationID != uuid.Nil
+}
+
+func hostOf(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
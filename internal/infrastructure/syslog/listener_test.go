@@ -0,0 +1,148 @@
+package syslog
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/application/log/dto"
+)
+
+type mockLogUsecase struct {
+	mu     sync.Mutex
+	inputs []dto.CreateLogInput
+}
+
+func (m *mockLogUsecase) CreateLog(ctx context.Context, input dto.CreateLogInput) (*dto.CreateLogOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inputs = append(m.inputs, input)
+	return &dto.CreateLogOutput{}, nil
+}
+
+func (m *mockLogUsecase) ListLogs(ctx context.Context, input dto.ListLogsInput) (*dto.ListLogsOutput, error) {
+	return nil, nil
+}
+
+func (m *mockLogUsecase) waitFor(t *testing.T, n int) []dto.CreateLogInput {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		m.mu.Lock()
+		if len(m.inputs) >= n {
+			inputs := append([]dto.CreateLogInput(nil), m.inputs...)
+			m.mu.Unlock()
+			return inputs
+		}
+		m.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Expected %d logs to be created", n)
+	return nil
+}
+
+func startListener(t *testing.T, cfg Config, uc *mockLogUsecase) *Listener {
+	cfg.Address = "127.0.0.1:0"
+	listener := NewListener(cfg, uc)
+	if err := listener.Listen(); err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	return listener
+}
+
+func TestListener_UDP(t *testing.T) {
+	applicationID := uuid.New()
+	userID := uuid.New()
+	uc := &mockLogUsecase{}
+	listener := startListener(t, Config{Network: "udp", ApplicationID: applicationID, UserID: userID}, uc)
+
+	conn, err := net.Dial("udp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close()
+	conn.Write([]byte(`<163>1 2025-10-28T10:00:00.123456789Z web01 nginx 42 - [req@1 path="/login"] upstream timed out`))
+
+	input := uc.waitFor(t, 1)[0]
+	if input.ApplicationID != applicationID || input.UserID != userID {
+		t.Errorf("Expected listener application and user, got %s and %s", input.ApplicationID, input.UserID)
+	}
+	if input.Level != "ERROR" || input.Message != "upstream timed out" || input.Source != "web01/nginx" {
+		t.Errorf("Unexpected mapped input: %+v", input)
+	}
+	if input.Tags["req@1/path"] != "/login" {
+		t.Errorf("Expected structured data in tags, got %v", input.Tags)
+	}
+	if input.Metadata["proc_id"] != "42" || input.Metadata["syslog_facility"] != 20 {
+		t.Errorf("Unexpected metadata: %v", input.Metadata)
+	}
+	if input.Timestamp == nil || input.Timestamp.Nanosecond() != 123456789 {
+		t.Errorf("Expected event timestamp with nanoseconds, got %v", input.Timestamp)
+	}
+}
+
+func TestListener_TCPFraming(t *testing.T) {
+	uc := &mockLogUsecase{}
+	listener := startListener(t, Config{Network: "tcp", ApplicationID: uuid.New(), UserID: uuid.New()}, uc)
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	octetCounted := "<14>1 - host app - - - first"
+	fmt.Fprintf(conn, "%d %s", len(octetCounted), octetCounted)
+	fmt.Fprint(conn, "<12>Oct 11 22:14:15 host app: second\n")
+
+	inputs := uc.waitFor(t, 2)
+	if inputs[0].Message != "first" || inputs[1].Message != "second" {
+		t.Errorf("Expected both frames in order, got %q and %q", inputs[0].Message, inputs[1].Message)
+	}
+	if inputs[1].Level != "WARN" {
+		t.Errorf("Expected WARN for severity 4, got %s", inputs[1].Level)
+	}
+}
+
+func TestListener_RoutesBySender(t *testing.T) {
+	defaultID := uuid.New()
+	byAddress := uuid.New()
+	byHostname := uuid.New()
+
+	l := NewListener(Config{
+		ApplicationID: defaultID,
+		Senders:       map[string]uuid.UUID{"10.0.0.5": byAddress, "router-1": byHostname},
+	}, &mockLogUsecase{})
+
+	tests := []struct {
+		name     string
+		sender   string
+		hostname string
+		expected uuid.UUID
+	}{
+		{name: "Sender address", sender: "10.0.0.5", hostname: "router-1", expected: byAddress},
+		{name: "Message hostname", sender: "10.0.0.9", hostname: "router-1", expected: byHostname},
+		{name: "Listener default", sender: "10.0.0.9", hostname: "other", expected: defaultID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := l.toInput(&Message{Hostname: tt.hostname, Text: "routed"}, tt.sender)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if input.ApplicationID != tt.expected {
+				t.Errorf("Expected application %s, got %s", tt.expected, input.ApplicationID)
+			}
+		})
+	}
+
+	unrouted := NewListener(Config{}, &mockLogUsecase{})
+	if _, err := unrouted.toInput(&Message{Text: "lost"}, "10.0.0.9"); err == nil {
+		t.Error("Expected error for a sender without an application")
+	}
+}
@@ -0,0 +1,239 @@
+package syslog
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+var (
+	ErrEmptyMessage  = errors.New("syslog: empty message")
+	ErrInvalidPri    = errors.New("syslog: invalid PRI")
+	ErrInvalidHeader = errors.New("syslog: malformed header")
+	ErrInvalidSD     = errors.New("syslog: malformed structured data")
+)
+
+const (
+	nilValue = "-"
+
+	// defaultPri is user.notice, assumed by RFC 3164 when a message has no PRI
+	defaultPri = 13
+)
+
+// Message is a parsed syslog message. Fields missing from the wire format
+// (or sent as the NILVALUE) are left empty.
+type Message struct {
+	Facility       int
+	Severity       int
+	Timestamp      time.Time
+	Hostname       string
+	AppName        string
+	ProcID         string
+	MsgID          string
+	StructuredData map[string]map[string]string
+	Text           string
+}
+
+// Level maps the syslog severity to a log level
+func (m *Message) Level() valueobjects.LogLevel {
+	return valueobjects.LogLevelFromSyslogSeverity(m.Severity)
+}
+
+// Parse decodes an RFC 5424 message, falling back to the BSD format of RFC 3164.
+// now is used to complete RFC 3164 timestamps, which carry no year or zone.
+func Parse(data []byte, now time.Time) (*Message, error) {
+	raw := strings.TrimRight(string(data), "\r\n\x00")
+	if strings.TrimSpace(raw) == "" {
+		return nil, ErrEmptyMessage
+	}
+
+	pri, rest, err := parsePri(raw)
+	if err != nil {
+		return nil, err
+	}
+	msg := &Message{Facility: pri / 8, Severity: pri % 8}
+
+	if after, ok := strings.CutPrefix(rest, "1 "); ok {
+		if err := parse5424(msg, after); err != nil {
+			return nil, err
+		}
+		return msg, nil
+	}
+
+	parse3164(msg, rest, now)
+	return msg, nil
+}
+
+func parsePri(raw string) (int, string, error) {
+	if raw[0] != '<' {
+		return defaultPri, raw, nil
+	}
+	end := strings.IndexByte(raw, '>')
+	if end < 2 || end > 4 {
+		return 0, "", ErrInvalidPri
+	}
+	pri, err := strconv.Atoi(raw[1:end])
+	if err != nil || pri > 191 {
+		return 0, "", ErrInvalidPri
+	}
+	return pri, raw[end+1:], nil
+}
+
+// parse5424 reads TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA [MSG]
+func parse5424(msg *Message, rest string) error {
+	fields := make([]string, 5)
+	for i := range fields {
+		field, after, ok := strings.Cut(rest, " ")
+		if !ok || field == "" {
+			return ErrInvalidHeader
+		}
+		fields[i] = field
+		rest = after
+	}
+
+	if fields[0] != nilValue {
+		ts, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+		}
+		msg.Timestamp = ts
+	}
+	msg.Hostname = nilToEmpty(fields[1])
+	msg.AppName = nilToEmpty(fields[2])
+	msg.ProcID = nilToEmpty(fields[3])
+	msg.MsgID = nilToEmpty(fields[4])
+
+	sd, rest, err := parseStructuredData(rest)
+	if err != nil {
+		return err
+	}
+	msg.StructuredData = sd
+
+	rest = strings.TrimPrefix(rest, " ")
+	msg.Text = strings.TrimPrefix(rest, "\ufeff")
+	return nil
+}
+
+// parseStructuredData reads either the NILVALUE or a sequence of SD-ELEMENTs
+// such as [exampleSDID@32473 iut="3" eventSource="Application"]
+func parseStructuredData(s string) (map[string]map[string]string, string, error) {
+	if strings.HasPrefix(s, nilValue) {
+		return nil, s[len(nilValue):], nil
+	}
+	if !strings.HasPrefix(s, "[") {
+		return nil, "", ErrInvalidSD
+	}
+
+	elements := make(map[string]map[string]string)
+	for strings.HasPrefix(s, "[") {
+		s = s[1:]
+		end := strings.IndexAny(s, " ]")
+		if end <= 0 {
+			return nil, "", ErrInvalidSD
+		}
+		id := s[:end]
+		params := make(map[string]string)
+		s = s[end:]
+
+		for strings.HasPrefix(s, " ") {
+			s = s[1:]
+			name, after, ok := strings.Cut(s, "=\"")
+			if !ok || name == "" {
+				return nil, "", ErrInvalidSD
+			}
+			value, remaining, err := readParamValue(after)
+			if err != nil {
+				return nil, "", err
+			}
+			params[name] = value
+			s = remaining
+		}
+
+		if !strings.HasPrefix(s, "]") {
+			return nil, "", ErrInvalidSD
+		}
+		s = s[1:]
+		elements[id] = params
+	}
+
+	return elements, s, nil
+}
+
+// readParamValue reads up to the closing quote, unescaping \", \\ and \]
+func readParamValue(s string) (string, string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\' || s[i+1] == ']') {
+				i++
+			}
+			b.WriteByte(s[i])
+		case '"':
+			return b.String(), s[i+1:], nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", "", ErrInvalidSD
+}
+
+// parse3164 reads "Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG". The format was
+// never strictly followed, so anything unrecognised is kept as message text.
+func parse3164(msg *Message, rest string, now time.Time) {
+	const stampLen = len(time.Stamp)
+	if len(rest) > stampLen && rest[stampLen] == ' ' {
+		if ts, err := time.ParseInLocation(time.Stamp, rest[:stampLen], now.Location()); err == nil {
+			ts = ts.AddDate(now.Year(), 0, 0)
+			// A timestamp far ahead of now was sent in December and received in January
+			if ts.After(now.AddDate(0, 0, 1)) {
+				ts = ts.AddDate(-1, 0, 0)
+			}
+			msg.Timestamp = ts
+			rest = rest[stampLen+1:]
+
+			if host, after, ok := strings.Cut(rest, " "); ok && host != "" && !isTag(host) {
+				msg.Hostname = host
+				rest = after
+			}
+		}
+	}
+
+	if tag, after, ok := strings.Cut(rest, ": "); ok && isTag(tag+":") {
+		if name, pid, ok := strings.Cut(tag, "["); ok {
+			msg.AppName = name
+			msg.ProcID = strings.TrimSuffix(pid, "]")
+		} else {
+			msg.AppName = tag
+		}
+		rest = after
+	}
+
+	msg.Text = rest
+}
+
+// isTag reports whether s looks like "name:" or "name[pid]:"
+func isTag(s string) bool {
+	if !strings.HasSuffix(s, ":") || len(s) < 2 || len(s) > 48 {
+		return false
+	}
+	name := strings.TrimSuffix(s, ":")
+	if open := strings.IndexByte(name, '['); open >= 0 {
+		if !strings.HasSuffix(name, "]") {
+			return false
+		}
+		name = name[:open]
+	}
+	return name != "" && !strings.ContainsAny(name, " \t")
+}
+
+func nilToEmpty(s string) string {
+	if s == nilValue {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,152 @@
+package syslog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rubensantoniorosa2704/LoggingSSE/internal/domain/valueobjects"
+)
+
+func TestParse_RFC5424(t *testing.T) {
+	raw := `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog 1234 ID47 [exampleSDID@32473 iut="3" eventSource="App\"lication\]"][origin ip="10.0.0.5"] ` + "\ufeff" + `An application event log entry`
+
+	msg, err := Parse([]byte(raw), time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if msg.Facility != 20 || msg.Severity != 5 {
+		t.Errorf("Expected facility 20 and severity 5, got %d and %d", msg.Facility, msg.Severity)
+	}
+	expectedTime := time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC)
+	if !msg.Timestamp.Equal(expectedTime) {
+		t.Errorf("Expected timestamp %v, got %v", expectedTime, msg.Timestamp)
+	}
+	if msg.Hostname != "mymachine.example.com" || msg.AppName != "evntslog" || msg.ProcID != "1234" || msg.MsgID != "ID47" {
+		t.Errorf("Unexpected header fields: %+v", msg)
+	}
+	if msg.StructuredData["exampleSDID@32473"]["eventSource"] != `App"lication]` {
+		t.Errorf("Expected escaped structured data value, got %q", msg.StructuredData["exampleSDID@32473"]["eventSource"])
+	}
+	if msg.StructuredData["origin"]["ip"] != "10.0.0.5" {
+		t.Errorf("Expected second SD element, got %v", msg.StructuredData)
+	}
+	if msg.Text != "An application event log entry" {
+		t.Errorf("Expected message text without BOM, got %q", msg.Text)
+	}
+}
+
+func TestParse_RFC5424NilValues(t *testing.T) {
+	msg, err := Parse([]byte("<11>1 - - - - - -"), time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !msg.Timestamp.IsZero() || msg.Hostname != "" || msg.AppName != "" || msg.StructuredData != nil || msg.Text != "" {
+		t.Errorf("Expected empty fields for NILVALUE, got %+v", msg)
+	}
+}
+
+func TestParse_RFC3164(t *testing.T) {
+	now := time.Date(2025, 10, 28, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		raw          string
+		expectedHost string
+		expectedApp  string
+		expectedPID  string
+		expectedText string
+		expectedTime time.Time
+	}{
+		{
+			name:         "Full header",
+			raw:          "<34>Oct 11 22:14:15 mymachine su[231]: 'su root' failed for lonvick on /dev/pts/8\n",
+			expectedHost: "mymachine",
+			expectedApp:  "su",
+			expectedPID:  "231",
+			expectedText: "'su root' failed for lonvick on /dev/pts/8",
+			expectedTime: time.Date(2025, 10, 11, 22, 14, 15, 0, time.UTC),
+		},
+		{
+			name:         "Missing hostname",
+			raw:          "<13>Oct  1 09:00:00 cron: job started",
+			expectedApp:  "cron",
+			expectedText: "job started",
+			expectedTime: time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Previous year",
+			raw:          "<13>Dec 31 23:59:59 host app: late",
+			expectedHost: "host",
+			expectedApp:  "app",
+			expectedText: "late",
+			expectedTime: time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name:         "No header",
+			raw:          "just some text",
+			expectedText: "just some text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := Parse([]byte(tt.raw), now)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if msg.Hostname != tt.expectedHost || msg.AppName != tt.expectedApp || msg.ProcID != tt.expectedPID {
+				t.Errorf("Expected host %q app %q pid %q, got %q %q %q", tt.expectedHost, tt.expectedApp, tt.expectedPID, msg.Hostname, msg.AppName, msg.ProcID)
+			}
+			if msg.Text != tt.expectedText {
+				t.Errorf("Expected text %q, got %q", tt.expectedText, msg.Text)
+			}
+			if !msg.Timestamp.Equal(tt.expectedTime) {
+				t.Errorf("Expected timestamp %v, got %v", tt.expectedTime, msg.Timestamp)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		expectedError error
+	}{
+		{name: "Empty", raw: "\n", expectedError: ErrEmptyMessage},
+		{name: "Unterminated PRI", raw: "<34 hello", expectedError: ErrInvalidPri},
+		{name: "PRI out of range", raw: "<192>hello", expectedError: ErrInvalidPri},
+		{name: "Truncated header", raw: "<34>1 2003-10-11T22:14:15Z host", expectedError: ErrInvalidHeader},
+		{name: "Unterminated SD", raw: `<34>1 - host app - - [id a="b" hello`, expectedError: ErrInvalidSD},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.raw), time.Now())
+			if err != tt.expectedError {
+				t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestMessage_Level(t *testing.T) {
+	expected := []valueobjects.LogLevel{
+		valueobjects.LogLevelFatal, // emergency
+		valueobjects.LogLevelFatal, // alert
+		valueobjects.LogLevelFatal, // critical
+		valueobjects.LogLevelError,
+		valueobjects.LogLevelWarn,
+		valueobjects.LogLevelInfo, // notice
+		valueobjects.LogLevelInfo,
+		valueobjects.LogLevelDebug,
+	}
+
+	for severity, level := range expected {
+		msg := &Message{Severity: severity}
+		if msg.Level() != level {
+			t.Errorf("Expected severity %d to map to %s, got %s", severity, level, msg.Level())
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Package tlscert serves TLS certificates from files that may be replaced
+// while the server is running, such as certificates renewed by cert-manager
+// or an ACME client.
+package tlscert
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Store holds a certificate and key pair loaded from disk. Handshakes use the
+// pair loaded last, so a renewal takes effect without dropping connections.
+type Store struct {
+	certFile string
+	keyFile  string
+
+	cert atomic.Pointer[tls.Certificate]
+
+	mu       sync.Mutex
+	modified [2]time.Time
+}
+
+// Load reads the key pair, failing when it cannot be used
+func Load(certFile, keyFile string) (*Store, error) {
+	s := &Store{certFile: certFile, keyFile: keyFile}
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetCertificate is used as tls.Config.GetCertificate
+func (s *Store) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return s.cert.Load(), nil
+}
+
+// Reload reads the key pair again if either file changed since it was last
+// loaded, and reports whether it did. The previous pair is kept when the new
+// one is invalid, for instance while only one of the files has been replaced.
+func (s *Store) Reload() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	modified, err := modTimes(s.certFile, s.keyFile)
+	if err != nil {
+		return false, err
+	}
+	if s.cert.Load() != nil && modified == s.modified {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(s.certFile, s.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	s.cert.Store(&cert)
+	s.modified = modified
+	return true, nil
+}
+
+// Watch polls the files every interval until ctx is done, logging reloads
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.ReloadNow()
+		}
+	}
+}
+
+// ReloadNow reloads the pair if it changed and logs the outcome, as Watch
+// does on every tick; it is used on SIGHUP
+func (s *Store) ReloadNow() {
+	reloaded, err := s.Reload()
+	switch {
+	case err != nil:
+		slog.Error("Keeping the current TLS certificate", "error", err)
+	case reloaded:
+		slog.Info("Reloaded TLS certificate", "file", s.certFile, "not_after", notAfter(s.cert.Load()))
+	}
+}
+
+func modTimes(certFile, keyFile string) ([2]time.Time, error) {
+	var modified [2]time.Time
+	for i, file := range []string{certFile, keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return modified, fmt.Errorf("failed to read TLS certificate: %w", err)
+		}
+		modified[i] = info.ModTime()
+	}
+	return modified, nil
+}
+
+func notAfter(cert *tls.Certificate) time.Time {
+	if cert == nil || cert.Leaf == nil {
+		return time.Time{}
+	}
+	return cert.Leaf.NotAfter
+}
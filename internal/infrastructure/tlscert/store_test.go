@@ -0,0 +1,119 @@
+package tlscert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeKeyPair writes a self-signed certificate for commonName, with a
+// modification time in the future so that rewrites are always detected
+func writeKeyPair(t *testing.T, certFile, keyFile, commonName string, modified time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	for file, block := range map[string]*pem.Block{
+		certFile: {Type: "CERTIFICATE", Bytes: der},
+		keyFile:  {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := os.WriteFile(file, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", file, err)
+		}
+		if err := os.Chtimes(file, modified, modified); err != nil {
+			t.Fatalf("Failed to set modification time: %v", err)
+		}
+	}
+}
+
+func servedName(t *testing.T, s *Store) string {
+	t.Helper()
+	cert, err := s.GetCertificate(nil)
+	if err != nil || cert == nil || cert.Leaf == nil {
+		t.Fatalf("Expected a certificate, got %v, %v", cert, err)
+	}
+	return cert.Leaf.Subject.CommonName
+}
+
+func TestStore_Reload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	start := time.Now()
+	writeKeyPair(t, certFile, keyFile, "first", start)
+
+	s, err := Load(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if name := servedName(t, s); name != "first" {
+		t.Errorf("Expected the first certificate, got %s", name)
+	}
+
+	if reloaded, err := s.Reload(); reloaded || err != nil {
+		t.Errorf("Expected unchanged files not to be reloaded, got %v, %v", reloaded, err)
+	}
+
+	writeKeyPair(t, certFile, keyFile, "renewed", start.Add(time.Minute))
+	if reloaded, err := s.Reload(); !reloaded || err != nil {
+		t.Fatalf("Expected the renewed certificate to be loaded, got %v, %v", reloaded, err)
+	}
+	if name := servedName(t, s); name != "renewed" {
+		t.Errorf("Expected the renewed certificate, got %s", name)
+	}
+}
+
+func TestStore_KeepsCertificateOnInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	start := time.Now()
+	writeKeyPair(t, certFile, keyFile, "first", start)
+
+	s, err := Load(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Only the certificate has been replaced so far, so it does not match the key
+	otherDir := t.TempDir()
+	writeKeyPair(t, filepath.Join(otherDir, "tls.crt"), filepath.Join(otherDir, "tls.key"), "renewed", start)
+	renewed, _ := os.ReadFile(filepath.Join(otherDir, "tls.crt"))
+	os.WriteFile(certFile, renewed, 0o600)
+	os.Chtimes(certFile, start.Add(time.Minute), start.Add(time.Minute))
+
+	if _, err := s.Reload(); err == nil {
+		t.Error("Expected a mismatched key pair to be refused")
+	}
+	if name := servedName(t, s); name != "first" {
+		t.Errorf("Expected the previous certificate to be kept, got %s", name)
+	}
+}
+
+func TestLoad_MissingFiles(t *testing.T) {
+	if _, err := Load("missing.crt", "missing.key"); err == nil {
+		t.Error("Expected an error for missing files")
+	}
+}
@@ -0,0 +1,472 @@
+// Package client sends logs to the LoggingSSE service. Entries are buffered
+// in memory and delivered in batches by a background goroutine; failed
+// deliveries are retried with exponential backoff and, when a spool
+// directory is configured, written to disk and resent once the service is
+// reachable again.
+//
+//	c, err := client.New(client.Config{URL: "http://localhost:8080", ApplicationID: appID, UserID: userID})
+//	defer c.Close(context.Background())
+//	slog.SetDefault(slog.New(client.NewHandler(c, nil)))
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// batchPath is the service endpoint accepting up to 1000 logs per request
+const batchPath = "/api/v1/logs/batch"
+
+// Levels accepted by the service
+const (
+	LevelTrace = "TRACE"
+	LevelDebug = "DEBUG"
+	LevelInfo  = "INFO"
+	LevelWarn  = "WARN"
+	LevelError = "ERROR"
+	LevelFatal = "FATAL"
+)
+
+var (
+	// ErrBufferFull is returned by Log when the buffer is full and the entry could not be spooled
+	ErrBufferFull = errors.New("client: buffer full, log dropped")
+	// ErrClosed is returned once Close was called
+	ErrClosed = errors.New("client: closed")
+	// ErrRejected reports logs the service refused in a way that retrying cannot fix
+	ErrRejected = errors.New("client: log rejected")
+)
+
+// Config describes the service and how logs are batched
+type Config struct {
+	URL           string // Base URL of the service, such as http://localhost:8080
+	ApplicationID uuid.UUID
+	UserID        uuid.UUID
+	Source        string // Source of entries that do not set one
+
+	BatchSize     int           // Logs per request, at most 1000; default 100
+	FlushInterval time.Duration // Maximum time a log waits for its batch to fill; default 1s
+	BufferSize    int           // Logs held in memory before Log spools or drops them; default 10000
+
+	MaxRetries int           // Attempts after the first before a batch is spooled or dropped; default 5
+	MinBackoff time.Duration // Default 200ms
+	MaxBackoff time.Duration // Default 10s
+	Timeout    time.Duration // Per request; default 10s
+
+	// SpoolDir enables a disk spool for logs that overflow the buffer or run
+	// out of retries; they are resent every FlushInterval
+	SpoolDir      string
+	SpoolMaxBytes int64 // Default 100 MiB
+
+	HTTPClient *http.Client
+	// OnError is called from the background goroutine when logs are dropped
+	OnError func(error)
+}
+
+// DefaultConfig returns the batching, retry and spool defaults
+func DefaultConfig() Config {
+	return Config{
+		BatchSize:     100,
+		FlushInterval: time.Second,
+		BufferSize:    10000,
+		MaxRetries:    5,
+		MinBackoff:    200 * time.Millisecond,
+		MaxBackoff:    10 * time.Second,
+		Timeout:       10 * time.Second,
+		SpoolMaxBytes: 100 << 20,
+	}
+}
+
+// Entry is a log to send. A zero ID and Timestamp are filled in by Log; the
+// ID makes retries safe because the service stores each ID once.
+type Entry struct {
+	ID        uuid.UUID
+	Message   string
+	Level     string
+	Source    string
+	Tags      map[string]string
+	Metadata  map[string]interface{}
+	Timestamp time.Time
+}
+
+// Stats counts logs by outcome
+type Stats struct {
+	Sent    uint64 `json:"sent"`
+	Dropped uint64 `json:"dropped"`
+	Spooled uint64 `json:"spooled"`
+}
+
+// createLogInput is the JSON body of one log, as accepted by the service
+type createLogInput struct {
+	ID            uuid.UUID              `json:"id"`
+	ApplicationID uuid.UUID              `json:"application_id"`
+	UserID        uuid.UUID              `json:"user_id"`
+	Message       string                 `json:"message"`
+	Level         string                 `json:"level"`
+	Source        string                 `json:"source,omitempty"`
+	Tags          map[string]string      `json:"tags,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Timestamp     time.Time              `json:"timestamp"`
+}
+
+type batchOutput struct {
+	Accepted int `json:"accepted"`
+	Errors   []struct {
+		Index  int    `json:"index"`
+		Status int    `json:"status"`
+		Error  string `json:"error"`
+	} `json:"errors"`
+}
+
+// Client buffers logs and delivers them in the background
+type Client struct {
+	cfg   Config
+	http  *http.Client
+	spool *spool
+
+	mu      sync.RWMutex
+	closed  bool
+	entries chan createLogInput
+	flushes chan chan struct{}
+	done    chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	sent    atomic.Uint64
+	dropped atomic.Uint64
+	spooled atomic.Uint64
+}
+
+func New(cfg Config) (*Client, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("client: URL is required")
+	}
+	if cfg.ApplicationID == uuid.Nil || cfg.UserID == uuid.Nil {
+		return nil, errors.New("client: ApplicationID and UserID are required")
+	}
+
+	defaults := DefaultConfig()
+	if cfg.BatchSize <= 0 || cfg.BatchSize > 1000 {
+		cfg.BatchSize = defaults.BatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaults.FlushInterval
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaults.BufferSize
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = defaults.MaxRetries
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaults.MinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = max(defaults.MaxBackoff, cfg.MinBackoff)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+	if cfg.SpoolMaxBytes <= 0 {
+		cfg.SpoolMaxBytes = defaults.SpoolMaxBytes
+	}
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+
+	c := &Client{
+		cfg:     cfg,
+		http:    cfg.HTTPClient,
+		entries: make(chan createLogInput, cfg.BufferSize),
+		flushes: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if c.http == nil {
+		c.http = &http.Client{Timeout: cfg.Timeout}
+	}
+	if cfg.SpoolDir != "" {
+		s, err := openSpool(cfg.SpoolDir, cfg.SpoolMaxBytes)
+		if err != nil {
+			return nil, err
+		}
+		c.spool = s
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+
+	go c.run()
+	return c, nil
+}
+
+// Log buffers an entry without blocking. When the buffer is full the entry is
+// spooled, or dropped with ErrBufferFull when no spool is configured.
+func (c *Client) Log(entry Entry) error {
+	input := createLogInput{
+		ID:            entry.ID,
+		ApplicationID: c.cfg.ApplicationID,
+		UserID:        c.cfg.UserID,
+		Message:       entry.Message,
+		Level:         entry.Level,
+		Source:        entry.Source,
+		Tags:          entry.Tags,
+		Metadata:      entry.Metadata,
+		Timestamp:     entry.Timestamp,
+	}
+	if input.ID == uuid.Nil {
+		input.ID = uuid.New()
+	}
+	if input.Level == "" {
+		input.Level = LevelInfo
+	}
+	if input.Source == "" {
+		input.Source = c.cfg.Source
+	}
+	if input.Timestamp.IsZero() {
+		input.Timestamp = time.Now()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return ErrClosed
+	}
+	select {
+	case c.entries <- input:
+		return nil
+	default:
+	}
+
+	if c.spool != nil && c.spool.write([]createLogInput{input}) == nil {
+		c.spooled.Add(1)
+		return nil
+	}
+	c.dropped.Add(1)
+	return ErrBufferFull
+}
+
+// Flush delivers every buffered log, including their retries
+func (c *Client) Flush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case c.flushes <- ack:
+	case <-c.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close delivers the buffered logs and stops the client. When the context
+// ends first, pending retries are abandoned and their logs spooled or dropped.
+func (c *Client) Close(ctx context.Context) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		<-c.done
+		return nil
+	}
+	c.closed = true
+	close(c.entries)
+	c.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		c.cancel()
+		<-c.done
+		return ctx.Err()
+	}
+}
+
+// Stats returns the number of logs sent, dropped and spooled so far
+func (c *Client) Stats() Stats {
+	return Stats{
+		Sent:    c.sent.Load(),
+		Dropped: c.dropped.Load(),
+		Spooled: c.spooled.Load(),
+	}
+}
+
+func (c *Client) run() {
+	defer close(c.done)
+	defer c.cancel()
+
+	ticker := time.NewTicker(c.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]createLogInput, 0, c.cfg.BatchSize)
+	add := func(input createLogInput) {
+		batch = append(batch, input)
+		if len(batch) >= c.cfg.BatchSize {
+			c.send(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case input, ok := <-c.entries:
+			if !ok {
+				c.send(batch)
+				return
+			}
+			add(input)
+		case <-ticker.C:
+			c.send(batch)
+			batch = batch[:0]
+			c.replay()
+		case ack := <-c.flushes:
+			for drained := false; !drained; {
+				select {
+				case input, ok := <-c.entries:
+					if ok {
+						add(input)
+					} else {
+						drained = true
+					}
+				default:
+					drained = true
+				}
+			}
+			c.send(batch)
+			batch = batch[:0]
+			close(ack)
+		}
+	}
+}
+
+// send delivers a batch, retrying the logs the service could not take yet
+func (c *Client) send(batch []createLogInput) {
+	pending := batch
+	backoff := c.cfg.MinBackoff
+	for attempt := 0; len(pending) > 0; attempt++ {
+		retry, retryAfter, err := c.post(pending)
+		if len(retry) == 0 {
+			return
+		}
+		if attempt >= c.cfg.MaxRetries || c.ctx.Err() != nil {
+			c.giveUp(retry, fmt.Errorf("client: %d logs undelivered after %d attempts: %w", len(retry), attempt+1, err))
+			return
+		}
+
+		select {
+		case <-c.ctx.Done():
+		case <-time.After(max(backoff, retryAfter)):
+		}
+		backoff = min(backoff*2, c.cfg.MaxBackoff)
+		pending = retry
+	}
+}
+
+// post makes one request and returns the logs worth retrying
+func (c *Client) post(inputs []createLogInput) ([]createLogInput, time.Duration, error) {
+	body, err := json.Marshal(inputs)
+	if err != nil {
+		c.reject(len(inputs), fmt.Errorf("%w: %v", ErrRejected, err))
+		return nil, 0, nil
+	}
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, c.cfg.URL+batchPath, bytes.NewReader(body))
+	if err != nil {
+		c.reject(len(inputs), fmt.Errorf("%w: %v", ErrRejected, err))
+		return nil, 0, nil
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return inputs, 0, err
+	}
+	defer resp.Body.Close()
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err := fmt.Errorf("server answered %s: %s", resp.Status, bytes.TrimSpace(message))
+		if retryable(resp.StatusCode) {
+			return inputs, retryAfter, err
+		}
+		c.reject(len(inputs), fmt.Errorf("%w: %v", ErrRejected, err))
+		return nil, 0, nil
+	}
+
+	var output batchOutput
+	if err := json.NewDecoder(resp.Body).Decode(&output); err != nil {
+		return inputs, retryAfter, fmt.Errorf("invalid batch response: %w", err)
+	}
+	c.sent.Add(uint64(output.Accepted))
+
+	var retry []createLogInput
+	var lastErr error
+	for _, e := range output.Errors {
+		if e.Index < 0 || e.Index >= len(inputs) {
+			continue
+		}
+		switch {
+		case e.Status == http.StatusConflict:
+			// Stored by an earlier attempt
+			c.sent.Add(1)
+		case retryable(e.Status):
+			retry = append(retry, inputs[e.Index])
+			lastErr = errors.New(e.Error)
+		default:
+			c.reject(1, fmt.Errorf("%w: %s", ErrRejected, e.Error))
+		}
+	}
+	return retry, retryAfter, lastErr
+}
+
+func (c *Client) giveUp(inputs []createLogInput, err error) {
+	if c.spool != nil && c.spool.write(inputs) == nil {
+		c.spooled.Add(uint64(len(inputs)))
+		return
+	}
+	c.reject(len(inputs), err)
+}
+
+func (c *Client) reject(n int, err error) {
+	c.dropped.Add(uint64(n))
+	if c.cfg.OnError != nil {
+		c.cfg.OnError(err)
+	}
+}
+
+// replay resends spooled logs, stopping at the first batch that still fails
+func (c *Client) replay() {
+	if c.spool == nil || c.ctx.Err() != nil {
+		return
+	}
+	c.spool.replay(c.cfg.BatchSize, func(inputs []createLogInput) bool {
+		retry, _, _ := c.post(inputs)
+		return len(retry) == 0
+	})
+}
+
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fake batch endpoint storing each log ID once, like the service
+type batchServer struct {
+	mu       sync.Mutex
+	stored   map[uuid.UUID]createLogInput
+	order    []string
+	requests int
+	down     bool
+	// throttle answers 429 for the listed messages on their first attempt
+	throttle map[string]bool
+}
+
+func newBatchServer(t *testing.T) (*batchServer, *httptest.Server) {
+	s := &batchServer{stored: make(map[uuid.UUID]createLogInput), throttle: make(map[string]bool)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests++
+		if r.URL.Path != batchPath {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if s.down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var inputs []createLogInput
+		if err := json.NewDecoder(r.Body).Decode(&inputs); err != nil {
+			t.Errorf("Invalid batch body: %v", err)
+			return
+		}
+		output := map[string]interface{}{}
+		var errs []map[string]interface{}
+		accepted := 0
+		for i, input := range inputs {
+			switch {
+			case s.throttle[input.Message]:
+				delete(s.throttle, input.Message)
+				errs = append(errs, map[string]interface{}{"index": i, "status": http.StatusTooManyRequests, "error": "Rate limit exceeded."})
+			case input.Level == "LOUD":
+				errs = append(errs, map[string]interface{}{"index": i, "status": http.StatusBadRequest, "error": "invalid level"})
+			case s.stored[input.ID].Message != "":
+				errs = append(errs, map[string]interface{}{"index": i, "status": http.StatusConflict, "error": "A log with this ID already exists."})
+			default:
+				s.stored[input.ID] = input
+				s.order = append(s.order, input.Message)
+				accepted++
+			}
+		}
+		output["accepted"] = accepted
+		output["errors"] = errs
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(output)
+	}))
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func (s *batchServer) messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.order...)
+}
+
+func newTestClient(t *testing.T, url string, modify func(*Config)) *Client {
+	cfg := Config{
+		URL:           url,
+		ApplicationID: uuid.New(),
+		UserID:        uuid.New(),
+		Source:        "billing",
+		FlushInterval: time.Hour,
+		MinBackoff:    time.Millisecond,
+		MaxBackoff:    time.Millisecond,
+	}
+	if modify != nil {
+		modify(&cfg)
+	}
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { c.Close(context.Background()) })
+	return c
+}
+
+func TestClient_BatchesLogs(t *testing.T) {
+	server, httpServer := newBatchServer(t)
+	c := newTestClient(t, httpServer.URL, func(cfg *Config) { cfg.BatchSize = 2 })
+
+	for _, message := range []string{"one", "two", "three"} {
+		if err := c.Log(Entry{Message: message, Tags: map[string]string{"env": "test"}}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := c.Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	messages := server.messages()
+	if len(messages) != 3 || messages[0] != "one" || messages[2] != "three" {
+		t.Fatalf("Expected all logs in order, got %v", messages)
+	}
+	if server.requests != 2 {
+		t.Errorf("Expected 2 batch requests, got %d", server.requests)
+	}
+	for _, input := range server.stored {
+		if input.Level != LevelInfo || input.Source != "billing" || input.Timestamp.IsZero() || input.Tags["env"] != "test" {
+			t.Errorf("Expected defaults to be filled in, got %+v", input)
+		}
+	}
+	if stats := c.Stats(); stats.Sent != 3 {
+		t.Errorf("Expected 3 sent logs, got %+v", stats)
+	}
+}
+
+func TestClient_RetriesThrottledEntries(t *testing.T) {
+	server, httpServer := newBatchServer(t)
+	server.throttle["second"] = true
+	var errs []error
+	c := newTestClient(t, httpServer.URL, func(cfg *Config) { cfg.OnError = func(err error) { errs = append(errs, err) } })
+
+	c.Log(Entry{Message: "first"})
+	c.Log(Entry{Message: "second"})
+	c.Log(Entry{Message: "invalid", Level: "LOUD"})
+	c.Flush(context.Background())
+
+	messages := server.messages()
+	if len(messages) != 2 || messages[0] != "first" || messages[1] != "second" {
+		t.Errorf("Expected throttled entry to be retried alone, got %v", messages)
+	}
+	if stats := c.Stats(); stats.Sent != 2 || stats.Dropped != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if len(errs) != 1 {
+		t.Errorf("Expected the rejected entry to be reported, got %v", errs)
+	}
+}
+
+func TestClient_SpoolsWhenServiceIsDown(t *testing.T) {
+	server, httpServer := newBatchServer(t)
+	server.down = true
+	c := newTestClient(t, httpServer.URL, func(cfg *Config) {
+		cfg.SpoolDir = t.TempDir()
+		cfg.MaxRetries = 1
+	})
+
+	c.Log(Entry{Message: "spooled"})
+	c.Flush(context.Background())
+
+	if stats := c.Stats(); stats.Spooled != 1 || stats.Sent != 0 {
+		t.Fatalf("Expected the log to be spooled, got %+v", stats)
+	}
+
+	server.mu.Lock()
+	server.down = false
+	server.mu.Unlock()
+	c.replay()
+
+	if messages := server.messages(); len(messages) != 1 || messages[0] != "spooled" {
+		t.Errorf("Expected the spooled log to be replayed, got %v", messages)
+	}
+	if c.spool.size != 0 {
+		t.Errorf("Expected the spool to be empty, got %d bytes", c.spool.size)
+	}
+}
+
+func TestClient_DropsWhenBufferIsFull(t *testing.T) {
+	_, httpServer := newBatchServer(t)
+	c := newTestClient(t, httpServer.URL, func(cfg *Config) {
+		cfg.BufferSize = 1
+		cfg.BatchSize = 1000
+	})
+
+	var dropped int
+	for i := 0; i < 100; i++ {
+		if err := c.Log(Entry{Message: "burst"}); err == ErrBufferFull {
+			dropped++
+		}
+	}
+
+	if dropped == 0 || c.Stats().Dropped != uint64(dropped) {
+		t.Errorf("Expected dropped logs to be counted, got %d and %+v", dropped, c.Stats())
+	}
+}
+
+func TestClient_Close(t *testing.T) {
+	server, httpServer := newBatchServer(t)
+	c := newTestClient(t, httpServer.URL, nil)
+
+	c.Log(Entry{Message: "before close"})
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if messages := server.messages(); len(messages) != 1 {
+		t.Errorf("Expected buffered logs to be delivered on close, got %v", messages)
+	}
+	if err := c.Log(Entry{Message: "after close"}); err != ErrClosed {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
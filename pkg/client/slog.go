@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// Levels beyond the range of log/slog, for use with Logger.Log
+const (
+	SlogLevelTrace = slog.Level(-8)
+	SlogLevelFatal = slog.Level(12)
+)
+
+// HandlerOptions configures the slog handler
+type HandlerOptions struct {
+	// Level is the minimum level sent; default slog.LevelInfo
+	Level slog.Leveler
+	// AddSource records the calling file and line in the "caller" metadata field
+	AddSource bool
+}
+
+// Handler is a slog.Handler sending records through a Client. Top-level
+// string attributes become tags, which the service can filter on; other
+// attributes and groups are stored in metadata.
+type Handler struct {
+	client *Client
+	opts   HandlerOptions
+	groups []string
+	attrs  []groupedAttr
+}
+
+// groupedAttr is an attribute added by WithAttrs under the groups open at the time
+type groupedAttr struct {
+	groups []string
+	attr   slog.Attr
+}
+
+func NewHandler(c *Client, opts *HandlerOptions) *Handler {
+	h := &Handler{client: c}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Level == nil {
+		h.opts.Level = slog.LevelInfo
+	}
+	return h
+}
+
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+	entry := Entry{
+		Message:   record.Message,
+		Level:     LevelFromSlog(record.Level),
+		Timestamp: record.Time,
+	}
+	add := func(groups []string, attr slog.Attr) {
+		addAttr(&entry, groups, attr)
+	}
+
+	for _, a := range h.attrs {
+		add(a.groups, a.attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		add(h.groups, attr)
+		return true
+	})
+	if h.opts.AddSource && record.PC != 0 {
+		// The caller changes with every record, so it is kept out of the tags
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		metadata(&entry)["caller"] = frame.File + ":" + strconv.Itoa(frame.Line)
+	}
+
+	return h.client.Log(entry)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := *h
+	clone.attrs = make([]groupedAttr, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(clone.attrs, h.attrs)
+	for _, attr := range attrs {
+		clone.attrs = append(clone.attrs, groupedAttr{groups: h.groups, attr: attr})
+	}
+	return &clone
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(append([]string(nil), h.groups...), name)
+	return &clone
+}
+
+// LevelFromSlog maps a slog level to the service's level names
+func LevelFromSlog(level slog.Level) string {
+	switch {
+	case level < slog.LevelDebug:
+		return LevelTrace
+	case level < slog.LevelInfo:
+		return LevelDebug
+	case level < slog.LevelWarn:
+		return LevelInfo
+	case level < slog.LevelError:
+		return LevelWarn
+	case level < SlogLevelFatal:
+		return LevelError
+	default:
+		return LevelFatal
+	}
+}
+
+func addAttr(entry *Entry, groups []string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		// Attributes of an inline group with an empty key belong to the enclosing group
+		if attr.Key != "" {
+			groups = append(append([]string(nil), groups...), attr.Key)
+		}
+		for _, member := range attr.Value.Group() {
+			addAttr(entry, groups, member)
+		}
+		return
+	}
+	if len(groups) == 0 && attr.Value.Kind() == slog.KindString {
+		if entry.Tags == nil {
+			entry.Tags = make(map[string]string)
+		}
+		entry.Tags[attr.Key] = attr.Value.String()
+		return
+	}
+
+	target := metadata(entry)
+	for _, group := range groups {
+		nested, ok := target[group].(map[string]interface{})
+		if !ok {
+			nested = make(map[string]interface{})
+			target[group] = nested
+		}
+		target = nested
+	}
+	target[attr.Key] = value(attr.Value)
+}
+
+func metadata(entry *Entry) map[string]interface{} {
+	if entry.Metadata == nil {
+		entry.Metadata = make(map[string]interface{})
+	}
+	return entry.Metadata
+}
+
+// value converts an attribute value to a JSON-encodable value
+func value(v slog.Value) interface{} {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	}
+
+	switch x := v.Any().(type) {
+	case error:
+		return x.Error()
+	default:
+		if _, err := json.Marshal(x); err != nil {
+			return fmt.Sprint(x)
+		}
+		return x
+	}
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestLevelFromSlog(t *testing.T) {
+	tests := []struct {
+		level    slog.Level
+		expected string
+	}{
+		{SlogLevelTrace, LevelTrace},
+		{slog.LevelDebug, LevelDebug},
+		{slog.LevelInfo, LevelInfo},
+		{slog.LevelInfo + 2, LevelInfo},
+		{slog.LevelWarn, LevelWarn},
+		{slog.LevelError, LevelError},
+		{SlogLevelFatal, LevelFatal},
+	}
+
+	for _, tt := range tests {
+		if got := LevelFromSlog(tt.level); got != tt.expected {
+			t.Errorf("Expected %s for %v, got %s", tt.expected, tt.level, got)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	server, httpServer := newBatchServer(t)
+	c := newTestClient(t, httpServer.URL, nil)
+	logger := slog.New(NewHandler(c, &HandlerOptions{Level: slog.LevelDebug, AddSource: true}))
+
+	logger.With("request_id", "req-1", "attempt", 2).
+		WithGroup("http").
+		Warn("slow request", "path", "/checkout", "duration", 1500*time.Millisecond, slog.Group("client", "ip", "10.0.0.7"))
+	logger.Error("payment failed", "err", errors.New("card declined"), slog.Group("empty"))
+	logger.Debug("debug enabled")
+	c.Flush(context.Background())
+
+	if len(server.stored) != 3 {
+		t.Fatalf("Expected 3 logs, got %d", len(server.stored))
+	}
+	byMessage := make(map[string]createLogInput)
+	for _, input := range server.stored {
+		byMessage[input.Message] = input
+	}
+
+	slow := byMessage["slow request"]
+	if slow.Level != LevelWarn || slow.Tags["request_id"] != "req-1" {
+		t.Errorf("Expected level and string attribute as tag, got %+v", slow)
+	}
+	if slow.Metadata["attempt"] != float64(2) || slow.Metadata["caller"] == nil {
+		t.Errorf("Expected other attributes and caller in metadata, got %v", slow.Metadata)
+	}
+	httpGroup, _ := slow.Metadata["http"].(map[string]interface{})
+	client, _ := httpGroup["client"].(map[string]interface{})
+	if httpGroup["path"] != "/checkout" || httpGroup["duration"] != "1.5s" || client["ip"] != "10.0.0.7" {
+		t.Errorf("Expected grouped attributes in nested metadata, got %v", slow.Metadata)
+	}
+
+	failed := byMessage["payment failed"]
+	if failed.Level != LevelError || failed.Metadata["err"] != "card declined" {
+		t.Errorf("Expected error attribute as its message, got %+v", failed)
+	}
+	if _, ok := failed.Metadata["empty"]; ok {
+		t.Errorf("Expected empty groups to be omitted, got %v", failed.Metadata)
+	}
+}
+
+func TestHandler_Enabled(t *testing.T) {
+	h := NewHandler(nil, nil)
+
+	if h.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Expected debug to be disabled by default")
+	}
+	if !h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("Expected info to be enabled")
+	}
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const segmentSuffix = ".jsonl"
+
+var errSpoolFull = errors.New("client: spool size limit reached")
+
+// spool keeps undelivered logs in numbered JSON lines files. Writes go to the
+// newest segment; replay seals it first so that it only reads complete files.
+type spool struct {
+	dir      string
+	maxBytes int64
+
+	mu   sync.Mutex
+	size int64
+	seq  uint64
+}
+
+func openSpool(dir string, maxBytes int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("client: failed to create spool directory: %w", err)
+	}
+
+	s := &spool{dir: dir, maxBytes: maxBytes}
+	segments, err := s.segments()
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range segments {
+		info, err := os.Stat(filepath.Join(dir, segment.name))
+		if err != nil {
+			return nil, fmt.Errorf("client: failed to stat spool segment: %w", err)
+		}
+		s.size += info.Size()
+		s.seq = max(s.seq, segment.seq+1)
+	}
+	return s, nil
+}
+
+func (s *spool) write(inputs []createLogInput) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, input := range inputs {
+		if err := encoder.Encode(input); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size+int64(buf.Len()) > s.maxBytes {
+		return errSpoolFull
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.dir, segmentName(s.seq)), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	s.size += int64(buf.Len())
+	return nil
+}
+
+// replay sends the sealed segments oldest first in batches, removing each
+// segment once all of it was delivered
+func (s *spool) replay(batchSize int, send func([]createLogInput) bool) {
+	s.mu.Lock()
+	if s.size == 0 {
+		s.mu.Unlock()
+		return
+	}
+	sealed := s.seq
+	s.seq++
+	s.mu.Unlock()
+
+	segments, err := s.segments()
+	if err != nil {
+		return
+	}
+	for _, segment := range segments {
+		if segment.seq > sealed {
+			return
+		}
+		path := filepath.Join(s.dir, segment.name)
+		inputs, size, err := readSegment(path)
+		if err != nil {
+			return
+		}
+		for start := 0; start < len(inputs); start += batchSize {
+			if !send(inputs[start:min(start+batchSize, len(inputs))]) {
+				return
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.size -= size
+		s.mu.Unlock()
+	}
+}
+
+type segment struct {
+	name string
+	seq  uint64
+}
+
+func (s *spool) segments() ([]segment, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("client: failed to read spool directory: %w", err)
+	}
+	var segments []segment
+	for _, entry := range entries {
+		seq, err := strconv.ParseUint(strings.TrimSuffix(entry.Name(), segmentSuffix), 10, 64)
+		if err != nil || !strings.HasSuffix(entry.Name(), segmentSuffix) {
+			continue
+		}
+		segments = append(segments, segment{name: entry.Name(), seq: seq})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].seq < segments[j].seq })
+	return segments, nil
+}
+
+func readSegment(path string) ([]createLogInput, int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var inputs []createLogInput
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		var input createLogInput
+		// A line cut short by a crash is skipped rather than blocking the segment
+		if err := json.Unmarshal(scanner.Bytes(), &input); err == nil {
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs, int64(len(data)), scanner.Err()
+}
+
+func segmentName(seq uint64) string {
+	return fmt.Sprintf("%020d%s", seq, segmentSuffix)
+}
@@ -0,0 +1,422 @@
+"use strict";
+
+// Dashboard for live tail over /api/v1/events/{applicationID} and search over
+// GET /api/v1/logs. The service does not list applications, so the IDs the
+// user adds are remembered in localStorage and the selected one in the URL.
+
+const LEVELS = ["TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"];
+const MAX_LIVE_LOGS = 2000;
+const UUID_PATTERN = /^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/i;
+
+const $ = (id) => document.getElementById(id);
+
+const state = {
+  apps: [],
+  app: "",
+  events: null,
+  paused: false,
+  live: [],
+  pending: [],
+  offset: 0,
+};
+
+// Table rows of rendered logs, so filtering can reuse them
+const rows = new WeakMap();
+
+// Applications
+
+function loadApps() {
+  try {
+    state.apps = JSON.parse(localStorage.getItem("apps")) || [];
+  } catch {
+    state.apps = [];
+  }
+  state.apps = state.apps.filter((app) => UUID_PATTERN.test(app));
+
+  const fromURL = new URLSearchParams(location.search).get("app");
+  if (fromURL && UUID_PATTERN.test(fromURL)) {
+    if (!state.apps.includes(fromURL)) state.apps.push(fromURL);
+    state.app = fromURL;
+  } else {
+    const saved = localStorage.getItem("app");
+    state.app = state.apps.includes(saved) ? saved : state.apps[0] || "";
+  }
+}
+
+function saveApps() {
+  localStorage.setItem("apps", JSON.stringify(state.apps));
+  localStorage.setItem("app", state.app);
+
+  const url = new URL(location.href);
+  if (state.app) {
+    url.searchParams.set("app", state.app);
+  } else {
+    url.searchParams.delete("app");
+  }
+  history.replaceState(null, "", url);
+}
+
+function renderApps() {
+  const select = $("app-select");
+  select.replaceChildren();
+  if (state.apps.length === 0) {
+    select.append(new Option("Add an application UUID to start", ""));
+  }
+  for (const app of state.apps) {
+    select.append(new Option(app, app, false, app === state.app));
+  }
+  $("app-remove").disabled = !state.app;
+}
+
+function selectApp(app) {
+  state.app = app;
+  saveApps();
+  renderApps();
+  connect();
+  resetSearch();
+}
+
+function setupApps() {
+  $("app-form").addEventListener("submit", (event) => {
+    event.preventDefault();
+    const input = $("app-input");
+    const app = input.value.trim().toLowerCase();
+    if (!UUID_PATTERN.test(app)) {
+      input.setCustomValidity("Enter an application UUID");
+      input.reportValidity();
+      return;
+    }
+    input.setCustomValidity("");
+    input.value = "";
+    if (!state.apps.includes(app)) state.apps.push(app);
+    selectApp(app);
+  });
+  $("app-input").addEventListener("input", (event) => event.target.setCustomValidity(""));
+  $("app-select").addEventListener("change", (event) => selectApp(event.target.value));
+  $("app-remove").addEventListener("click", () => {
+    state.apps = state.apps.filter((app) => app !== state.app);
+    selectApp(state.apps[0] || "");
+  });
+}
+
+// Filters
+
+function addLevelCheckboxes(form) {
+  const fieldset = form.querySelector(".levels");
+  for (const level of LEVELS) {
+    const label = document.createElement("label");
+    const checkbox = document.createElement("input");
+    checkbox.type = "checkbox";
+    checkbox.name = "level";
+    checkbox.value = level;
+    checkbox.checked = true;
+    label.append(checkbox, level);
+    fieldset.append(label);
+  }
+}
+
+// parseTags reads "key:value" pairs separated by commas
+function parseTags(raw) {
+  const tags = [];
+  for (const part of (raw || "").split(",")) {
+    const index = part.indexOf(":");
+    if (index > 0) tags.push([part.slice(0, index).trim(), part.slice(index + 1).trim()]);
+  }
+  return tags;
+}
+
+function readFilters(form) {
+  const data = new FormData(form);
+  const levels = data.getAll("level");
+  return {
+    // No level or every level checked means all levels
+    levels: levels.length === 0 || levels.length === LEVELS.length ? [] : levels,
+    source: (data.get("source") || "").trim(),
+    tags: parseTags(data.get("tags")),
+    text: (data.get("q") || "").trim().toLowerCase(),
+  };
+}
+
+function matches(log, filters) {
+  if (filters.levels.length > 0 && !filters.levels.includes(log.level)) return false;
+  if (filters.source && log.source !== filters.source) return false;
+  for (const [key, value] of filters.tags) {
+    if (!log.tags || log.tags[key] !== value) return false;
+  }
+  if (filters.text && !(log.message || "").toLowerCase().includes(filters.text)) return false;
+  return true;
+}
+
+// Rendering
+
+function formatTime(timestamp) {
+  const date = new Date(timestamp);
+  if (Number.isNaN(date.getTime())) return timestamp || "";
+  const ms = String(date.getMilliseconds()).padStart(3, "0");
+  return `${date.toLocaleDateString()} ${date.toLocaleTimeString([], { hour12: false })}.${ms}`;
+}
+
+function cell(text, className) {
+  const td = document.createElement("td");
+  if (className) td.className = className;
+  td.textContent = text;
+  return td;
+}
+
+function rowFor(log) {
+  let tr = rows.get(log);
+  if (tr) return tr;
+
+  tr = document.createElement("tr");
+  tr.className = `level-${(log.level || "").toLowerCase()}`;
+  const message = cell((log.message || "").split("\n")[0], "message");
+  message.title = log.message || "";
+  const tags = cell("");
+  for (const [key, value] of Object.entries(log.tags || {}).sort()) {
+    const span = document.createElement("span");
+    span.className = "tag";
+    span.textContent = `${key}=${value}`;
+    tags.append(span);
+  }
+  tr.append(cell(formatTime(log.timestamp)), cell(log.level, "level"), cell(log.source || ""), message, tags);
+  tr.addEventListener("click", () => showDetail(log));
+  rows.set(log, tr);
+  return tr;
+}
+
+function showDetail(log) {
+  const fields = $("detail-fields");
+  fields.replaceChildren();
+  for (const [name, value] of [
+    ["ID", log.id],
+    ["Application", log.application_id],
+    ["User", log.user_id],
+    ["Level", log.level],
+    ["Source", log.source || "-"],
+    ["Timestamp", log.timestamp],
+    ["Ingested at", log.ingested_at],
+  ]) {
+    const dt = document.createElement("dt");
+    dt.textContent = name;
+    const dd = document.createElement("dd");
+    dd.textContent = value || "";
+    fields.append(dt, dd);
+  }
+  $("detail-message").textContent = log.message || "";
+  $("detail-tags").textContent = JSON.stringify(log.tags || {}, null, 2);
+  $("detail-metadata").textContent = JSON.stringify(log.metadata || {}, null, 2);
+  $("detail-copy").onclick = () => navigator.clipboard?.writeText(JSON.stringify(log, null, 2));
+  $("detail").showModal();
+}
+
+// Live tail
+
+function setStatus(text, className) {
+  const status = $("live-status");
+  status.textContent = text;
+  status.className = `status ${className}`;
+}
+
+function connect() {
+  if (state.events) state.events.close();
+  state.events = null;
+  state.live = [];
+  state.pending = [];
+  renderLive();
+
+  if (!state.app) {
+    setStatus("no application", "off");
+    return;
+  }
+  const events = new EventSource(`/api/v1/events/${encodeURIComponent(state.app)}`);
+  state.events = events;
+  setStatus("connecting", "off");
+  events.onopen = () => setStatus(state.paused ? "paused" : "live", state.paused ? "paused" : "on");
+  // EventSource reconnects by itself unless the server refused the stream
+  events.onerror = () =>
+    setStatus(events.readyState === EventSource.CLOSED ? "disconnected" : "reconnecting", "off");
+  // Sent before the server restarts; the stream then ends and EventSource reconnects
+  events.addEventListener("shutdown", () => setStatus("reconnecting", "off"));
+  events.onmessage = (event) => {
+    let log;
+    try {
+      log = JSON.parse(event.data);
+    } catch {
+      return;
+    }
+    if (state.paused) {
+      state.pending.push(log);
+      if (state.pending.length > MAX_LIVE_LOGS) state.pending.shift();
+      updateCount();
+      return;
+    }
+    addLive(log);
+  };
+}
+
+function addLive(log) {
+  state.live.push(log);
+  if (state.live.length > MAX_LIVE_LOGS) {
+    const oldest = state.live.shift();
+    rows.get(oldest)?.remove();
+  }
+  if (matches(log, readFilters($("live-filters")))) {
+    $("live-rows").prepend(rowFor(log));
+  }
+  updateCount();
+}
+
+function renderLive() {
+  const filters = readFilters($("live-filters"));
+  const visible = state.live.filter((log) => matches(log, filters)).reverse();
+  $("live-rows").replaceChildren(...visible.map(rowFor));
+  updateCount();
+}
+
+function updateCount() {
+  const shown = $("live-rows").childElementCount;
+  let text = `${shown} shown of ${state.live.length}`;
+  if (state.pending.length > 0) text += `, ${state.pending.length} waiting`;
+  $("live-count").textContent = text;
+  $("live-empty").hidden = shown > 0;
+}
+
+function setupLive() {
+  const form = $("live-filters");
+  addLevelCheckboxes(form);
+  form.addEventListener("input", renderLive);
+  form.addEventListener("submit", (event) => event.preventDefault());
+
+  $("live-pause").addEventListener("click", () => {
+    state.paused = !state.paused;
+    $("live-pause").textContent = state.paused ? "Resume" : "Pause";
+    if (!state.paused) {
+      const pending = state.pending;
+      state.pending = [];
+      pending.forEach(addLive);
+    }
+    if (state.events?.readyState === EventSource.OPEN) {
+      setStatus(state.paused ? "paused" : "live", state.paused ? "paused" : "on");
+    }
+    updateCount();
+  });
+  $("live-clear").addEventListener("click", () => {
+    state.live = [];
+    state.pending = [];
+    renderLive();
+  });
+}
+
+// Search
+
+function resetSearch() {
+  state.offset = 0;
+  $("search-rows").replaceChildren();
+  $("search-error").hidden = true;
+  $("search-empty").textContent = "Choose filters and press Search.";
+  $("search-empty").hidden = false;
+  $("search-page").textContent = "";
+  $("search-prev").disabled = true;
+  $("search-next").disabled = true;
+}
+
+function searchParams(offset) {
+  const form = $("search-form");
+  const data = new FormData(form);
+  const filters = readFilters(form);
+  const params = new URLSearchParams({ application_id: state.app });
+
+  for (const name of ["from", "to"]) {
+    // datetime-local values are in the browser's time zone
+    const value = data.get(name);
+    if (value) params.set(name, new Date(value).toISOString());
+  }
+  if (filters.levels.length > 0) params.set("level", filters.levels.join(","));
+  if (filters.source) params.set("source", filters.source);
+  for (const [key, value] of filters.tags) params.append("tag", `${key}:${value}`);
+  for (const name of ["min_level", "q", "time_field", "order", "limit"]) {
+    const value = (data.get(name) || "").trim();
+    if (value) params.set(name, value);
+  }
+  params.set("offset", String(offset));
+  return params;
+}
+
+async function search(offset) {
+  if (!state.app) {
+    showSearchError("Add an application first.");
+    return;
+  }
+
+  const params = searchParams(offset);
+  let output;
+  try {
+    const response = await fetch(`/api/v1/logs?${params}`, { headers: { Accept: "application/json" } });
+    output = await response.json().catch(() => ({}));
+    if (!response.ok) {
+      showSearchError(output.error || `The search failed with status ${response.status}.`);
+      return;
+    }
+  } catch (error) {
+    showSearchError(`The service could not be reached: ${error.message}`);
+    return;
+  }
+
+  state.offset = offset;
+  const logs = output.logs || [];
+  $("search-error").hidden = true;
+  $("search-rows").replaceChildren(...logs.map(rowFor));
+  $("search-empty").textContent = "No logs match these filters.";
+  $("search-empty").hidden = logs.length > 0;
+  $("search-page").textContent = logs.length > 0 ? `${offset + 1}–${offset + logs.length}` : "";
+  $("search-prev").disabled = offset === 0;
+  $("search-next").disabled = !output.has_more;
+}
+
+function showSearchError(message) {
+  const error = $("search-error");
+  error.textContent = message;
+  error.hidden = false;
+}
+
+function setupSearch() {
+  const form = $("search-form");
+  addLevelCheckboxes(form);
+  form.addEventListener("submit", (event) => {
+    event.preventDefault();
+    search(0);
+  });
+  const pageSize = () => Number(new FormData(form).get("limit")) || 100;
+  $("search-prev").addEventListener("click", () => search(Math.max(state.offset - pageSize(), 0)));
+  $("search-next").addEventListener("click", () => search(state.offset + pageSize()));
+}
+
+// Tabs and dialog
+
+function setupTabs() {
+  for (const tab of document.querySelectorAll(".tab")) {
+    tab.addEventListener("click", () => {
+      for (const other of document.querySelectorAll(".tab")) other.classList.toggle("active", other === tab);
+      for (const panel of document.querySelectorAll(".panel")) {
+        panel.classList.toggle("active", panel.id === tab.dataset.tab);
+      }
+    });
+  }
+  $("detail-close").addEventListener("click", () => $("detail").close());
+  $("detail").addEventListener("click", (event) => {
+    // A click on the backdrop closes the dialog
+    const box = $("detail").getBoundingClientRect();
+    const inside = event.clientX >= box.left && event.clientX <= box.right && event.clientY >= box.top && event.clientY <= box.bottom;
+    if (!inside) $("detail").close();
+  });
+}
+
+loadApps();
+setupApps();
+setupLive();
+setupSearch();
+setupTabs();
+renderApps();
+saveApps();
+connect();
+resetSearch();
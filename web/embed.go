@@ -0,0 +1,20 @@
+// Package web holds the static files of the dashboard, embedded in the binary
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed dashboard
+var files embed.FS
+
+// Dashboard returns the dashboard files, rooted at index.html
+func Dashboard() fs.FS {
+	dashboard, err := fs.Sub(files, "dashboard")
+	if err != nil {
+		// The directory is embedded at build time, so this cannot happen
+		panic(err)
+	}
+	return dashboard
+}